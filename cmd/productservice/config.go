@@ -20,6 +20,12 @@ type Service struct {
 	HTTPAddress string `envconfig:"http_address" default:":8082"`
 }
 
+// MediaStorage локальное хранилище загруженных медиа, файлы раздаются HTTP-сервером по пути /media/
+type MediaStorage struct {
+	Path    string `envconfig:"path" default:"media"`
+	BaseURL string `envconfig:"base_url" default:"/media"`
+}
+
 type Database struct {
 	Product               string        `envconfig:"user" required:"true"`
 	Password              string        `envconfig:"password" required:"true"`
@@ -36,3 +42,39 @@ type AMQP struct {
 	Host           string        `envconfig:"host" required:"true"`
 	ConnectTimeout time.Duration `envconfig:"connect_timeout"`
 }
+
+type Purge struct {
+	Interval  time.Duration `envconfig:"interval" default:"1h"`
+	Retention time.Duration `envconfig:"retention" default:"720h"`
+}
+
+type PriceSchedule struct {
+	Interval time.Duration `envconfig:"interval" default:"1m"`
+}
+
+type ReservationExpiry struct {
+	Interval time.Duration `envconfig:"interval" default:"1m"`
+}
+
+// OrderEvents очередь событий сервиса заказов, по которым резервируются остатки
+type OrderEvents struct {
+	Exchange        string        `envconfig:"exchange" default:"domain_event_exchange"`
+	Queue           string        `envconfig:"queue" default:"productservice_order_events"`
+	PrefetchCount   int           `envconfig:"prefetch_count" default:"10"`
+	RedeliveryDelay time.Duration `envconfig:"redelivery_delay" default:"5s"`
+	ReservationTTL  time.Duration `envconfig:"reservation_ttl" default:"24h"`
+}
+
+// InboxRetention срок хранения отметок об обработанных входящих сообщениях
+type InboxRetention struct {
+	Interval  time.Duration `envconfig:"interval" default:"1h"`
+	Retention time.Duration `envconfig:"retention" default:"168h"`
+}
+
+// OutboxRetry попытки доставки события outbox. В dead letter событие переносится только после MaxAttempts
+// отказов, которые не исчезнут при повторе, при недоступности брокера оно повторяется с паузой до MaxBackoff
+type OutboxRetry struct {
+	MaxAttempts    int           `envconfig:"max_attempts" default:"8"`
+	InitialBackoff time.Duration `envconfig:"initial_backoff" default:"10s"`
+	MaxBackoff     time.Duration `envconfig:"max_backoff" default:"10m"`
+}
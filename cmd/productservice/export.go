@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	"github.com/urfave/cli/v2"
+
+	appquery "productservice/pkg/product/application/query"
+	"productservice/pkg/product/infrastructure/exporter"
+	"productservice/pkg/product/infrastructure/mysql/query"
+)
+
+type exportConfig struct {
+	Database Database `envconfig:"database" required:"true"`
+}
+
+// exportWatermark водяной знак снимка, события outbox после OutboxEventID продолжают снимок
+type exportWatermark struct {
+	MaxUpdatedAt  *time.Time `json:"max_updated_at,omitempty"`
+	OutboxEventID uint64     `json:"outbox_event_id"`
+	Products      int        `json:"products"`
+}
+
+func exportProducts(logger logging.Logger) *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Export a consistent snapshot of all products as JSON Lines or CSV",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "format", Usage: "jsonl or csv", Value: "jsonl"},
+			&cli.BoolFlag{Name: "gzip", Usage: "compress the output with gzip"},
+			&cli.StringFlag{Name: "output", Usage: "output file, - for stdout", Value: "-"},
+			&cli.StringFlag{Name: "watermark", Usage: "file to write the snapshot watermark to as JSON"},
+			&cli.IntFlag{Name: "page-size", Usage: "products read per query", Value: appquery.MaxListLimit},
+		},
+		Action: func(c *cli.Context) (err error) {
+			format, err := exporter.ParseFormat(c.String("format"))
+			if err != nil {
+				return err
+			}
+			cnf, err := parseEnvs[exportConfig]()
+			if err != nil {
+				return err
+			}
+
+			databaseConnector, err := newDatabaseConnector(cnf.Database)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				err = errors.Join(err, databaseConnector.Close())
+			}()
+
+			output, commit, err := createExportOutput(c.String("output"))
+			if err != nil {
+				return err
+			}
+
+			var watermark appquery.SnapshotWatermark
+			count, err := exporter.Export(
+				c.Context,
+				query.NewProductSnapshotService(databaseConnector.TransactionalClient()),
+				output,
+				exporter.Options{
+					Format:   format,
+					Gzip:     c.Bool("gzip"),
+					PageSize: c.Int("page-size"),
+				},
+				func(snapshotWatermark appquery.SnapshotWatermark) error {
+					watermark = snapshotWatermark
+					return nil
+				},
+			)
+			err = commit(err)
+			if err != nil {
+				return err
+			}
+
+			report := exportWatermark{
+				OutboxEventID: watermark.OutboxEventID,
+				Products:      count,
+			}
+			if !watermark.MaxUpdatedAt.IsZero() {
+				maxUpdatedAt := watermark.MaxUpdatedAt.UTC()
+				report.MaxUpdatedAt = &maxUpdatedAt
+			}
+			if path := c.String("watermark"); path != "" {
+				data, err := json.Marshal(report)
+				if err != nil {
+					return err
+				}
+				err = os.WriteFile(path, append(data, '\n'), 0o644)
+				if err != nil {
+					return err
+				}
+			}
+			logger.WithFields(logging.Fields{
+				"products":        count,
+				"max_updated_at":  report.MaxUpdatedAt,
+				"outbox_event_id": report.OutboxEventID,
+			}).Info("export finished")
+			return nil
+		},
+	}
+}
+
+// createExportOutput открывает вывод экспорта. Файл пишется во временный рядом и переименовывается
+// только после успешного экспорта, чтобы потребители не прочитали неполный снимок.
+// commit принимает ошибку экспорта и возвращает ее вместе с ошибками закрытия
+func createExportOutput(path string) (io.Writer, func(error) error, error) {
+	if path == "-" {
+		return os.Stdout, func(err error) error { return err }, nil
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, nil, err
+	}
+	// CreateTemp создает файл, доступный только владельцу, а снимок читают другие процессы
+	err = file.Chmod(0o644)
+	if err != nil {
+		return nil, nil, errors.Join(err, file.Close(), os.Remove(file.Name()))
+	}
+	return file, func(err error) error {
+		err = errors.Join(err, file.Close())
+		if err == nil {
+			err = os.Rename(file.Name(), path)
+		}
+		if err != nil {
+			return errors.Join(err, os.Remove(file.Name()))
+		}
+		return nil
+	}, nil
+}
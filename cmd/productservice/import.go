@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+
+	appmodel "productservice/pkg/product/application/model"
+	appservice "productservice/pkg/product/application/service"
+	"productservice/pkg/product/infrastructure/importer"
+	"productservice/pkg/product/infrastructure/integrationevent"
+	inframysql "productservice/pkg/product/infrastructure/mysql"
+)
+
+type importConfig struct {
+	Database Database `envconfig:"database" required:"true"`
+}
+
+var importKeys = map[string]appmodel.ImportKey{
+	"name": appmodel.ImportByName,
+	"sku":  appmodel.ImportBySKU,
+}
+
+var importActions = map[appmodel.ImportAction]string{
+	appmodel.ImportCreated:   "created",
+	appmodel.ImportUpdated:   "updated",
+	appmodel.ImportUnchanged: "unchanged",
+}
+
+// importRowReport строка отчета импорта, печатается в stdout в формате JSON Lines
+type importRowReport struct {
+	Row       int    `json:"row"`
+	ProductID string `json:"product_id,omitempty"`
+	Action    string `json:"action,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func importProducts(logger logging.Logger) *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import products from a CSV or JSON file, one transaction per row",
+		ArgsUsage: "<file, - for stdin>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "format", Usage: "csv or json, detected from the file extension by default"},
+			&cli.StringFlag{Name: "key", Usage: "field matching rows with existing products: name or sku", Value: "name"},
+			&cli.BoolFlag{Name: "dry-run", Usage: "validate every row and roll back all changes"},
+		},
+		Before: migrateImpl(logger),
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return errors.New("import expects exactly one file argument")
+			}
+			key, ok := importKeys[c.String("key")]
+			if !ok {
+				return fmt.Errorf("unknown import key %q, expected name or sku", c.String("key"))
+			}
+			cnf, err := parseEnvs[importConfig]()
+			if err != nil {
+				return err
+			}
+
+			closer := libio.NewMultiCloser()
+			defer func() {
+				err = errors.Join(err, closer.Close())
+			}()
+
+			fileName := c.Args().First()
+			var input io.Reader = os.Stdin
+			if fileName != "-" {
+				file, err := os.Open(fileName)
+				if err != nil {
+					return err
+				}
+				closer.AddCloser(file)
+				input = file
+			}
+			reader, err := newImportReader(c.String("format"), fileName, input)
+			if err != nil {
+				return err
+			}
+
+			databaseConnector, err := newDatabaseConnector(cnf.Database)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(databaseConnector)
+			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
+
+			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
+			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+			productService := appservice.NewProductService(
+				inframysql.NewUnitOfWork(libUoW),
+				inframysql.NewLockableUnitOfWork(libLUow),
+				eventDispatcher,
+			)
+
+			encoder := json.NewEncoder(os.Stdout)
+			summary, err := importer.Import(c.Context, reader, productService, importer.Options{
+				Key:    key,
+				DryRun: c.Bool("dry-run"),
+			}, func(result importer.RowResult) {
+				report := importRowReport{Row: result.Row}
+				if result.Err != nil {
+					report.Error = result.Err.Error()
+				} else {
+					report.Action = importActions[result.Action]
+					if result.ProductID != uuid.Nil {
+						report.ProductID = result.ProductID.String()
+					}
+				}
+				encodeErr := encoder.Encode(report)
+				if encodeErr != nil {
+					logger.Error(encodeErr, "failed to write import report")
+				}
+			})
+			logger.WithFields(logging.Fields{
+				"created":   summary.Created,
+				"updated":   summary.Updated,
+				"unchanged": summary.Unchanged,
+				"failed":    summary.Failed,
+				"dry_run":   c.Bool("dry-run"),
+			}).Info("import finished")
+			if err != nil {
+				return err
+			}
+			if summary.Failed > 0 {
+				return fmt.Errorf("%d rows failed to import", summary.Failed)
+			}
+			return nil
+		},
+	}
+}
+
+func newImportReader(format, fileName string, input io.Reader) (importer.RowReader, error) {
+	if format == "" {
+		format = filepath.Ext(fileName)
+		if len(format) > 0 {
+			format = format[1:]
+		}
+	}
+	switch format {
+	case "csv":
+		return importer.NewCSVReader(input), nil
+	case "json", "jsonl":
+		return importer.NewJSONReader(input), nil
+	default:
+		return nil, fmt.Errorf("unknown import format %q, expected csv or json", format)
+	}
+}
@@ -24,6 +24,9 @@ func main() {
 		Name: appID,
 		Commands: cli.Commands{
 			migrate(logger),
+			importProducts(logger),
+			exportProducts(logger),
+			outboxCommand(logger),
 			messageHandler(logger),
 			service(logger),
 		},
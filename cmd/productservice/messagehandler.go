@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"expvar"
 	"net/http"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
@@ -13,15 +15,35 @@ import (
 	"github.com/urfave/cli/v2"
 	"golang.org/x/sync/errgroup"
 
+	"productservice/pkg/common/infrastructure/consumer"
+	"productservice/pkg/common/infrastructure/deadletter"
+	"productservice/pkg/common/infrastructure/inbox"
+	appservice "productservice/pkg/product/application/service"
 	"productservice/pkg/product/infrastructure/integrationevent"
+	inframysql "productservice/pkg/product/infrastructure/mysql"
+	"productservice/pkg/product/infrastructure/storage"
+	stockappservice "productservice/pkg/stock/application/service"
+	stockintegrationevent "productservice/pkg/stock/infrastructure/integrationevent"
+	stockmysql "productservice/pkg/stock/infrastructure/mysql"
+	"productservice/pkg/stock/infrastructure/orderevent"
 )
 
 type messageHandlerConfig struct {
-	Service  Service  `envconfig:"service"`
-	Database Database `envconfig:"database" required:"true"`
-	AMQP     AMQP     `envconfig:"amqp" required:"true"`
+	Service           Service           `envconfig:"service"`
+	Database          Database          `envconfig:"database" required:"true"`
+	AMQP              AMQP              `envconfig:"amqp" required:"true"`
+	MediaStorage      MediaStorage      `envconfig:"media_storage"`
+	Purge             Purge             `envconfig:"purge"`
+	PriceSchedule     PriceSchedule     `envconfig:"price_schedule"`
+	ReservationExpiry ReservationExpiry `envconfig:"reservation_expiry"`
+	OrderEvents       OrderEvents       `envconfig:"order_events"`
+	InboxRetention    InboxRetention    `envconfig:"inbox_retention"`
+	OutboxRetry       OutboxRetry       `envconfig:"outbox_retry"`
 }
 
+// metricsPath путь HTTP-сервера, по которому expvar отдает метрики, в том числе метрики inbox
+const metricsPath = "/debug/vars"
+
 func messageHandler(logger logging.Logger) *cli.Command {
 	return &cli.Command{
 		Name:   "message-handler",
@@ -44,6 +66,27 @@ func messageHandler(logger logging.Logger) *cli.Command {
 			closer.AddCloser(databaseConnector)
 			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
 
+			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
+			uow := inframysql.NewUnitOfWork(libUoW)
+			luow := inframysql.NewLockableUnitOfWork(libLUow)
+			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+			productService := appservice.NewProductService(uow, luow, eventDispatcher)
+			// Очистка удаленных продуктов удаляет и файлы их медиа, поэтому хранилище должно совпадать с хранилищем service
+			mediaStorage, err := storage.NewLocalMediaStorage(cnf.MediaStorage.Path, cnf.MediaStorage.BaseURL)
+			if err != nil {
+				return err
+			}
+			purgeService := appservice.NewPurgeService(uow, mediaStorage)
+
+			stockLibUoW := mysql.NewUnitOfWork(databaseConnectionPool, stockmysql.NewRepositoryProvider)
+			stockLibLUow := mysql.NewLockableUnitOfWork(stockLibUoW, mysql.NewLocker(databaseConnectionPool))
+			stockEventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, stockintegrationevent.NewEventSerializer(), stockLibUoW)
+			stockUoW := stockmysql.NewUnitOfWork(stockLibUoW)
+			stockLUoW := stockmysql.NewLockableUnitOfWork(stockLibLUow)
+			stockService := stockappservice.NewStockService(stockUoW, stockLUoW, stockEventDispatcher)
+			orderService := stockappservice.NewOrderService(stockLUoW, stockEventDispatcher, cnf.OrderEvents.ReservationTTL)
+
 			amqpConnection := newAMQPConnection(cnf.AMQP, logger)
 			amqpEventProducer := amqpConnection.Producer(
 				&amqp.ExchangeConfig{
@@ -54,6 +97,20 @@ func messageHandler(logger logging.Logger) *cli.Command {
 				nil,
 				nil,
 			)
+			amqpConnection.AddChannel(consumer.NewConsumer(
+				c.Context,
+				consumer.Config{
+					ExchangeName:    cnf.OrderEvents.Exchange,
+					ExchangeKind:    integrationevent.ExchangeKind,
+					QueueName:       cnf.OrderEvents.Queue,
+					RoutingKeys:     []string{orderevent.RoutingKeyOrderCreated, orderevent.RoutingKeyOrderCancelled},
+					PrefetchCount:   cnf.OrderEvents.PrefetchCount,
+					RedeliveryDelay: cnf.OrderEvents.RedeliveryDelay,
+				},
+				// Обработчик и inbox используют один пул транзакций, чтобы резерв и отметка о сообщении фиксировались вместе
+				inbox.NewHandler(stockLibUoW, orderevent.NewHandler(orderService, logger)),
+				logger,
+			))
 			err = amqpConnection.Start()
 			if err != nil {
 				return err
@@ -63,8 +120,17 @@ func messageHandler(logger logging.Logger) *cli.Command {
 			}))
 
 			outboxEventHandler := outbox.NewEventHandler(outbox.EventHandlerConfig{
-				TransportName:  integrationevent.TransportName,
-				Transport:      integrationevent.NewTransport(logger, amqpEventProducer),
+				TransportName: integrationevent.TransportName,
+				Transport: deadletter.NewTransport(
+					integrationevent.NewTransport(logger, amqpEventProducer, stockintegrationevent.RoutingKey),
+					deadletter.NewRepository(integrationevent.TransportName, libUoW),
+					deadletter.RetryPolicy{
+						MaxAttempts:    cnf.OutboxRetry.MaxAttempts,
+						InitialBackoff: cnf.OutboxRetry.InitialBackoff,
+						MaxBackoff:     cnf.OutboxRetry.MaxBackoff,
+					},
+					logger,
+				),
 				ConnectionPool: databaseConnectionPool,
 				Logger:         logger,
 			})
@@ -74,9 +140,56 @@ func messageHandler(logger logging.Logger) *cli.Command {
 				return outboxEventHandler.Start(c.Context)
 			})
 
+			errGroup.Go(func() error {
+				l := logger.WithField("job", "purge_deleted_products")
+				return runPeriodically(c.Context, l, cnf.Purge.Interval, func(ctx context.Context) error {
+					count, err := purgeService.PurgeDeletedProducts(ctx, cnf.Purge.Retention)
+					if err != nil {
+						return err
+					}
+					l.WithField("count", count).Info("deleted products purged")
+					return nil
+				})
+			})
+
+			errGroup.Go(func() error {
+				l := logger.WithField("job", "apply_scheduled_price_changes")
+				return runPeriodically(c.Context, l, cnf.PriceSchedule.Interval, func(ctx context.Context) error {
+					count, err := productService.ApplyScheduledPriceChanges(ctx)
+					if count > 0 {
+						l.WithField("count", count).Info("scheduled price changes applied")
+					}
+					return err
+				})
+			})
+
+			errGroup.Go(func() error {
+				l := logger.WithField("job", "expire_stock_reservations")
+				return runPeriodically(c.Context, l, cnf.ReservationExpiry.Interval, func(ctx context.Context) error {
+					count, err := stockService.ExpireReservations(ctx)
+					if count > 0 {
+						l.WithField("count", count).Info("stock reservations expired")
+					}
+					return err
+				})
+			})
+
+			errGroup.Go(func() error {
+				l := logger.WithField("job", "sweep_inbox")
+				inboxSweeper := inbox.NewSweeper(databaseConnector.TransactionalClient())
+				return runPeriodically(c.Context, l, cnf.InboxRetention.Interval, func(ctx context.Context) error {
+					count, err := inboxSweeper.Sweep(ctx, cnf.InboxRetention.Retention)
+					if count > 0 {
+						l.WithField("count", count).Info("inbox messages swept")
+					}
+					return err
+				})
+			})
+
 			errGroup.Go(func() error {
 				router := mux.NewRouter()
 				registerHealthcheck(router)
+				router.Handle(metricsPath, expvar.Handler())
 				// nolint:gosec
 				server := http.Server{
 					Addr:    cnf.Service.HTTPAddress,
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/urfave/cli/v2"
+
+	"productservice/pkg/common/infrastructure/deadletter"
+	"productservice/pkg/product/infrastructure/integrationevent"
+	inframysql "productservice/pkg/product/infrastructure/mysql"
+)
+
+type outboxConfig struct {
+	Database Database `envconfig:"database" required:"true"`
+}
+
+// deadLetterReport событие dead letter, печатается в stdout в формате JSON Lines
+type deadLetterReport struct {
+	ID            uint64 `json:"id"`
+	CorrelationID string `json:"correlation_id"`
+	EventType     string `json:"event_type"`
+	Payload       string `json:"payload"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"last_error"`
+	FailedAt      string `json:"failed_at"`
+}
+
+func outboxCommand(logger logging.Logger) *cli.Command {
+	return &cli.Command{
+		Name:   "outbox",
+		Usage:  "Inspect and replay outbox events that were moved to dead letter",
+		Before: migrateImpl(logger),
+		Subcommands: cli.Commands{
+			&cli.Command{
+				Name:  "list-failed",
+				Usage: "Print dead letter events as JSON Lines, oldest first",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "limit", Usage: "maximum number of events to print", Value: 100},
+				},
+				Action: func(c *cli.Context) error {
+					return withDeadLetterRepository(func(repository deadletter.Repository) error {
+						deadLetters, err := repository.List(c.Context, c.Int("limit"))
+						if err != nil {
+							return err
+						}
+						encoder := json.NewEncoder(os.Stdout)
+						for _, deadLetter := range deadLetters {
+							err = encoder.Encode(deadLetterReport{
+								ID:            deadLetter.ID,
+								CorrelationID: deadLetter.CorrelationID,
+								EventType:     deadLetter.EventType,
+								Payload:       deadLetter.Payload,
+								Attempts:      deadLetter.Attempts,
+								LastError:     deadLetter.LastError,
+								FailedAt:      deadLetter.FailedAt.Format(time.RFC3339),
+							})
+							if err != nil {
+								return err
+							}
+						}
+						return nil
+					})
+				},
+			},
+			&cli.Command{
+				Name:      "replay",
+				Usage:     "Put a dead letter event back at the end of the outbox",
+				ArgsUsage: "<id>",
+				Action: func(c *cli.Context) error {
+					id, err := deadLetterID(c)
+					if err != nil {
+						return err
+					}
+					return withDeadLetterRepository(func(repository deadletter.Repository) error {
+						err := repository.Replay(c.Context, id)
+						if err == nil {
+							logger.WithField("id", id).Info("dead letter event replayed")
+						}
+						return err
+					})
+				},
+			},
+			&cli.Command{
+				Name:      "discard",
+				Usage:     "Delete a dead letter event without delivering it",
+				ArgsUsage: "<id>",
+				Action: func(c *cli.Context) error {
+					id, err := deadLetterID(c)
+					if err != nil {
+						return err
+					}
+					return withDeadLetterRepository(func(repository deadletter.Repository) error {
+						err := repository.Discard(c.Context, id)
+						if err == nil {
+							logger.WithField("id", id).Info("dead letter event discarded")
+						}
+						return err
+					})
+				},
+			},
+		},
+	}
+}
+
+func deadLetterID(c *cli.Context) (uint64, error) {
+	if c.NArg() != 1 {
+		return 0, fmt.Errorf("%s expects exactly one dead letter id argument", c.Command.Name)
+	}
+	id, err := strconv.ParseUint(c.Args().First(), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid dead letter id %q", c.Args().First())
+	}
+	return id, nil
+}
+
+func withDeadLetterRepository(f func(repository deadletter.Repository) error) (err error) {
+	cnf, err := parseEnvs[outboxConfig]()
+	if err != nil {
+		return err
+	}
+
+	databaseConnector, err := newDatabaseConnector(cnf.Database)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, databaseConnector.Close())
+	}()
+	databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
+
+	libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+	return f(deadletter.NewRepository(integrationevent.TransportName, libUoW))
+}
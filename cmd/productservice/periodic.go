@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+)
+
+func runPeriodically(ctx context.Context, logger logging.Logger, interval time.Duration, job func(ctx context.Context) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		err := job(ctx)
+		if err != nil {
+			logger.Error(err, "periodic job failed")
+		}
+	}
+}
@@ -16,19 +16,35 @@ import (
 	"google.golang.org/grpc"
 
 	"productservice/api/server/productinternal"
+	"productservice/api/server/stockinternal"
+	commontransport "productservice/pkg/common/infrastructure/transport"
 	appservice "productservice/pkg/product/application/service"
 	"productservice/pkg/product/infrastructure/integrationevent"
 	inframysql "productservice/pkg/product/infrastructure/mysql"
 	"productservice/pkg/product/infrastructure/mysql/query"
+	"productservice/pkg/product/infrastructure/storage"
 	"productservice/pkg/product/infrastructure/transport"
 	"productservice/pkg/product/infrastructure/transport/middlewares"
+	stockappservice "productservice/pkg/stock/application/service"
+	stockintegrationevent "productservice/pkg/stock/infrastructure/integrationevent"
+	stockmysql "productservice/pkg/stock/infrastructure/mysql"
+	stockquery "productservice/pkg/stock/infrastructure/mysql/query"
+	stocktransport "productservice/pkg/stock/infrastructure/transport"
 )
 
 type serviceConfig struct {
-	Service  Service  `envconfig:"service"`
-	Database Database `envconfig:"database" required:"true"`
+	Service      Service      `envconfig:"service"`
+	Database     Database     `envconfig:"database" required:"true"`
+	MediaStorage MediaStorage `envconfig:"media_storage"`
 }
 
+// mediaPathPrefix путь HTTP-сервера, по которому раздаются файлы локального хранилища медиа
+const (
+	mediaPathPrefix = "/media/"
+	// grpcMessageOverhead запас размера сообщения на поля запроса помимо содержимого файла
+	grpcMessageOverhead = 1 << 20
+)
+
 func service(logger logging.Logger) *cli.Command {
 	return &cli.Command{
 		Name:   "service",
@@ -56,10 +72,30 @@ func service(logger logging.Logger) *cli.Command {
 			uow := inframysql.NewUnitOfWork(libUoW)
 			luow := inframysql.NewLockableUnitOfWork(libLUow)
 			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+			mediaStorage, err := storage.NewLocalMediaStorage(cnf.MediaStorage.Path, cnf.MediaStorage.BaseURL)
+			if err != nil {
+				return err
+			}
 
 			productInternalAPI := transport.NewProductInternalAPI(
 				query.NewProductQueryService(databaseConnector.TransactionalClient()),
+				query.NewCategoryQueryService(databaseConnector.TransactionalClient()),
+				query.NewAttributeQueryService(databaseConnector.TransactionalClient()),
+				query.NewProductSearchService(databaseConnector.TransactionalClient()),
+				query.NewProductSnapshotService(databaseConnector.TransactionalClient()),
 				appservice.NewProductService(uow, luow, eventDispatcher),
+				appservice.NewMediaService(luow, eventDispatcher, mediaStorage),
+				mediaStorage,
+				appservice.NewCategoryService(luow, eventDispatcher),
+				appservice.NewAttributeService(luow, eventDispatcher),
+			)
+
+			stockLibUoW := mysql.NewUnitOfWork(databaseConnectionPool, stockmysql.NewRepositoryProvider)
+			stockLibLUow := mysql.NewLockableUnitOfWork(stockLibUoW, mysql.NewLocker(databaseConnectionPool))
+			stockEventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, stockintegrationevent.NewEventSerializer(), stockLibUoW)
+			stockInternalAPI := stocktransport.NewStockInternalAPI(
+				stockquery.NewStockQueryService(databaseConnector.TransactionalClient()),
+				stockappservice.NewStockService(stockmysql.NewUnitOfWork(stockLibUoW), stockmysql.NewLockableUnitOfWork(stockLibLUow), stockEventDispatcher),
 			)
 
 			errGroup := errgroup.Group{}
@@ -68,10 +104,21 @@ func service(logger logging.Logger) *cli.Command {
 				if err != nil {
 					return err
 				}
-				grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
-					middlewares.NewGRPCLoggingMiddleware(logger),
-				))
+				errorMapper := commontransport.NewErrorMapper(transport.ErrorMappings, stocktransport.ErrorMappings)
+				grpcServer := grpc.NewServer(
+					// Загружаемые файлы медиа передаются одним сообщением
+					grpc.MaxRecvMsgSize(appservice.MaxMediaContentSize+grpcMessageOverhead),
+					grpc.ChainUnaryInterceptor(
+						errorMapper.UnaryInterceptor(),
+						middlewares.NewGRPCLoggingMiddleware(logger),
+					),
+					grpc.ChainStreamInterceptor(
+						errorMapper.StreamInterceptor(),
+						middlewares.NewGRPCStreamLoggingMiddleware(logger),
+					),
+				)
 				productinternal.RegisterProductInternalServiceServer(grpcServer, productInternalAPI)
+				stockinternal.RegisterStockInternalServiceServer(grpcServer, stockInternalAPI)
 				graceCallback(c.Context, logger, cnf.Service.GracePeriod, func(_ context.Context) error {
 					grpcServer.GracefulStop()
 					return nil
@@ -81,6 +128,7 @@ func service(logger logging.Logger) *cli.Command {
 			errGroup.Go(func() error {
 				router := mux.NewRouter()
 				registerHealthcheck(router)
+				router.PathPrefix(mediaPathPrefix).Handler(http.StripPrefix(mediaPathPrefix, mediaStorage.Handler()))
 				// nolint:gosec
 				server := http.Server{
 					Addr:    cnf.Service.HTTPAddress,
@@ -0,0 +1,146 @@
+package consumer
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	libamqp "gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/amqp"
+	"github.com/pkg/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Message входящее сообщение. ID берется из message_id, а если он не задан - из correlation_id,
+// который outbox других сервисов заполняет уникальным идентификатором события.
+// Type - ключ маршрутизации, с которым сообщение опубликовано
+type Message struct {
+	ID   string
+	Type string
+	Body []byte
+}
+
+// Handler обрабатывает сообщение. Сообщение подтверждается, если Handler не вернул ошибку или вернул ErrRejected,
+// иначе возвращается в очередь через RedeliveryDelay
+type Handler func(ctx context.Context, message Message) error
+
+// ErrRejected сообщение нельзя применить, сколько его ни доставляй. Handler оборачивает в него причину,
+// сам логирует ее, и сообщение подтверждается без повторной доставки
+var ErrRejected = errors.New("message rejected")
+
+type Config struct {
+	ExchangeName    string
+	ExchangeKind    string
+	QueueName       string
+	RoutingKeys     []string
+	PrefetchCount   int
+	RedeliveryDelay time.Duration
+}
+
+// NewConsumer создает канал, который объявляет очередь, привязывает ее к обменнику и читает из нее сообщения.
+// Канал добавляется в соединение через amqp.Connection.AddChannel и переподключается вместе с соединением
+func NewConsumer(ctx context.Context, config Config, handler Handler, logger logging.Logger) libamqp.Channel {
+	return &consumer{
+		ctx:     ctx,
+		config:  config,
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+type consumer struct {
+	ctx     context.Context
+	config  Config
+	handler Handler
+	logger  logging.Logger
+}
+
+func (c *consumer) Connect(conn *amqp.Connection) (err error) {
+	channel, err := conn.Channel()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = channel.Close()
+		}
+	}()
+
+	err = channel.ExchangeDeclare(c.config.ExchangeName, c.config.ExchangeKind, true, false, false, false, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = channel.QueueDeclare(c.config.QueueName, true, false, false, false, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, routingKey := range c.config.RoutingKeys {
+		err = channel.QueueBind(c.config.QueueName, routingKey, c.config.ExchangeName, false, nil)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	err = channel.Qos(c.config.PrefetchCount, 0, false)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	deliveries, err := channel.Consume(c.config.QueueName, "", false, false, false, false, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	go c.consume(deliveries)
+	go c.reconnectOnClose(conn, channel.NotifyClose(make(chan *amqp.Error, 1)))
+	return nil
+}
+
+func (c *consumer) consume(deliveries <-chan amqp.Delivery) {
+	for delivery := range deliveries {
+		messageID := delivery.MessageId
+		if messageID == "" {
+			messageID = delivery.CorrelationId
+		}
+		err := c.handler(c.ctx, Message{
+			ID:   messageID,
+			Type: delivery.RoutingKey,
+			Body: delivery.Body,
+		})
+		if err == nil || errors.Is(err, ErrRejected) {
+			_ = delivery.Ack(false)
+			continue
+		}
+
+		c.logger.WithField("messageID", messageID).Error(err, "failed to handle message, redelivering")
+		select {
+		case <-c.ctx.Done():
+		case <-time.After(c.config.RedeliveryDelay):
+		}
+		_ = delivery.Nack(false, true)
+	}
+}
+
+// reconnectOnClose переоткрывает канал, закрытый брокером при живом соединении.
+// Закрытие соединения обрабатывает amqp.Connection, заново подключая все каналы
+func (c *consumer) reconnectOnClose(conn *amqp.Connection, closed <-chan *amqp.Error) {
+	amqpErr := <-closed
+	if amqpErr == nil || conn.IsClosed() {
+		return
+	}
+
+	c.logger.Error(amqpErr, "AMQP consumer channel closed, trying to reconnect")
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(c.config.RedeliveryDelay):
+		}
+		err := c.Connect(conn)
+		if err == nil {
+			c.logger.Info("AMQP consumer channel restored")
+			return
+		}
+		if conn.IsClosed() {
+			return
+		}
+		c.logger.Error(err, "failed to reconnect AMQP consumer channel")
+	}
+}
@@ -0,0 +1,147 @@
+package deadletter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	pkgerrors "github.com/pkg/errors"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetter событие outbox, которое не удалось доставить за отведенное число попыток
+type DeadLetter struct {
+	ID            uint64    `db:"dead_letter_id"`
+	TransportName string    `db:"transport_name"`
+	CorrelationID string    `db:"correlation_id"`
+	EventType     string    `db:"event_type"`
+	Payload       string    `db:"payload"`
+	Attempts      int       `db:"attempts"`
+	LastError     string    `db:"last_error"`
+	FailedAt      time.Time `db:"failed_at"`
+}
+
+type Repository interface {
+	// Add сохраняет событие, повторное сохранение события с тем же CorrelationID ничего не меняет
+	Add(ctx context.Context, deadLetter DeadLetter) error
+	// List возвращает события транспорта в порядке поступления
+	List(ctx context.Context, limit int) ([]DeadLetter, error)
+	// Replay возвращает событие в конец outbox с тем же CorrelationID, чтобы получатели с inbox не обработали его дважды
+	Replay(ctx context.Context, id uint64) error
+	Discard(ctx context.Context, id uint64) error
+}
+
+// NewRepository работает с событиями одного транспорта, outbox транспорта хранится в таблице outbox_<transportName>_event
+func NewRepository(transportName string, uow mysql.UnitOfWork) Repository {
+	return &repository{
+		transportName: transportName,
+		uow:           uow,
+	}
+}
+
+type repository struct {
+	transportName string
+	uow           mysql.UnitOfWork
+}
+
+func (r *repository) Add(ctx context.Context, deadLetter DeadLetter) error {
+	return r.uow.ExecuteWithClientContext(ctx, func(client mysql.ClientContext) error {
+		_, err := client.ExecContext(
+			ctx,
+			`
+			INSERT IGNORE INTO outbox_dead_letter (transport_name, correlation_id, event_type, payload, attempts, last_error, failed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			`,
+			r.transportName,
+			deadLetter.CorrelationID,
+			deadLetter.EventType,
+			deadLetter.Payload,
+			deadLetter.Attempts,
+			deadLetter.LastError,
+			deadLetter.FailedAt,
+		)
+		return pkgerrors.WithStack(err)
+	})
+}
+
+func (r *repository) List(ctx context.Context, limit int) ([]DeadLetter, error) {
+	var deadLetters []DeadLetter
+	err := r.uow.ExecuteWithClientContext(ctx, func(client mysql.ClientContext) error {
+		return pkgerrors.WithStack(client.SelectContext(
+			ctx,
+			&deadLetters,
+			`
+			SELECT dead_letter_id, transport_name, correlation_id, event_type, payload, attempts, last_error, failed_at
+			FROM outbox_dead_letter
+			WHERE transport_name = ?
+			ORDER BY dead_letter_id
+			LIMIT ?
+			`,
+			r.transportName,
+			limit,
+		))
+	})
+	return deadLetters, err
+}
+
+func (r *repository) Replay(ctx context.Context, id uint64) error {
+	return r.uow.ExecuteWithClientContext(ctx, func(client mysql.ClientContext) error {
+		deadLetter, err := r.findForUpdate(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		_, err = client.ExecContext(
+			ctx,
+			fmt.Sprintf(`INSERT INTO outbox_%s_event (correlation_id, event_type, payload) VALUES (?, ?, ?)`, r.transportName),
+			deadLetter.CorrelationID,
+			deadLetter.EventType,
+			deadLetter.Payload,
+		)
+		if err != nil {
+			return pkgerrors.WithStack(err)
+		}
+		return r.delete(ctx, client, id)
+	})
+}
+
+func (r *repository) Discard(ctx context.Context, id uint64) error {
+	return r.uow.ExecuteWithClientContext(ctx, func(client mysql.ClientContext) error {
+		_, err := r.findForUpdate(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		return r.delete(ctx, client, id)
+	})
+}
+
+func (r *repository) findForUpdate(ctx context.Context, client mysql.ClientContext, id uint64) (*DeadLetter, error) {
+	var deadLetter DeadLetter
+	err := client.GetContext(
+		ctx,
+		&deadLetter,
+		`
+		SELECT dead_letter_id, transport_name, correlation_id, event_type, payload, attempts, last_error, failed_at
+		FROM outbox_dead_letter
+		WHERE dead_letter_id = ? AND transport_name = ?
+		FOR UPDATE
+		`,
+		id,
+		r.transportName,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, pkgerrors.WithStack(ErrDeadLetterNotFound)
+		}
+		return nil, pkgerrors.WithStack(err)
+	}
+	return &deadLetter, nil
+}
+
+func (r *repository) delete(ctx context.Context, client mysql.ClientContext, id uint64) error {
+	_, err := client.ExecContext(ctx, `DELETE FROM outbox_dead_letter WHERE dead_letter_id = ?`, id)
+	return pkgerrors.WithStack(err)
+}
@@ -0,0 +1,122 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+)
+
+// ErrUndeliverable помечает ошибки, которые не исчезнут при повторной отправке того же события,
+// например некорректное содержимое или отказ брокера принять публикацию. Транспорт оборачивает в нее причину
+var ErrUndeliverable = errors.New("event can not be delivered")
+
+// RetryPolicy задает число попыток доставки события, завершившихся ErrUndeliverable, и паузы между попытками.
+// Пауза после n-й неудачной попытки равна InitialBackoff * 2^(n-1), но не больше MaxBackoff
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempts && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, p.MaxBackoff)
+}
+
+// NewTransport ограничивает число попыток доставки события через transport.
+// Обработчик outbox повторяет неотправленное событие на каждой итерации и не отправляет следующие,
+// пока оно не уйдет. До истечения паузы транспорт отвечает ошибкой, не вызывая transport. После
+// MaxAttempts попыток с ErrUndeliverable событие сохраняется в repository и считается доставленным,
+// чтобы очередь outbox двигалась дальше. Остальные ошибки, например недоступность брокера, в dead letter
+// не ведут: транспорт повторяет событие с паузой не больше MaxBackoff, сохраняя порядок событий.
+//
+// Счетчик попыток хранится в памяти и сбрасывается при перезапуске
+func NewTransport(transport outbox.Transport, repository Repository, policy RetryPolicy, logger logging.Logger) outbox.Transport {
+	return &retryingTransport{
+		transport:  transport,
+		repository: repository,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+type retryingTransport struct {
+	transport  outbox.Transport
+	repository Repository
+	policy     RetryPolicy
+	logger     logging.Logger
+
+	mu sync.Mutex
+	// failed последнее недоставленное событие, обработчик outbox отправляет события по одному
+	failed *failedEvent
+}
+
+type failedEvent struct {
+	correlationID string
+	// attempts все неудачные попытки, от них зависит пауза
+	attempts int
+	// undeliverableAttempts попытки, завершившиеся ErrUndeliverable
+	undeliverableAttempts int
+	lastErr               error
+	nextAttemptAt         time.Time
+}
+
+func (t *retryingTransport) HandleEvents(ctx context.Context, correlationID, eventType, payload string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.failed != nil && t.failed.correlationID != correlationID {
+		t.failed = nil
+	}
+	if t.failed != nil && time.Now().Before(t.failed.nextAttemptAt) {
+		return fmt.Errorf("delivery postponed until %s after %d attempts: %w",
+			t.failed.nextAttemptAt.Format(time.RFC3339), t.failed.attempts, t.failed.lastErr)
+	}
+
+	err := t.transport.HandleEvents(ctx, correlationID, eventType, payload)
+	if err == nil {
+		t.failed = nil
+		return nil
+	}
+
+	if t.failed == nil {
+		t.failed = &failedEvent{correlationID: correlationID}
+	}
+	t.failed.attempts++
+	t.failed.lastErr = err
+	undeliverable := errors.Is(err, ErrUndeliverable)
+	if undeliverable {
+		t.failed.undeliverableAttempts++
+	}
+	if !undeliverable || t.failed.undeliverableAttempts < t.policy.MaxAttempts {
+		t.failed.nextAttemptAt = time.Now().Add(t.policy.backoff(t.failed.attempts))
+		return err
+	}
+
+	err = t.repository.Add(ctx, DeadLetter{
+		CorrelationID: correlationID,
+		EventType:     eventType,
+		Payload:       payload,
+		Attempts:      t.failed.attempts,
+		LastError:     err.Error(),
+		FailedAt:      time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	t.logger.WithFields(logging.Fields{
+		"correlationID": correlationID,
+		"eventType":     eventType,
+		"attempts":      t.failed.attempts,
+	}).Warning(t.failed.lastErr, "event moved to dead letter")
+	t.failed = nil
+	return nil
+}
@@ -0,0 +1,160 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mocks ---
+type mockTransport struct {
+	errs  []error
+	calls int
+}
+
+// HandleEvents возвращает ошибки из errs по очереди, после них - успех
+func (m *mockTransport) HandleEvents(context.Context, string, string, string) error {
+	m.calls++
+	if len(m.errs) == 0 {
+		return nil
+	}
+	err := m.errs[0]
+	m.errs = m.errs[1:]
+	return err
+}
+
+type mockRepository struct {
+	deadLetters []DeadLetter
+}
+
+func (m *mockRepository) Add(_ context.Context, deadLetter DeadLetter) error {
+	m.deadLetters = append(m.deadLetters, deadLetter)
+	return nil
+}
+
+func (m *mockRepository) List(context.Context, int) ([]DeadLetter, error) {
+	return m.deadLetters, nil
+}
+
+func (m *mockRepository) Replay(context.Context, uint64) error {
+	return nil
+}
+
+func (m *mockRepository) Discard(context.Context, uint64) error {
+	return nil
+}
+
+type mockLogger struct{}
+
+func (m mockLogger) WithField(string, interface{}) logging.Logger { return m }
+func (m mockLogger) WithFields(logging.Fields) logging.Logger     { return m }
+func (m mockLogger) Info(...interface{})                          {}
+func (m mockLogger) Error(error, ...interface{})                  {}
+func (m mockLogger) Warning(error, ...interface{})                {}
+func (m mockLogger) Debug(...interface{})                         {}
+
+func repeatErr(err error, n int) []error {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+var (
+	errConnection    = errors.New("amqp channel is closed")
+	errUndeliverable = fmt.Errorf("%w: failed to publish delivery", ErrUndeliverable)
+)
+
+// --- Tests ---
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 10 * time.Second, MaxBackoff: 10 * time.Minute}
+	testCases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: 10 * time.Second},
+		{attempts: 2, expected: 20 * time.Second},
+		{attempts: 3, expected: 40 * time.Second},
+		{attempts: 7, expected: 10 * time.Minute},
+		{attempts: 1000, expected: 10 * time.Minute},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprint(tc.attempts), func(t *testing.T) {
+			assert.Equal(t, tc.expected, policy.backoff(tc.attempts))
+		})
+	}
+}
+
+func TestRetryingTransport_ConnectionErrorsNeverDeadLetter(t *testing.T) {
+	// Arrange
+	inner := &mockTransport{errs: repeatErr(errConnection, 20)}
+	repository := &mockRepository{}
+	transport := NewTransport(inner, repository, RetryPolicy{MaxAttempts: 3}, mockLogger{})
+
+	// Act & Assert
+	for range 20 {
+		err := transport.HandleEvents(context.Background(), "event-1", "product_created", `{}`)
+		require.ErrorIs(t, err, errConnection)
+	}
+	require.NoError(t, transport.HandleEvents(context.Background(), "event-1", "product_created", `{}`))
+	assert.Empty(t, repository.deadLetters)
+	assert.Equal(t, 21, inner.calls)
+}
+
+func TestRetryingTransport_UndeliverableMovedToDeadLetter(t *testing.T) {
+	// Arrange
+	inner := &mockTransport{errs: append([]error{errConnection}, repeatErr(errUndeliverable, 3)...)}
+	repository := &mockRepository{}
+	transport := NewTransport(inner, repository, RetryPolicy{MaxAttempts: 3}, mockLogger{})
+
+	// Act & Assert
+	for range 3 {
+		err := transport.HandleEvents(context.Background(), "event-1", "product_created", `{}`)
+		require.Error(t, err)
+	}
+	// Третий отказ брокера переносит событие в dead letter, ошибка соединения в MaxAttempts не считается
+	err := transport.HandleEvents(context.Background(), "event-1", "product_created", `{}`)
+	require.NoError(t, err)
+
+	require.Len(t, repository.deadLetters, 1)
+	deadLetter := repository.deadLetters[0]
+	assert.Equal(t, "event-1", deadLetter.CorrelationID)
+	assert.Equal(t, "product_created", deadLetter.EventType)
+	assert.Equal(t, `{}`, deadLetter.Payload)
+	assert.Equal(t, 4, deadLetter.Attempts)
+	assert.Equal(t, errUndeliverable.Error(), deadLetter.LastError)
+
+	// Следующее событие отправляется с чистым счетчиком
+	require.NoError(t, transport.HandleEvents(context.Background(), "event-2", "product_updated", `{}`))
+	assert.Equal(t, 5, inner.calls)
+}
+
+func TestRetryingTransport_PostponesUntilBackoff(t *testing.T) {
+	// Arrange
+	inner := &mockTransport{errs: []error{errConnection}}
+	repository := &mockRepository{}
+	transport := NewTransport(inner, repository, RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Hour,
+		MaxBackoff:     time.Hour,
+	}, mockLogger{})
+
+	// Act
+	firstErr := transport.HandleEvents(context.Background(), "event-1", "product_created", `{}`)
+	postponedErr := transport.HandleEvents(context.Background(), "event-1", "product_created", `{}`)
+
+	// Assert
+	require.ErrorIs(t, firstErr, errConnection)
+	require.ErrorIs(t, postponedErr, errConnection)
+	assert.Contains(t, postponedErr.Error(), "delivery postponed")
+	assert.Equal(t, 1, inner.calls)
+	assert.Empty(t, repository.deadLetters)
+}
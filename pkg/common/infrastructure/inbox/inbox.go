@@ -0,0 +1,88 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/common/infrastructure/consumer"
+)
+
+// NewHandler оборачивает обработчик входящих сообщений транзакционным inbox.
+// Идентификатор сообщения записывается в inbox_message в транзакции, внутри которой выполняется handler:
+// unit of work с тем же ctx и тем же пулом транзакций, что и uow, продолжают эту транзакцию.
+// Поэтому эффекты сообщения и отметка о нем фиксируются или откатываются вместе, а повторно доставленное
+// сообщение пропускается без вызова handler.
+//
+// Транзакция начинается до блокировок handler, а блокировки handler снимаются до ее фиксации, поэтому handler
+// должен брать блокировки до первого чтения и читать изменяемые строки блокирующим чтением.
+//
+// Отклоненное сообщение (consumer.ErrRejected) откатывает транзакцию вместе с отметкой,
+// поэтому отметка о нем записывается отдельной транзакцией
+func NewHandler(uow mysql.UnitOfWork, handler consumer.Handler) consumer.Handler {
+	return func(ctx context.Context, message consumer.Message) error {
+		if message.ID == "" {
+			return handler(ctx, message)
+		}
+
+		duplicate := false
+		err := uow.ExecuteWithClientContext(ctx, func(client mysql.ClientContext) error {
+			registered, err := register(ctx, client, message, time.Now())
+			if err != nil {
+				return err
+			}
+			if !registered {
+				duplicate = true
+				return nil
+			}
+			return handler(ctx, message)
+		})
+		if errors.Is(err, consumer.ErrRejected) {
+			return registerRejected(ctx, uow, message)
+		}
+		if err != nil {
+			return err
+		}
+		if duplicate {
+			metrics.duplicate(message.Type)
+		} else {
+			metrics.processed(message.Type)
+		}
+		return nil
+	}
+}
+
+func registerRejected(ctx context.Context, uow mysql.UnitOfWork, message consumer.Message) error {
+	registered := false
+	err := uow.ExecuteWithClientContext(ctx, func(client mysql.ClientContext) (err error) {
+		registered, err = register(ctx, client, message, time.Now())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if registered {
+		metrics.rejected(message.Type)
+	} else {
+		metrics.duplicate(message.Type)
+	}
+	return nil
+}
+
+// register при одновременной доставке дубля ждет завершения транзакции, первой вставившей сообщение
+func register(ctx context.Context, client mysql.ClientContext, message consumer.Message, processedAt time.Time) (bool, error) {
+	result, err := client.ExecContext(
+		ctx,
+		`INSERT IGNORE INTO inbox_message (message_id, message_type, processed_at) VALUES (?, ?, ?)`,
+		message.ID,
+		message.Type,
+		processedAt,
+	)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	count, err := result.RowsAffected()
+	return count == 1, errors.WithStack(err)
+}
@@ -0,0 +1,190 @@
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+	"testing"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/common/infrastructure/consumer"
+)
+
+// --- Mocks ---
+
+// mockUnitOfWork хранит отметки inbox_message: отметки транзакции фиксируются, только если callback не вернул ошибку
+type mockUnitOfWork struct {
+	committed map[string]bool
+}
+
+func newMockUnitOfWork() *mockUnitOfWork {
+	return &mockUnitOfWork{committed: make(map[string]bool)}
+}
+
+func (m *mockUnitOfWork) ExecuteWithClientContext(_ context.Context, callback func(client mysql.ClientContext) error) error {
+	client := &mockClient{committed: m.committed, pending: make(map[string]bool)}
+	err := callback(client)
+	if err != nil {
+		return err
+	}
+	for messageID := range client.pending {
+		m.committed[messageID] = true
+	}
+	return nil
+}
+
+type mockClient struct {
+	mysql.ClientContext
+	committed map[string]bool
+	pending   map[string]bool
+}
+
+// ExecContext выполняет только INSERT IGNORE INTO inbox_message, первый аргумент - идентификатор сообщения
+func (m *mockClient) ExecContext(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+	messageID := args[0].(string)
+	if m.committed[messageID] || m.pending[messageID] {
+		return mockResult(0), nil
+	}
+	m.pending[messageID] = true
+	return mockResult(1), nil
+}
+
+type mockResult int64
+
+func (m mockResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (m mockResult) RowsAffected() (int64, error) {
+	return int64(m), nil
+}
+
+type mockHandler struct {
+	err   error
+	calls int
+}
+
+func (m *mockHandler) handle(context.Context, consumer.Message) error {
+	m.calls++
+	return m.err
+}
+
+// counter возвращает значение счетчика метрик inbox, например "processed.order.created"
+func counter(name string) int64 {
+	value, ok := metrics.m.Get(name).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return value.Value()
+}
+
+type counters struct {
+	processed, rejected, duplicates int64
+}
+
+func readCounters(messageType string) counters {
+	return counters{
+		processed:  counter("processed." + messageType),
+		rejected:   counter("rejected." + messageType),
+		duplicates: counter("duplicates." + messageType),
+	}
+}
+
+// --- Tests ---
+
+func TestNewHandler_ProcessedThenDuplicate(t *testing.T) {
+	// Arrange
+	uow := newMockUnitOfWork()
+	handler := &mockHandler{}
+	inboxHandler := NewHandler(uow, handler.handle)
+	message := consumer.Message{ID: "message-1", Type: "test.processed"}
+	before := readCounters(message.Type)
+
+	// Act
+	firstErr := inboxHandler(context.Background(), message)
+	secondErr := inboxHandler(context.Background(), message)
+
+	// Assert
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	assert.Equal(t, 1, handler.calls)
+	assert.True(t, uow.committed[message.ID])
+	assert.Equal(t, counters{
+		processed:  before.processed + 1,
+		rejected:   before.rejected,
+		duplicates: before.duplicates + 1,
+	}, readCounters(message.Type))
+}
+
+func TestNewHandler_Rejected(t *testing.T) {
+	// Arrange
+	uow := newMockUnitOfWork()
+	handler := &mockHandler{err: errors.Join(consumer.ErrRejected, errors.New("insufficient stock"))}
+	inboxHandler := NewHandler(uow, handler.handle)
+	message := consumer.Message{ID: "message-1", Type: "test.rejected"}
+	before := readCounters(message.Type)
+
+	// Act
+	firstErr := inboxHandler(context.Background(), message)
+	secondErr := inboxHandler(context.Background(), message)
+
+	// Assert
+	// Транзакция обработчика откатилась, но отметка записана отдельно, поэтому повтор не доходит до обработчика
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	assert.Equal(t, 1, handler.calls)
+	assert.True(t, uow.committed[message.ID])
+	assert.Equal(t, counters{
+		processed:  before.processed,
+		rejected:   before.rejected + 1,
+		duplicates: before.duplicates + 1,
+	}, readCounters(message.Type))
+}
+
+func TestNewHandler_FailedIsRedelivered(t *testing.T) {
+	// Arrange
+	uow := newMockUnitOfWork()
+	handlerErr := errors.New("database is unavailable")
+	handler := &mockHandler{err: handlerErr}
+	inboxHandler := NewHandler(uow, handler.handle)
+	message := consumer.Message{ID: "message-1", Type: "test.failed"}
+	before := readCounters(message.Type)
+
+	// Act
+	firstErr := inboxHandler(context.Background(), message)
+	handler.err = nil
+	secondErr := inboxHandler(context.Background(), message)
+
+	// Assert
+	// Отметка откатилась вместе с обработкой, поэтому повторная доставка обрабатывается
+	require.ErrorIs(t, firstErr, handlerErr)
+	require.NoError(t, secondErr)
+	assert.Equal(t, 2, handler.calls)
+	assert.Equal(t, counters{
+		processed:  before.processed + 1,
+		rejected:   before.rejected,
+		duplicates: before.duplicates,
+	}, readCounters(message.Type))
+}
+
+func TestNewHandler_MessageWithoutID(t *testing.T) {
+	// Arrange
+	uow := newMockUnitOfWork()
+	handler := &mockHandler{}
+	inboxHandler := NewHandler(uow, handler.handle)
+	message := consumer.Message{Type: "test.without_id"}
+	before := readCounters(message.Type)
+
+	// Act
+	err := inboxHandler(context.Background(), message)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, handler.calls)
+	assert.Empty(t, uow.committed)
+	assert.Equal(t, before, readCounters(message.Type))
+}
@@ -0,0 +1,30 @@
+package inbox
+
+import "expvar"
+
+// metrics публикуются в expvar под именем "inbox": число обработанных, отклоненных и повторно доставленных сообщений,
+// всего и по типам сообщений, например "duplicates" и "duplicates.order.created"
+var metrics = inboxMetrics{m: expvar.NewMap("inbox")}
+
+type inboxMetrics struct {
+	m *expvar.Map
+}
+
+func (m inboxMetrics) processed(messageType string) {
+	m.add("processed", messageType)
+}
+
+func (m inboxMetrics) rejected(messageType string) {
+	m.add("rejected", messageType)
+}
+
+func (m inboxMetrics) duplicate(messageType string) {
+	m.add("duplicates", messageType)
+}
+
+func (m inboxMetrics) add(name, messageType string) {
+	m.m.Add(name, 1)
+	if messageType != "" {
+		m.m.Add(name+"."+messageType, 1)
+	}
+}
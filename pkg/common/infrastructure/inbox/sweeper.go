@@ -0,0 +1,50 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+// sweepBatchSize ограничивает число строк, удаляемых одним запросом, чтобы не держать долгих блокировок
+const sweepBatchSize = 1000
+
+// Sweeper удаляет старые записи inbox. Сообщение, доставленное повторно после удаления его записи,
+// будет обработано еще раз, поэтому срок хранения должен превышать время, за которое брокер может повторить доставку
+type Sweeper interface {
+	Sweep(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+func NewSweeper(client mysql.ClientContext) Sweeper {
+	return &sweeper{client: client}
+}
+
+type sweeper struct {
+	client mysql.ClientContext
+}
+
+func (s *sweeper) Sweep(ctx context.Context, retention time.Duration) (int64, error) {
+	processedBefore := time.Now().Add(-retention)
+	var total int64
+	for {
+		result, err := s.client.ExecContext(
+			ctx,
+			`DELETE FROM inbox_message WHERE processed_at < ? ORDER BY processed_at LIMIT ?`,
+			processedBefore,
+			sweepBatchSize,
+		)
+		if err != nil {
+			return total, errors.WithStack(err)
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return total, errors.WithStack(err)
+		}
+		total += count
+		if count < sweepBatchSize {
+			return total, nil
+		}
+	}
+}
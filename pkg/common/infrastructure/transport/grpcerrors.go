@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errorDomain = "productservice"
+
+// ErrorMapping gRPC код и причина ErrorInfo для ошибок, совпадающих с Target по errors.Is.
+// Metadata, если задан, заполняет метаданные ErrorInfo
+type ErrorMapping struct {
+	Target   error
+	Code     codes.Code
+	Reason   string
+	Metadata func(err error) map[string]string
+}
+
+// commonErrorMappings ошибки инфраструктуры, общие для всех модулей
+var commonErrorMappings = []ErrorMapping{
+	{Target: mysql.ErrLockTimeout, Code: codes.Aborted, Reason: "LOCK_TIMEOUT"},
+}
+
+// ErrorMapper переводит ошибки в gRPC статусы по соответствиям, которые регистрирует каждый модуль
+type ErrorMapper struct {
+	mappings []ErrorMapping
+}
+
+// NewErrorMapper объединяет соответствия модулей, первое совпавшее соответствие выигрывает
+func NewErrorMapper(mappings ...[]ErrorMapping) *ErrorMapper {
+	m := &ErrorMapper{}
+	for _, moduleMappings := range mappings {
+		m.mappings = append(m.mappings, moduleMappings...)
+	}
+	m.mappings = append(m.mappings, commonErrorMappings...)
+	return m
+}
+
+// UnaryInterceptor переводит ошибки обработчиков в gRPC статусы.
+// Неизвестные ошибки превращаются в codes.Internal без текста исходной ошибки
+func (m *ErrorMapper) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, m.toGRPCError(err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamInterceptor то же, что UnaryInterceptor, для потоковых вызовов
+func (m *ErrorMapper) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, stream)
+		if err != nil {
+			return m.toGRPCError(err)
+		}
+		return nil
+	}
+}
+
+// ErrorReason возвращает причину и текст ошибки так же, как их получил бы клиент в gRPC статусе.
+// Используется там, где ошибка передается в ответе, а не статусом вызова
+func (m *ErrorMapper) ErrorReason(err error) (reason string, message string) {
+	st := status.Convert(m.toGRPCError(err))
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			reason = info.Reason
+		}
+	}
+	return reason, st.Message()
+}
+
+func (m *ErrorMapper) toGRPCError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, context.Canceled.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, context.DeadlineExceeded.Error())
+	}
+
+	for _, mapping := range m.mappings {
+		if !errors.Is(err, mapping.Target) {
+			continue
+		}
+		var metadata map[string]string
+		if mapping.Metadata != nil {
+			metadata = mapping.Metadata(err)
+		}
+		st, detailsErr := status.New(mapping.Code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+			Reason:   mapping.Reason,
+			Domain:   errorDomain,
+			Metadata: metadata,
+		})
+		if detailsErr != nil {
+			return status.Error(mapping.Code, err.Error())
+		}
+		return st.Err()
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// AttributeDefinition DTO, Type одно из "string", "number", "bool", "enum"
+type AttributeDefinition struct {
+	Code       string
+	Name       string
+	Type       string
+	EnumValues []string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// AttributeValue DTO, значение в каноническом виде: числа без лишних нулей, bool как "true" или "false"
+type AttributeValue struct {
+	Code  string
+	Value string
+}
@@ -0,0 +1,36 @@
+package model
+
+import "github.com/google/uuid"
+
+// ProductImport DTO строки импорта. nil означает, что поле существующего продукта не меняется,
+// а у нового продукта остается пустым
+type ProductImport struct {
+	Name        *string
+	Description *string
+	Prices      *[]Money
+	SKU         *string
+	Barcodes    *[]string
+}
+
+// ImportKey поле, по которому строка импорта сопоставляется с существующим продуктом
+type ImportKey int
+
+const (
+	ImportByName ImportKey = iota
+	ImportBySKU
+)
+
+type ImportAction int
+
+const (
+	ImportCreated ImportAction = iota
+	ImportUpdated
+	// ImportUnchanged продукт найден, но строка не изменила ни одного поля
+	ImportUnchanged
+)
+
+// ImportResult DTO результата импорта строки, ProductID пустой для продукта, созданного в режиме dry run
+type ImportResult struct {
+	ProductID uuid.UUID
+	Action    ImportAction
+}
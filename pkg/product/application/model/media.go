@@ -0,0 +1,27 @@
+package model
+
+import "github.com/google/uuid"
+
+// Media DTO, задан ровно один из URL и StorageKey
+type Media struct {
+	MediaID     uuid.UUID
+	URL         string
+	StorageKey  string
+	AltText     string
+	Width       int
+	Height      int
+	ContentType string
+	Primary     bool
+}
+
+// MediaCreate DTO нового медиа. Content загружается в хранилище медиа вместо внешнего URL,
+// размеры и тип содержимого загруженного изображения определяются по нему, если не заданы
+type MediaCreate struct {
+	URL         string
+	Content     []byte
+	AltText     string
+	Width       int
+	Height      int
+	ContentType string
+	Primary     bool
+}
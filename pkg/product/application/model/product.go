@@ -1,10 +1,59 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Product DTO
 type Product struct {
 	ProductID uuid.UUID
-	Name      string
-	Price     int64
+	// Name и Description тексты локали по умолчанию
+	Name         string
+	Description  string
+	Translations []Translation
+	// Localized заполняется только при чтении по запрошенным локалям
+	Localized LocalizedText
+	Prices    []Money
+	SKU       string
+	Barcodes  []string
+	// CategoryIDs заполняется только при чтении, категории назначаются отдельно
+	CategoryIDs []uuid.UUID
+	// Attributes заполняется только при чтении, атрибуты назначаются отдельно
+	Attributes []AttributeValue
+	// Variants заполняется только при чтении, варианты меняются отдельно
+	Variants []Variant
+	// Media заполняется только при чтении, медиа меняются отдельно
+	Media []Media
+	// Status заполняется только при чтении, одно из "draft", "active", "archived"
+	Status    string
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ProductUpdate DTO частичного обновления, nil означает, что поле не меняется
+type ProductUpdate struct {
+	Name         *string
+	Description  *string
+	Translations *[]Translation
+	Prices       *[]Money
+	// DefaultCurrencyPrice заменяет только цену в валюте по умолчанию, игнорируется при заданном Prices
+	DefaultCurrencyPrice *int64
+	SKU                  *string
+	Barcodes             *[]string
+}
+
+// Money DTO, сумма в минимальных единицах валюты
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// PriceHistoryEntry DTO, Amount nil означает, что цена в валюте удалена
+type PriceHistoryEntry struct {
+	Currency      string
+	Amount        *int64
+	EffectiveFrom time.Time
 }
@@ -0,0 +1,16 @@
+package model
+
+// Translation DTO перевода имени и описания продукта, Locale тег BCP 47
+type Translation struct {
+	Locale      string
+	Name        string
+	Description string
+}
+
+// LocalizedText DTO имени и описания, выбранных по запрошенным локалям.
+// Пустой Locale означает тексты локали по умолчанию
+type LocalizedText struct {
+	Locale      string
+	Name        string
+	Description string
+}
@@ -0,0 +1,23 @@
+package model
+
+import "github.com/google/uuid"
+
+// Variant DTO, пустой Prices означает цены продукта
+type Variant struct {
+	VariantID uuid.UUID
+	Options   []VariantOption
+	Prices    []Money
+	SKU       string
+}
+
+type VariantOption struct {
+	Name  string
+	Value string
+}
+
+// VariantUpdate DTO частичного обновления варианта, nil означает, что поле не меняется
+type VariantUpdate struct {
+	Options *[]VariantOption
+	Prices  *[]Money
+	SKU     *string
+}
@@ -0,0 +1,12 @@
+package query
+
+import (
+	"context"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+type AttributeQueryService interface {
+	// ListAttributeDefinitions возвращает все определения атрибутов, упорядоченные по коду
+	ListAttributeDefinitions(ctx context.Context) ([]appmodel.AttributeDefinition, error)
+}
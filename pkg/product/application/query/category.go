@@ -0,0 +1,15 @@
+package query
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+type CategoryQueryService interface {
+	FindCategory(ctx context.Context, categoryID uuid.UUID) (*appmodel.Category, error)
+	// ListCategories возвращает дочерние категории parentID, для nil - корневые категории, упорядоченные по имени
+	ListCategories(ctx context.Context, parentID *uuid.UUID) ([]appmodel.Category, error)
+}
@@ -0,0 +1,62 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/text/language"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/domain/model"
+)
+
+// anyLocale тег, которым language.ParseAcceptLanguage представляет "*"
+var anyLocale = language.Make("mul")
+
+// LocaleChain строит цепочку локалей для выбора перевода по значению в формате Accept-Language:
+// локали в порядке убывания веса, после каждой ее родительские локали, например "pt-BR, en;q=0.8" дает pt-BR, pt, en.
+// Пустое значение и "*" дают пустую цепочку, то есть тексты локали по умолчанию
+func LocaleChain(acceptLanguage string) ([]string, error) {
+	if acceptLanguage == "" {
+		return nil, nil
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", model.ErrInvalidLocale, acceptLanguage)
+	}
+
+	var chain []string
+	for _, tag := range tags {
+		if tag == anyLocale {
+			continue
+		}
+		for ; tag != language.Und; tag = tag.Parent() {
+			if locale := tag.String(); !slices.Contains(chain, locale) {
+				chain = append(chain, locale)
+			}
+		}
+	}
+	return chain, nil
+}
+
+// Localize выбирает перевод по первой подходящей локали цепочки, без подходящего перевода
+// остаются тексты локали по умолчанию
+func Localize(product appmodel.Product, chain []string) appmodel.LocalizedText {
+	for _, locale := range chain {
+		i := slices.IndexFunc(product.Translations, func(t appmodel.Translation) bool {
+			return t.Locale == locale
+		})
+		if i != -1 {
+			translation := product.Translations[i]
+			return appmodel.LocalizedText{
+				Locale:      translation.Locale,
+				Name:        translation.Name,
+				Description: translation.Description,
+			}
+		}
+	}
+	return appmodel.LocalizedText{
+		Name:        product.Name,
+		Description: product.Description,
+	}
+}
@@ -2,12 +2,100 @@ package query
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 
 	appmodel "productservice/pkg/product/application/model"
 )
 
+var (
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrTooManyProductIDs = errors.New("too many product ids")
+
+	ErrTooManyAttributeFilters = errors.New("too many attribute filters")
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+
+	MaxFindProductsBatchSize = 100
+
+	MaxAttributeFilters = 10
+)
+
+type ProductSortField int
+
+const (
+	SortByCreatedAt ProductSortField = iota
+	SortByName
+	SortByPrice
+)
+
+// ListProductsSpec фильтры, сортировка и пагинация списка продуктов.
+// Границы цены включаются, временные диапазоны полуоткрытые: From включается, To - нет
+type ListProductsSpec struct {
+	// Locale значение в формате Accept-Language для выбора переводов, см. LocaleChain
+	Locale string
+	// NamePrefix и сортировка по имени используют имя в локали по умолчанию
+	NamePrefix *string
+	// PriceCurrency валюта для фильтра и сортировки по цене, по умолчанию model.DefaultCurrency.
+	// Продукты без цены в этой валюте не попадают в выборку с фильтром или сортировкой по цене
+	PriceCurrency string
+	MinPrice      *int64
+	MaxPrice      *int64
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	UpdatedFrom   *time.Time
+	UpdatedTo     *time.Time
+	CategoryID    *uuid.UUID
+	// IncludeSubcategories включает в фильтр по CategoryID продукты всех подкатегорий
+	IncludeSubcategories bool
+	// Statuses оставляет продукты с одним из статусов "draft", "active", "archived", пустой - с любым статусом
+	Statuses []string
+	// AttributeFilters все условия должны выполняться, не больше MaxAttributeFilters
+	AttributeFilters []AttributeFilter
+
+	SortBy     ProductSortField
+	Descending bool
+
+	// Cursor указывает на последний продукт предыдущей страницы, пустой для первой страницы
+	Cursor string
+	// Limit размер страницы, по умолчанию DefaultListLimit, не больше MaxListLimit
+	Limit int
+}
+
+// AttributeFilter условие на значение атрибута Code. Value сравнивается с каноническим значением,
+// MinNumber и MaxNumber включаются и применимы к числовым атрибутам
+type AttributeFilter struct {
+	Code      string
+	Value     *string
+	MinNumber *float64
+	MaxNumber *float64
+}
+
+type ProductList struct {
+	Products []appmodel.Product
+	// NextCursor пустой, если страница последняя
+	NextCursor string
+}
+
 type ProductQueryService interface {
-	FindProduct(ctx context.Context, productID uuid.UUID) (*appmodel.Product, error)
+	// FindProduct заполняет Localized по locale в формате Accept-Language, см. LocaleChain
+	FindProduct(ctx context.Context, productID uuid.UUID, locale string) (*appmodel.Product, error)
+	// FindProductByName сравнивает имена по тем же правилам, что и проверка уникальности имени
+	FindProductByName(ctx context.Context, name string) (*appmodel.Product, error)
+	// FindProductBySKU и FindProductByBarcode нормализуют значение так же, как при сохранении,
+	// поэтому штрихкод UPC-A находит продукт с соответствующим EAN-13
+	FindProductBySKU(ctx context.Context, sku string) (*appmodel.Product, error)
+	FindProductByBarcode(ctx context.Context, barcode string) (*appmodel.Product, error)
+	ListProducts(ctx context.Context, spec ListProductsSpec) (*ProductList, error)
+	// FindProducts возвращает найденные продукты, отсутствующие идентификаторы пропускаются.
+	// Возвращает ErrTooManyProductIDs, если идентификаторов больше MaxFindProductsBatchSize
+	FindProducts(ctx context.Context, productIDs []uuid.UUID) ([]appmodel.Product, error)
+	// GetPriceHistory возвращает изменения цен продукта по возрастанию EffectiveFrom,
+	// непустой currency ограничивает историю одной валютой
+	GetPriceHistory(ctx context.Context, productID uuid.UUID, currency *string) ([]appmodel.PriceHistoryEntry, error)
 }
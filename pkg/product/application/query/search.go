@@ -0,0 +1,68 @@
+package query
+
+import (
+	"context"
+	"errors"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	// MaxSearchOffset ограничивает глубину пагинации, дальние страницы по релевантности дороги и бесполезны
+	MaxSearchOffset = 1000
+	// MaxSearchTerms ограничивает число слов запроса, лишние слова отбрасываются
+	MaxSearchTerms = 10
+)
+
+// SearchProductsSpec запрос полнотекстового поиска. Каждое слово Query ищется как начало слова
+// в имени и описании продукта и в его переводах, продукт должен содержать все слова
+type SearchProductsSpec struct {
+	Query string
+	// Locale значение в формате Accept-Language для выбора переводов, см. LocaleChain
+	Locale string
+	// Statuses оставляет продукты с одним из статусов, пустой - с любым статусом
+	Statuses []string
+
+	// Cursor указывает на следующую страницу результатов того же запроса, пустой для первой страницы
+	Cursor string
+	// Limit размер страницы, по умолчанию DefaultSearchLimit, не больше MaxSearchLimit
+	Limit int
+}
+
+// ProductSearchHit найденный продукт, Relevance имеет смысл только для сравнения результатов одного запроса
+type ProductSearchHit struct {
+	Product    appmodel.Product
+	Relevance  float64
+	Highlights []Highlight
+}
+
+// Highlight фрагмент текста продукта с совпадениями слов запроса.
+// Field "name" или "description", пустой Locale означает текст локали по умолчанию
+type Highlight struct {
+	Field   string
+	Locale  string
+	Text    string
+	Matches []TextRange
+}
+
+// TextRange полуоткрытый диапазон [Start, End) в символах Unicode
+type TextRange struct {
+	Start int
+	End   int
+}
+
+type ProductSearchResult struct {
+	// Hits отсортированы по убыванию релевантности
+	Hits []ProductSearchHit
+	// NextCursor пустой, если страница последняя
+	NextCursor string
+}
+
+// ProductSearchService полнотекстовый поиск продуктов, реализация зависит от поискового движка
+type ProductSearchService interface {
+	SearchProducts(ctx context.Context, spec SearchProductsSpec) (*ProductSearchResult, error)
+}
@@ -0,0 +1,23 @@
+package query
+
+import (
+	"context"
+	"time"
+)
+
+// SnapshotWatermark позиция согласованного снимка каталога. Изменения, не попавшие в снимок,
+// публикуются событиями outbox с идентификатором больше OutboxEventID
+type SnapshotWatermark struct {
+	// MaxUpdatedAt наибольший UpdatedAt продуктов снимка, нулевой для пустого каталога
+	MaxUpdatedAt time.Time
+	// OutboxEventID последнее событие outbox, изменения которого вошли в снимок.
+	// Может быть меньше фактического, тогда часть событий после него уже отражена в снимке и отличима по Version
+	OutboxEventID uint64
+}
+
+// ProductSnapshotService выполняет чтения каталога в одном согласованном снимке базы
+type ProductSnapshotService interface {
+	// InSnapshot вызывает f с ProductQueryService, все чтения которого видят один и тот же снимок,
+	// и водяным знаком этого снимка
+	InSnapshot(ctx context.Context, f func(watermark SnapshotWatermark, products ProductQueryService) error) error
+}
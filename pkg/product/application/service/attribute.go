@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/domain/model"
+	"productservice/pkg/product/domain/service"
+)
+
+type AttributeService interface {
+	CreateAttributeDefinition(ctx context.Context, definition appmodel.AttributeDefinition) error
+	// UpdateAttributeDefinition меняет имя и значения перечисления атрибута
+	UpdateAttributeDefinition(ctx context.Context, code, name string, enumValues []string) error
+	DeleteAttributeDefinition(ctx context.Context, code string) error
+	// SetProductAttributes заменяет все значения атрибутов продукта
+	SetProductAttributes(ctx context.Context, productID uuid.UUID, values []appmodel.AttributeValue) error
+}
+
+func NewAttributeService(
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+) AttributeService {
+	return &attributeService{
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+type attributeService struct {
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+}
+
+func (s *attributeService) CreateAttributeDefinition(ctx context.Context, definition appmodel.AttributeDefinition) error {
+	attributeType, err := model.ParseAttributeType(definition.Type)
+	if err != nil {
+		return err
+	}
+	return s.luow.Execute(ctx, []string{attributeLock(definition.Code)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).CreateAttributeDefinition(model.AttributeDefinition{
+			Code:       definition.Code,
+			Name:       definition.Name,
+			Type:       attributeType,
+			EnumValues: definition.EnumValues,
+		})
+	})
+}
+
+func (s *attributeService) UpdateAttributeDefinition(ctx context.Context, code, name string, enumValues []string) error {
+	return s.luow.Execute(ctx, []string{attributeLock(code)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).UpdateAttributeDefinition(code, name, enumValues)
+	})
+}
+
+func (s *attributeService) DeleteAttributeDefinition(ctx context.Context, code string) error {
+	return s.luow.Execute(ctx, []string{attributeLock(code)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).DeleteAttributeDefinition(code)
+	})
+}
+
+func (s *attributeService) SetProductAttributes(ctx context.Context, productID uuid.UUID, values []appmodel.AttributeValue) error {
+	// Блокировки атрибутов не дают удалить определение или значение перечисления, пока оно назначается.
+	// Атрибуты блокируются в одном порядке, чтобы параллельные запросы не ждали друг друга по кругу
+	var attributeLocks []string
+	domainValues := make([]model.AttributeValue, 0, len(values))
+	for _, value := range values {
+		attributeLocks = append(attributeLocks, attributeLock(value.Code))
+		domainValues = append(domainValues, model.AttributeValue{
+			Code:  value.Code,
+			Value: value.Value,
+		})
+	}
+
+	slices.Sort(attributeLocks)
+	lockNames := append([]string{productLock(productID)}, slices.Compact(attributeLocks)...)
+
+	return s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).SetProductAttributes(productID, domainValues)
+	})
+}
+
+func (s *attributeService) domainService(ctx context.Context, provider RepositoryProvider) service.AttributeService {
+	eventDispatcher := &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	}
+	return service.NewAttributeService(
+		provider.AttributeDefinitionRepository(ctx),
+		service.NewProductService(provider.ProductRepository(ctx), eventDispatcher),
+	)
+}
+
+func attributeLock(code string) string {
+	return "attribute_" + model.NormalizeAttributeCode(code)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	"productservice/pkg/product/domain/service"
+)
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, name string, parentID *uuid.UUID) (uuid.UUID, error)
+	// UpdateCategory переименовывает категорию и переносит ее к parentID, nil делает категорию корневой
+	UpdateCategory(ctx context.Context, categoryID uuid.UUID, name string, parentID *uuid.UUID) error
+	DeleteCategory(ctx context.Context, categoryID uuid.UUID) error
+	// SetProductCategories заменяет набор категорий продукта
+	SetProductCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error
+}
+
+func NewCategoryService(
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+) CategoryService {
+	return &categoryService{
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+type categoryService struct {
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, name string, parentID *uuid.UUID) (uuid.UUID, error) {
+	var categoryID uuid.UUID
+	err := s.luow.Execute(ctx, []string{categoryTreeLock}, func(provider RepositoryProvider) error {
+		var err error
+		categoryID, err = s.domainService(ctx, provider).CreateCategory(name, parentID)
+		return err
+	})
+	return categoryID, err
+}
+
+func (s *categoryService) UpdateCategory(ctx context.Context, categoryID uuid.UUID, name string, parentID *uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{categoryTreeLock}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).UpdateCategory(categoryID, name, parentID)
+	})
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, categoryID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{categoryTreeLock}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).DeleteCategory(categoryID)
+	})
+}
+
+func (s *categoryService) SetProductCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{productLock(productID), categoryTreeLock}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).SetProductCategories(productID, categoryIDs)
+	})
+}
+
+func (s *categoryService) domainService(ctx context.Context, provider RepositoryProvider) service.CategoryService {
+	eventDispatcher := &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	}
+	return service.NewCategoryService(
+		provider.CategoryRepository(ctx),
+		eventDispatcher,
+		service.NewProductService(provider.ProductRepository(ctx), eventDispatcher),
+	)
+}
+
+// categoryTreeLock сериализует изменения дерева категорий, чтобы параллельные переносы не образовали цикл
+const categoryTreeLock = "category_tree"
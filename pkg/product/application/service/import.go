@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/domain/model"
+)
+
+var (
+	ErrImportKeyMissing = errors.New("import row has no key field")
+	ErrImportConflict   = errors.New("imported product changed concurrently")
+)
+
+// errDryRun откатывает транзакцию импорта строки в режиме dryRun
+var errDryRun = errors.New("dry run")
+
+func (s *productService) ImportProduct(ctx context.Context, product appmodel.ProductImport, key appmodel.ImportKey, dryRun bool) (appmodel.ImportResult, error) {
+	if key == appmodel.ImportByName && (product.Name == nil || model.NormalizeName(*product.Name) == "") {
+		return appmodel.ImportResult{}, fmt.Errorf("%w: name is required to import by name", ErrImportKeyMissing)
+	}
+	if key == appmodel.ImportBySKU && (product.SKU == nil || model.NormalizeSKU(*product.SKU) == "") {
+		return appmodel.ImportResult{}, fmt.Errorf("%w: sku is required to import by sku", ErrImportKeyMissing)
+	}
+
+	// Продукт ищется до захвата блокировок, чтобы заблокировать и его, и повторно под блокировками
+	var existingID uuid.UUID
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		existing, err := findImportedProduct(provider.ProductRepository(ctx), product, key)
+		if existing != nil {
+			existingID = existing.ProductID
+		}
+		return err
+	})
+	if err != nil {
+		return appmodel.ImportResult{}, err
+	}
+
+	var lockNames []string
+	if existingID != uuid.Nil {
+		lockNames = append(lockNames, productLock(existingID))
+	}
+	if product.Name != nil {
+		lockNames = append(lockNames, productNameLock(*product.Name))
+	}
+	lockNames = append(lockNames, identifierLocks(product.SKU, product.Barcodes)...)
+
+	result := appmodel.ImportResult{ProductID: existingID}
+	err = s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		productRepository := provider.ProductRepository(ctx)
+		existing, err := findImportedProduct(productRepository, product, key)
+		if err != nil {
+			return err
+		}
+		if existing == nil && existingID != uuid.Nil || existing != nil && existing.ProductID != existingID {
+			return ErrImportConflict
+		}
+
+		domainService := s.domainService(ctx, productRepository)
+		if existing == nil {
+			create := model.ProductCreate{}
+			if product.Name != nil {
+				create.Name = *product.Name
+			}
+			if product.Description != nil {
+				create.Description = *product.Description
+			}
+			if product.Prices != nil {
+				create.Prices = toDomainPrices(*product.Prices)
+			}
+			if product.SKU != nil {
+				create.SKU = *product.SKU
+			}
+			if product.Barcodes != nil {
+				create.Barcodes = *product.Barcodes
+			}
+			result.ProductID, err = domainService.CreateProduct(create)
+			result.Action = appmodel.ImportCreated
+		} else {
+			update := model.ProductUpdate{
+				Name:        product.Name,
+				Description: product.Description,
+				SKU:         product.SKU,
+				Barcodes:    product.Barcodes,
+			}
+			if product.Prices != nil {
+				prices := toDomainPrices(*product.Prices)
+				update.Prices = &prices
+			}
+			err = domainService.UpdateProduct(existing.ProductID, update, nil)
+			if err != nil {
+				return err
+			}
+			result.Action, err = importUpdateAction(productRepository, existing)
+		}
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+		if result.Action == appmodel.ImportCreated {
+			result.ProductID = uuid.Nil
+		}
+	}
+	if err != nil {
+		return appmodel.ImportResult{}, err
+	}
+	return result, nil
+}
+
+// findImportedProduct ищет продукт по ключу импорта, nil означает, что продукт нужно создать
+func findImportedProduct(productRepository model.ProductRepository, product appmodel.ProductImport, key appmodel.ImportKey) (*model.Product, error) {
+	spec := model.FindSpec{}
+	var sku string
+	switch key {
+	case appmodel.ImportByName:
+		name := model.NormalizeName(*product.Name)
+		spec.Name = &name
+	case appmodel.ImportBySKU:
+		sku = model.NormalizeSKU(*product.SKU)
+		spec.SKU = &sku
+	default:
+		return nil, fmt.Errorf("unknown import key %d", key)
+	}
+
+	existing, err := productRepository.Find(spec)
+	if errors.Is(err, model.ErrProductNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// FindSpec.SKU находит продукт и по SKU его варианта, такой SKU нельзя отдать продукту
+	if key == appmodel.ImportBySKU && existing.SKU != sku {
+		return nil, fmt.Errorf("%w: %q is used by a product variant", model.ErrSKUAlreadyUsed, sku)
+	}
+	return existing, nil
+}
+
+func importUpdateAction(productRepository model.ProductRepository, existing *model.Product) (appmodel.ImportAction, error) {
+	updated, err := productRepository.Find(model.FindSpec{ProductID: &existing.ProductID})
+	if err != nil {
+		return 0, err
+	}
+	if updated.Version == existing.Version {
+		return appmodel.ImportUnchanged, nil
+	}
+	return appmodel.ImportUpdated, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	// Декодеры нужны image.DecodeConfig для определения размеров загруженных изображений
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/domain/model"
+	"productservice/pkg/product/domain/service"
+)
+
+// MaxMediaContentSize ограничивает размер загружаемого файла медиа
+const MaxMediaContentSize = 8 << 20
+
+// MediaStorage хранилище загруженных файлов медиа
+type MediaStorage interface {
+	// Put сохраняет файл под ключом, существующий файл перезаписывается
+	Put(ctx context.Context, key, contentType string, content io.Reader) error
+	// Delete удаляет файл, отсутствующий файл не считается ошибкой
+	Delete(ctx context.Context, key string) error
+	// URL возвращает адрес, по которому файл доступен клиентам
+	URL(key string) string
+}
+
+type MediaService interface {
+	// AddMedia добавляет медиа по внешнему URL или загружает Content в хранилище
+	AddMedia(ctx context.Context, productID uuid.UUID, media appmodel.MediaCreate) (uuid.UUID, error)
+	ReorderMedia(ctx context.Context, productID uuid.UUID, mediaIDs []uuid.UUID) error
+	SetPrimaryMedia(ctx context.Context, productID, mediaID uuid.UUID) error
+	// RemoveMedia удаляет медиа и после фиксации изменений его файл из хранилища
+	RemoveMedia(ctx context.Context, productID, mediaID uuid.UUID) error
+}
+
+func NewMediaService(
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	storage MediaStorage,
+) MediaService {
+	return &mediaService{
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+		storage:         storage,
+	}
+}
+
+type mediaService struct {
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	storage         MediaStorage
+}
+
+func (s *mediaService) AddMedia(ctx context.Context, productID uuid.UUID, media appmodel.MediaCreate) (mediaID uuid.UUID, err error) {
+	domainMedia := model.MediaCreate{
+		URL:         media.URL,
+		AltText:     media.AltText,
+		Width:       media.Width,
+		Height:      media.Height,
+		ContentType: media.ContentType,
+		Primary:     media.Primary,
+	}
+	if len(media.Content) > 0 {
+		domainMedia, err = s.upload(ctx, productID, domainMedia, media.Content)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		defer func() {
+			// Файл без медиа в базе никому не нужен
+			if err != nil {
+				err = errors.Join(err, s.storage.Delete(ctx, domainMedia.StorageKey))
+			}
+		}()
+	}
+
+	err = s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		var err error
+		mediaID, err = s.domainService(ctx, provider).AddMedia(productID, domainMedia)
+		return err
+	})
+	return mediaID, err
+}
+
+func (s *mediaService) ReorderMedia(ctx context.Context, productID uuid.UUID, mediaIDs []uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).ReorderMedia(productID, mediaIDs)
+	})
+}
+
+func (s *mediaService) SetPrimaryMedia(ctx context.Context, productID, mediaID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).SetPrimaryMedia(productID, mediaID)
+	})
+}
+
+func (s *mediaService) RemoveMedia(ctx context.Context, productID, mediaID uuid.UUID) error {
+	var storageKey string
+	err := s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		product, err := provider.ProductRepository(ctx).Find(model.FindSpec{ProductID: &productID})
+		if err != nil {
+			return err
+		}
+		if media, ok := product.FindMedia(mediaID); ok {
+			storageKey = media.StorageKey
+		}
+		return s.domainService(ctx, provider).RemoveMedia(productID, mediaID)
+	})
+	if err != nil || storageKey == "" {
+		return err
+	}
+	return s.storage.Delete(ctx, storageKey)
+}
+
+// upload сохраняет содержимое в хранилище и дополняет медиа ключом, типом содержимого и размерами изображения
+func (s *mediaService) upload(ctx context.Context, productID uuid.UUID, media model.MediaCreate, content []byte) (model.MediaCreate, error) {
+	if media.URL != "" {
+		return media, fmt.Errorf("%w: url must not be set with content", model.ErrInvalidMedia)
+	}
+	if len(content) > MaxMediaContentSize {
+		return media, fmt.Errorf("%w: content must be at most %d bytes", model.ErrInvalidMedia, MaxMediaContentSize)
+	}
+	// Заявленный тип должен совпадать с содержимым, иначе под видом изображения можно загрузить исполняемый файл
+	detectedType, _, _ := mime.ParseMediaType(http.DetectContentType(content))
+	if media.ContentType == "" {
+		media.ContentType = detectedType
+	} else if declaredType, _, err := mime.ParseMediaType(media.ContentType); err != nil || !strings.EqualFold(declaredType, detectedType) {
+		return media, fmt.Errorf("%w: content type %q does not match content, detected %q", model.ErrInvalidMedia, media.ContentType, detectedType)
+	}
+	if media.Width == 0 && media.Height == 0 {
+		if config, _, err := image.DecodeConfig(bytes.NewReader(content)); err == nil {
+			media.Width, media.Height = config.Width, config.Height
+		}
+	}
+	// Проверяем до загрузки, чтобы не сохранять файл, который все равно будет отклонен
+	media.StorageKey = mediaStorageKey(productID, media.ContentType)
+	media = model.NormalizeMediaCreate(media)
+	err := model.ValidateMediaCreate(media)
+	if err != nil {
+		return media, err
+	}
+
+	return media, s.storage.Put(ctx, media.StorageKey, media.ContentType, bytes.NewReader(content))
+}
+
+func (s *mediaService) domainService(ctx context.Context, provider RepositoryProvider) service.ProductService {
+	return service.NewProductService(provider.ProductRepository(ctx), &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	})
+}
+
+// mediaStorageKey возвращает новый ключ вида <product_id>/<uuid><расширение>
+func mediaStorageKey(productID uuid.UUID, contentType string) string {
+	return productID.String() + "/" + uuid.NewString() + model.MediaExtension(contentType)
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
 	"github.com/google/uuid"
@@ -13,9 +15,31 @@ import (
 )
 
 type ProductService interface {
-	StoreProduct(ctx context.Context, product appmodel.Product) (uuid.UUID, error)
+	// StoreProduct создает продукт при нулевом productID, незаданные поля остаются пустыми,
+	// или обновляет существующий так же, как UpdateProduct: незаданные поля не меняются.
+	// Непустой expectedVersion включает оптимистичную проверку версии при обновлении
+	StoreProduct(ctx context.Context, productID uuid.UUID, product appmodel.ProductUpdate, expectedVersion *int64) (uuid.UUID, error)
+	UpdateProduct(ctx context.Context, productID uuid.UUID, update appmodel.ProductUpdate, expectedVersion *int64) error
+	DeleteProduct(ctx context.Context, productID uuid.UUID) error
+	RestoreProduct(ctx context.Context, productID uuid.UUID) error
+	PublishProduct(ctx context.Context, productID uuid.UUID) error
+	ArchiveProduct(ctx context.Context, productID uuid.UUID) error
+	SchedulePriceChange(ctx context.Context, productID uuid.UUID, price appmodel.Money, effectiveFrom time.Time) (uuid.UUID, error)
+	// ApplyScheduledPriceChanges применяет наступившие изменения цен, возвращает число обновленных продуктов.
+	// Ошибка одного продукта не мешает обработке остальных
+	ApplyScheduledPriceChanges(ctx context.Context) (int, error)
+	// ImportProduct создает продукт или обновляет найденный по key, каждая строка импортируется в своей транзакции.
+	// В режиме dryRun выполняются все проверки, но изменения и события откатываются
+	ImportProduct(ctx context.Context, product appmodel.ProductImport, key appmodel.ImportKey, dryRun bool) (appmodel.ImportResult, error)
+
+	AddVariant(ctx context.Context, productID uuid.UUID, variant appmodel.Variant) (uuid.UUID, error)
+	UpdateVariant(ctx context.Context, productID, variantID uuid.UUID, update appmodel.VariantUpdate) error
+	RemoveVariant(ctx context.Context, productID, variantID uuid.UUID) error
 }
 
+// priceChangeBatchSize ограничивает число продуктов, обрабатываемых за один вызов ApplyScheduledPriceChanges
+const priceChangeBatchSize = 100
+
 func NewProductService(
 	uow UnitOfWork,
 	luow LockableUnitOfWork,
@@ -34,26 +58,150 @@ type productService struct {
 	eventDispatcher outbox.EventDispatcher[outbox.Event]
 }
 
-func (s *productService) StoreProduct(ctx context.Context, product appmodel.Product) (uuid.UUID, error) {
-	var lockNames []string
-	if product.ProductID != uuid.Nil {
-		lockNames = append(lockNames, productLock(product.ProductID))
+func (s *productService) StoreProduct(ctx context.Context, productID uuid.UUID, product appmodel.ProductUpdate, expectedVersion *int64) (uuid.UUID, error) {
+	if productID != uuid.Nil {
+		return productID, s.UpdateProduct(ctx, productID, product, expectedVersion)
 	}
-	lockNames = append(lockNames, productNameLock(product.Name))
 
-	productID := product.ProductID
+	create := model.ProductCreate{}
+	if product.Name != nil {
+		create.Name = *product.Name
+	}
+	if product.Description != nil {
+		create.Description = *product.Description
+	}
+	if product.Translations != nil {
+		create.Translations = toDomainTranslations(*product.Translations)
+	}
+	if product.Prices != nil {
+		create.Prices = toDomainPrices(*product.Prices)
+	} else if product.DefaultCurrencyPrice != nil {
+		create.Prices = withDefaultCurrencyPrice(nil, *product.DefaultCurrencyPrice)
+	}
+	if product.SKU != nil {
+		create.SKU = *product.SKU
+	}
+	if product.Barcodes != nil {
+		create.Barcodes = *product.Barcodes
+	}
+
+	lockNames := append([]string{productNameLock(create.Name)}, identifierLocks(product.SKU, product.Barcodes)...)
 	err := s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
-		domainService := s.domainService(ctx, provider.ProductRepository(ctx))
 		var err error
-		if product.ProductID == uuid.Nil {
-			productID, err = domainService.CreateProduct(product.Name, product.Price)
-			return err
+		productID, err = s.domainService(ctx, provider.ProductRepository(ctx)).CreateProduct(create)
+		return err
+	})
+	return productID, err
+}
+
+func (s *productService) UpdateProduct(ctx context.Context, productID uuid.UUID, update appmodel.ProductUpdate, expectedVersion *int64) error {
+	lockNames := []string{productLock(productID)}
+	if update.Name != nil {
+		lockNames = append(lockNames, productNameLock(*update.Name))
+	}
+	lockNames = append(lockNames, identifierLocks(update.SKU, update.Barcodes)...)
+
+	return s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		domainService := s.domainService(ctx, provider.ProductRepository(ctx))
+		domainUpdate := model.ProductUpdate{
+			Name:        update.Name,
+			Description: update.Description,
+			SKU:         update.SKU,
+			Barcodes:    update.Barcodes,
+		}
+		if update.Translations != nil {
+			translations := toDomainTranslations(*update.Translations)
+			domainUpdate.Translations = &translations
 		}
+		if update.Prices != nil {
+			prices := toDomainPrices(*update.Prices)
+			domainUpdate.Prices = &prices
+		} else if update.DefaultCurrencyPrice != nil {
+			product, err := provider.ProductRepository(ctx).Find(model.FindSpec{ProductID: &productID})
+			if err != nil {
+				return err
+			}
+			prices := withDefaultCurrencyPrice(product.Prices, *update.DefaultCurrencyPrice)
+			domainUpdate.Prices = &prices
+		}
+		return domainService.UpdateProduct(productID, domainUpdate, expectedVersion)
+	})
+}
+
+func (s *productService) DeleteProduct(ctx context.Context, productID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		domainService := s.domainService(ctx, provider.ProductRepository(ctx))
+		return domainService.DeleteProduct(productID)
+	})
+}
+
+func (s *productService) RestoreProduct(ctx context.Context, productID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		domainService := s.domainService(ctx, provider.ProductRepository(ctx))
+		return domainService.RestoreProduct(productID)
+	})
+}
+
+func (s *productService) PublishProduct(ctx context.Context, productID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		domainService := s.domainService(ctx, provider.ProductRepository(ctx))
+		return domainService.PublishProduct(productID)
+	})
+}
+
+func (s *productService) ArchiveProduct(ctx context.Context, productID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		domainService := s.domainService(ctx, provider.ProductRepository(ctx))
+		return domainService.ArchiveProduct(productID)
+	})
+}
 
-		err = domainService.UpdateProduct(productID, product.Name, product.Price)
+func (s *productService) SchedulePriceChange(ctx context.Context, productID uuid.UUID, price appmodel.Money, effectiveFrom time.Time) (uuid.UUID, error) {
+	var changeID uuid.UUID
+	err := s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		var err error
+		changeID, err = s.priceChangeService(ctx, provider).SchedulePriceChange(productID, model.Money{
+			Amount:   price.Amount,
+			Currency: price.Currency,
+		}, effectiveFrom)
 		return err
 	})
-	return productID, err
+	return changeID, err
+}
+
+func (s *productService) ApplyScheduledPriceChanges(ctx context.Context) (int, error) {
+	var productIDs []uuid.UUID
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		productIDs, err = provider.ScheduledPriceChangeRepository(ctx).FindDueProductIDs(time.Now(), priceChangeBatchSize)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	var errs []error
+	for _, productID := range productIDs {
+		err = s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+			return s.priceChangeService(ctx, provider).ApplyDuePriceChanges(productID)
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		applied++
+	}
+	return applied, errors.Join(errs...)
+}
+
+func (s *productService) priceChangeService(ctx context.Context, provider RepositoryProvider) service.PriceChangeService {
+	productRepository := provider.ProductRepository(ctx)
+	return service.NewPriceChangeService(
+		productRepository,
+		provider.ScheduledPriceChangeRepository(ctx),
+		s.domainService(ctx, productRepository),
+	)
 }
 
 func (s *productService) domainService(ctx context.Context, repository model.ProductRepository) service.ProductService {
@@ -67,12 +215,61 @@ func (s *productService) domainEventDispatcher(ctx context.Context) domain.Event
 	}
 }
 
+func toDomainPrices(prices []appmodel.Money) []model.Money {
+	result := make([]model.Money, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, model.Money{
+			Amount:   price.Amount,
+			Currency: price.Currency,
+		})
+	}
+	return result
+}
+
+// withDefaultCurrencyPrice возвращает копию цен, в которой цена в валюте по умолчанию заменена на amount
+func withDefaultCurrencyPrice(prices []model.Money, amount int64) []model.Money {
+	result := make([]model.Money, 0, len(prices)+1)
+	for _, price := range prices {
+		if price.Currency != model.DefaultCurrency {
+			result = append(result, price)
+		}
+	}
+	return append(result, model.Money{Amount: amount, Currency: model.DefaultCurrency})
+}
+
+func toDomainTranslations(translations []appmodel.Translation) []model.Translation {
+	result := make([]model.Translation, 0, len(translations))
+	for _, translation := range translations {
+		result = append(result, model.Translation{
+			Locale:      translation.Locale,
+			Name:        translation.Name,
+			Description: translation.Description,
+		})
+	}
+	return result
+}
+
 const baseProductLock = "product_"
 
 func productLock(id uuid.UUID) string {
 	return baseProductLock + id.String()
 }
 
+// productNameLock блокирует имя с точностью до правил сравнения, по которым проверяется уникальность
 func productNameLock(name string) string {
-	return baseProductLock + "name_" + name
+	return baseProductLock + "name_" + model.NameKey(name)
+}
+
+// identifierLocks блокирует SKU и штрихкоды, чтобы параллельные запросы не заняли их дважды
+func identifierLocks(sku *string, barcodes *[]string) []string {
+	var lockNames []string
+	if sku != nil && model.NormalizeSKU(*sku) != "" {
+		lockNames = append(lockNames, baseProductLock+"sku_"+model.NormalizeSKU(*sku))
+	}
+	if barcodes != nil {
+		for _, barcode := range model.NormalizeBarcodes(*barcodes) {
+			lockNames = append(lockNames, baseProductLock+"barcode_"+barcode)
+		}
+	}
+	return lockNames
 }
@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/application/service"
+	"productservice/pkg/product/domain/model"
+)
+
+// --- Mocks ---
+type mockProductRepository struct {
+	products map[uuid.UUID]model.Product
+}
+
+func (m *mockProductRepository) NextID() (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+func (m *mockProductRepository) Store(product model.Product) error {
+	m.products[product.ProductID] = product
+	return nil
+}
+
+func (m *mockProductRepository) Find(spec model.FindSpec) (*model.Product, error) {
+	for _, p := range m.products {
+		if spec.ProductID != nil && p.ProductID != *spec.ProductID ||
+			spec.Name != nil && p.Name != *spec.Name ||
+			spec.SKU != nil && p.SKU != *spec.SKU ||
+			spec.Barcode != nil {
+			continue
+		}
+		return &p, nil
+	}
+	return nil, model.ErrProductNotFound
+}
+
+func (m *mockProductRepository) PurgeDeleted(deletedBefore time.Time) (int64, []string, error) {
+	var (
+		count       int64
+		storageKeys []string
+	)
+	for id, p := range m.products {
+		if p.DeletedAt != nil && p.DeletedAt.Before(deletedBefore) {
+			for _, media := range p.Media {
+				if media.StorageKey != "" {
+					storageKeys = append(storageKeys, media.StorageKey)
+				}
+			}
+			delete(m.products, id)
+			count++
+		}
+	}
+	return count, storageKeys, nil
+}
+
+type mockRepositoryProvider struct {
+	productRepository *mockProductRepository
+}
+
+func (m mockRepositoryProvider) ProductRepository(context.Context) model.ProductRepository {
+	return m.productRepository
+}
+
+func (m mockRepositoryProvider) CategoryRepository(context.Context) model.CategoryRepository {
+	return nil
+}
+
+func (m mockRepositoryProvider) AttributeDefinitionRepository(context.Context) model.AttributeDefinitionRepository {
+	return nil
+}
+
+func (m mockRepositoryProvider) ScheduledPriceChangeRepository(context.Context) model.ScheduledPriceChangeRepository {
+	return nil
+}
+
+type mockLockableUnitOfWork struct {
+	provider mockRepositoryProvider
+}
+
+func (m mockLockableUnitOfWork) Execute(_ context.Context, _ []string, f func(provider service.RepositoryProvider) error) error {
+	return f(m.provider)
+}
+
+type mockEventDispatcher struct{}
+
+func (m mockEventDispatcher) Dispatch(context.Context, outbox.Event) error {
+	return nil
+}
+
+// --- Tests ---
+
+func TestProductService_StoreProduct_KeepsUnsetFields(t *testing.T) {
+	// Arrange
+	repo := &mockProductRepository{products: make(map[uuid.UUID]model.Product)}
+	productID := uuid.New()
+	repo.products[productID] = model.Product{
+		ProductID:    productID,
+		Name:         "Coffee",
+		Description:  "Arabica",
+		Translations: []model.Translation{{Locale: "en", Name: "Coffee"}},
+		Prices:       []model.Money{{Amount: 300, Currency: "RUB"}, {Amount: 4, Currency: "USD"}},
+		SKU:          "COF-1",
+		Barcodes:     []string{"4006381333931"},
+		Version:      1,
+	}
+	luow := mockLockableUnitOfWork{provider: mockRepositoryProvider{productRepository: repo}}
+	productService := service.NewProductService(nil, luow, mockEventDispatcher{})
+
+	// Act
+	// Так сохраняют клиенты, которые знают только имя и цену
+	name := "Coffee Beans"
+	price := int64(350)
+	_, err := productService.StoreProduct(context.Background(), productID, appmodel.ProductUpdate{
+		Name:                 &name,
+		DefaultCurrencyPrice: &price,
+	}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	product := repo.products[productID]
+	assert.Equal(t, "Coffee Beans", product.Name)
+	assert.Equal(t, "Arabica", product.Description)
+	assert.Equal(t, []model.Translation{{Locale: "en", Name: "Coffee"}}, product.Translations)
+	assert.Equal(t, []model.Money{{Amount: 350, Currency: "RUB"}, {Amount: 4, Currency: "USD"}}, product.Prices)
+	assert.Equal(t, "COF-1", product.SKU)
+	assert.Equal(t, []string{"4006381333931"}, product.Barcodes)
+}
+
+func TestProductService_StoreProduct_CreatesWithDefaultCurrencyPrice(t *testing.T) {
+	// Arrange
+	repo := &mockProductRepository{products: make(map[uuid.UUID]model.Product)}
+	luow := mockLockableUnitOfWork{provider: mockRepositoryProvider{productRepository: repo}}
+	productService := service.NewProductService(nil, luow, mockEventDispatcher{})
+
+	// Act
+	name := "Tea"
+	price := int64(120)
+	productID, err := productService.StoreProduct(context.Background(), uuid.Nil, appmodel.ProductUpdate{
+		Name:                 &name,
+		DefaultCurrencyPrice: &price,
+	}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	product := repo.products[productID]
+	assert.Equal(t, "Tea", product.Name)
+	assert.Equal(t, []model.Money{{Amount: 120, Currency: "RUB"}}, product.Prices)
+	assert.Empty(t, product.SKU)
+	assert.Empty(t, product.Barcodes)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// PurgeService окончательно удаляет продукты, удаленные дольше срока хранения
+type PurgeService interface {
+	// PurgeDeletedProducts удаляет продукты, которые удалены дольше retention, и после фиксации изменений
+	// файлы их медиа из хранилища
+	PurgeDeletedProducts(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+func NewPurgeService(uow UnitOfWork, storage MediaStorage) PurgeService {
+	return &purgeService{
+		uow:     uow,
+		storage: storage,
+	}
+}
+
+type purgeService struct {
+	uow     UnitOfWork
+	storage MediaStorage
+}
+
+func (s *purgeService) PurgeDeletedProducts(ctx context.Context, retention time.Duration) (int64, error) {
+	var (
+		count       int64
+		storageKeys []string
+	)
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		count, storageKeys, err = provider.ProductRepository(ctx).PurgeDeleted(time.Now().Add(-retention))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Неудаленный файл уже ни на что не ссылается, поэтому удаляются все файлы, а ошибки собираются вместе
+	var errs []error
+	for _, storageKey := range storageKeys {
+		errs = append(errs, s.storage.Delete(ctx, storageKey))
+	}
+	return count, errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/product/application/service"
+	"productservice/pkg/product/domain/model"
+)
+
+// --- Mocks ---
+type mockUnitOfWork struct {
+	provider mockRepositoryProvider
+}
+
+func (m mockUnitOfWork) Execute(_ context.Context, f func(provider service.RepositoryProvider) error) error {
+	return f(m.provider)
+}
+
+type mockMediaStorage struct {
+	deletedKeys []string
+}
+
+func (m *mockMediaStorage) Put(context.Context, string, string, io.Reader) error {
+	return nil
+}
+
+func (m *mockMediaStorage) Delete(_ context.Context, key string) error {
+	m.deletedKeys = append(m.deletedKeys, key)
+	return nil
+}
+
+func (m *mockMediaStorage) URL(key string) string {
+	return "/media/" + key
+}
+
+// --- Tests ---
+
+func TestPurgeService_PurgeDeletedProducts_DeletesMediaFiles(t *testing.T) {
+	// Arrange
+	repo := &mockProductRepository{products: make(map[uuid.UUID]model.Product)}
+	deletedAt := time.Now().Add(-48 * time.Hour)
+	purgedID := uuid.New()
+	repo.products[purgedID] = model.Product{
+		ProductID: purgedID,
+		Name:      "Coffee",
+		Media: []model.Media{
+			{MediaID: uuid.New(), StorageKey: purgedID.String() + "/a.jpg"},
+			{MediaID: uuid.New(), URL: "https://example.com/b.jpg"},
+		},
+		DeletedAt: &deletedAt,
+	}
+	keptID := uuid.New()
+	repo.products[keptID] = model.Product{
+		ProductID: keptID,
+		Name:      "Tea",
+		Media:     []model.Media{{MediaID: uuid.New(), StorageKey: keptID.String() + "/c.jpg"}},
+	}
+	mediaStorage := &mockMediaStorage{}
+	purgeService := service.NewPurgeService(mockUnitOfWork{provider: mockRepositoryProvider{productRepository: repo}}, mediaStorage)
+
+	// Act
+	count, err := purgeService.PurgeDeletedProducts(context.Background(), 24*time.Hour)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, []string{purgedID.String() + "/a.jpg"}, mediaStorage.deletedKeys)
+	assert.Contains(t, repo.products, keptID)
+}
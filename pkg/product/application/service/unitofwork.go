@@ -8,6 +8,9 @@ import (
 
 type RepositoryProvider interface {
 	ProductRepository(ctx context.Context) model.ProductRepository
+	CategoryRepository(ctx context.Context) model.CategoryRepository
+	AttributeDefinitionRepository(ctx context.Context) model.AttributeDefinitionRepository
+	ScheduledPriceChangeRepository(ctx context.Context) model.ScheduledPriceChangeRepository
 }
 
 type LockableUnitOfWork interface {
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/domain/model"
+)
+
+func (s *productService) AddVariant(ctx context.Context, productID uuid.UUID, variant appmodel.Variant) (uuid.UUID, error) {
+	lockNames := append([]string{productLock(productID)}, identifierLocks(&variant.SKU, nil)...)
+	var variantID uuid.UUID
+	err := s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		var err error
+		variantID, err = s.domainService(ctx, provider.ProductRepository(ctx)).AddVariant(productID, model.VariantCreate{
+			Options: toDomainVariantOptions(variant.Options),
+			Prices:  toDomainPrices(variant.Prices),
+			SKU:     variant.SKU,
+		})
+		return err
+	})
+	return variantID, err
+}
+
+func (s *productService) UpdateVariant(ctx context.Context, productID, variantID uuid.UUID, update appmodel.VariantUpdate) error {
+	lockNames := append([]string{productLock(productID)}, identifierLocks(update.SKU, nil)...)
+	return s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		domainUpdate := model.VariantUpdate{
+			SKU: update.SKU,
+		}
+		if update.Options != nil {
+			options := toDomainVariantOptions(*update.Options)
+			domainUpdate.Options = &options
+		}
+		if update.Prices != nil {
+			prices := toDomainPrices(*update.Prices)
+			domainUpdate.Prices = &prices
+		}
+		return s.domainService(ctx, provider.ProductRepository(ctx)).UpdateVariant(productID, variantID, domainUpdate)
+	})
+}
+
+func (s *productService) RemoveVariant(ctx context.Context, productID, variantID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{productLock(productID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider.ProductRepository(ctx)).RemoveVariant(productID, variantID)
+	})
+}
+
+func toDomainVariantOptions(options []appmodel.VariantOption) []model.VariantOption {
+	result := make([]model.VariantOption, 0, len(options))
+	for _, option := range options {
+		result = append(result, model.VariantOption{
+			Name:  option.Name,
+			Value: option.Value,
+		})
+	}
+	return result
+}
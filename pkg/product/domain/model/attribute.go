@@ -0,0 +1,187 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrAttributeNotFound            = errors.New("attribute definition not found")
+	ErrAttributeAlreadyExists       = errors.New("attribute definition already exists")
+	ErrAttributeInUse               = errors.New("attribute is used by products")
+	ErrInvalidAttributeDefinition   = errors.New("invalid attribute definition")
+	ErrInvalidAttributeValue        = errors.New("invalid attribute value")
+	errAttributeValueDuplicateCode  = errors.New("duplicate attribute code")
+	errAttributeValueUnknownEnumVal = errors.New("value is not one of enum values")
+)
+
+type AttributeType int
+
+const (
+	AttributeTypeString AttributeType = iota
+	AttributeTypeNumber
+	AttributeTypeBool
+	AttributeTypeEnum
+)
+
+var attributeTypeNames = map[AttributeType]string{
+	AttributeTypeString: "string",
+	AttributeTypeNumber: "number",
+	AttributeTypeBool:   "bool",
+	AttributeTypeEnum:   "enum",
+}
+
+func (t AttributeType) String() string {
+	if name, ok := attributeTypeNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func ParseAttributeType(name string) (AttributeType, error) {
+	for attributeType, typeName := range attributeTypeNames {
+		if typeName == name {
+			return attributeType, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown type %q", ErrInvalidAttributeDefinition, name)
+}
+
+const (
+	// MaxAttributeCodeLength длина колонки code VARCHAR(64)
+	MaxAttributeCodeLength = 64
+	// MaxAttributeValueLength длина колонки value VARCHAR(255) в символах
+	MaxAttributeValueLength = 255
+)
+
+// AttributeDefinition описывает атрибут продукта, Code неизменяемый ключ атрибута
+type AttributeDefinition struct {
+	Code string
+	Name string
+	Type AttributeType
+	// EnumValues допустимые значения атрибута типа AttributeTypeEnum
+	EnumValues []string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// AttributeValue значение атрибута продукта в каноническом строковом виде
+type AttributeValue struct {
+	Code  string
+	Value string
+}
+
+type AttributeDefinitionRepository interface {
+	Store(definition AttributeDefinition) error
+	Find(code string) (*AttributeDefinition, error)
+	Delete(code string) error
+	// IsUsed проверяет, есть ли продукты со значением атрибута, для nil value - с любым значением
+	IsUsed(code string, value *string) (bool, error)
+}
+
+// NormalizeAttributeCode убирает пробелы по краям и приводит код к нижнему регистру
+func NormalizeAttributeCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// ValidateAttributeDefinition проверяет уже нормализованное определение атрибута
+func ValidateAttributeDefinition(definition AttributeDefinition) error {
+	if definition.Code == "" || len(definition.Code) > MaxAttributeCodeLength {
+		return fmt.Errorf("%w: code must be 1 to %d characters", ErrInvalidAttributeDefinition, MaxAttributeCodeLength)
+	}
+	for _, r := range definition.Code {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return fmt.Errorf("%w: code must contain only latin letters, digits and '_'", ErrInvalidAttributeDefinition)
+		}
+	}
+	err := ValidateName(definition.Name)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAttributeDefinition, err)
+	}
+
+	switch definition.Type {
+	case AttributeTypeString, AttributeTypeNumber, AttributeTypeBool:
+		if len(definition.EnumValues) != 0 {
+			return fmt.Errorf("%w: enum values are allowed only for enum attributes", ErrInvalidAttributeDefinition)
+		}
+	case AttributeTypeEnum:
+		if len(definition.EnumValues) == 0 {
+			return fmt.Errorf("%w: enum attribute must have at least one value", ErrInvalidAttributeDefinition)
+		}
+		for i, value := range definition.EnumValues {
+			if err := validateStringValue(value); err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidAttributeDefinition, err)
+			}
+			if slices.Contains(definition.EnumValues[:i], value) {
+				return fmt.Errorf("%w: duplicate enum value %q", ErrInvalidAttributeDefinition, value)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %d", ErrInvalidAttributeDefinition, definition.Type)
+	}
+	return nil
+}
+
+// NormalizeAttributeValue проверяет значение по определению атрибута и приводит его к каноническому виду:
+// числа без лишних нулей, bool как "true" или "false"
+func NormalizeAttributeValue(definition AttributeDefinition, value string) (string, error) {
+	value = NormalizeName(value)
+	switch definition.Type {
+	case AttributeTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", fmt.Errorf("%w: %s must be a finite number, got %q", ErrInvalidAttributeValue, definition.Code, value)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case AttributeTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a boolean, got %q", ErrInvalidAttributeValue, definition.Code, value)
+		}
+		return strconv.FormatBool(b), nil
+	case AttributeTypeEnum:
+		if !slices.Contains(definition.EnumValues, value) {
+			return "", fmt.Errorf("%w: %s: %w %q", ErrInvalidAttributeValue, definition.Code, errAttributeValueUnknownEnumVal, value)
+		}
+		return value, nil
+	default:
+		if err := validateStringValue(value); err != nil {
+			return "", fmt.Errorf("%w: %s: %w", ErrInvalidAttributeValue, definition.Code, err)
+		}
+		return value, nil
+	}
+}
+
+// SortAttributeValues сортирует значения по коду и проверяет, что коды не повторяются
+func SortAttributeValues(values []AttributeValue) ([]AttributeValue, error) {
+	result := slices.Clone(values)
+	slices.SortFunc(result, func(l, r AttributeValue) int {
+		return strings.Compare(l.Code, r.Code)
+	})
+	for i := 1; i < len(result); i++ {
+		if result[i-1].Code == result[i].Code {
+			return nil, fmt.Errorf("%w: %w %q", ErrInvalidAttributeValue, errAttributeValueDuplicateCode, result[i].Code)
+		}
+	}
+	return result, nil
+}
+
+func validateStringValue(value string) error {
+	if !utf8.ValidString(value) {
+		return errors.New("value must be valid UTF-8")
+	}
+	if value == "" || utf8.RuneCountInString(value) > MaxAttributeValueLength {
+		return fmt.Errorf("value must be 1 to %d characters", MaxAttributeValueLength)
+	}
+	if strings.IndexFunc(value, unicode.IsControl) != -1 {
+		return errors.New("value must not contain control characters")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrInvalidCategoryParent = errors.New("invalid category parent")
+	ErrCategoryNotEmpty      = errors.New("category has subcategories or products")
+)
+
+// Category узел дерева категорий, корневые категории не имеют ParentID
+type Category struct {
+	CategoryID uuid.UUID
+	ParentID   *uuid.UUID
+	Name       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type CategoryRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(category Category) error
+	Find(categoryID uuid.UUID) (*Category, error)
+	Delete(categoryID uuid.UUID) error
+	HasChildren(categoryID uuid.UUID) (bool, error)
+	// HasProducts учитывает в том числе удаленные продукты, чтобы их можно было восстановить вместе с категориями
+	HasProducts(categoryID uuid.UUID) (bool, error)
+}
+
+// NormalizeCategoryIDs убирает повторы и сортирует идентификаторы категорий
+func NormalizeCategoryIDs(categoryIDs []uuid.UUID) []uuid.UUID {
+	result := slices.Clone(categoryIDs)
+	slices.SortFunc(result, func(l, r uuid.UUID) int {
+		return bytes.Compare(l[:], r[:])
+	})
+	return slices.Compact(result)
+}
@@ -8,23 +8,46 @@ import (
 
 // ProductCreated событие о создании продукта
 type ProductCreated struct {
-	ProductID uuid.UUID
-	Name      string
-	Price     int64 // Цена в копейках
-	CreatedAt time.Time
+	ProductID    uuid.UUID
+	Name         string
+	Description  string
+	Translations []Translation
+	Prices       []Money
+	SKU          string
+	Barcodes     []string
+	Status       ProductStatus
+	CreatedAt    time.Time
 }
 
 func (e ProductCreated) Type() string {
 	return "product_created"
 }
 
-// ProductUpdated событие об обновлении продукта
+// ProductUpdated событие об обновлении продукта, содержит только изменившиеся поля
 type ProductUpdated struct {
 	ProductID     uuid.UUID
 	UpdatedFields struct {
-		Name  *string
-		Price *int64
+		Name         *string
+		Description  *string
+		Translations *[]Translation
+		Prices       *[]Money
+		SKU          *string
+		Barcodes     *[]string
+		CategoryIDs  *[]uuid.UUID
+		Attributes   *[]AttributeValue
+	}
+	// PreviousFields значения изменившихся полей до обновления
+	PreviousFields struct {
+		Name         *string
+		Description  *string
+		Translations *[]Translation
+		Prices       *[]Money
+		SKU          *string
+		Barcodes     *[]string
+		CategoryIDs  *[]uuid.UUID
+		Attributes   *[]AttributeValue
 	}
+	Version   int64
 	UpdatedAt time.Time
 }
 
@@ -41,3 +64,157 @@ type ProductDeleted struct {
 func (e ProductDeleted) Type() string {
 	return "product_deleted"
 }
+
+// ProductRestored событие о восстановлении удаленного продукта
+type ProductRestored struct {
+	ProductID  uuid.UUID
+	RestoredAt time.Time
+}
+
+func (e ProductRestored) Type() string {
+	return "product_restored"
+}
+
+// ProductPublished событие о переводе продукта в статус active из черновика или архива
+type ProductPublished struct {
+	ProductID      uuid.UUID
+	PreviousStatus ProductStatus
+	Version        int64
+	PublishedAt    time.Time
+}
+
+func (e ProductPublished) Type() string {
+	return "product_published"
+}
+
+// ProductArchived событие о переводе активного продукта в архив
+type ProductArchived struct {
+	ProductID  uuid.UUID
+	Version    int64
+	ArchivedAt time.Time
+}
+
+func (e ProductArchived) Type() string {
+	return "product_archived"
+}
+
+// ProductVariantAdded событие о добавлении варианта продукта
+type ProductVariantAdded struct {
+	ProductID uuid.UUID
+	Variant   Variant
+	Version   int64
+	AddedAt   time.Time
+}
+
+func (e ProductVariantAdded) Type() string {
+	return "product_variant_added"
+}
+
+// ProductVariantUpdated событие об изменении варианта, содержит вариант после изменения
+type ProductVariantUpdated struct {
+	ProductID uuid.UUID
+	Variant   Variant
+	Version   int64
+	UpdatedAt time.Time
+}
+
+func (e ProductVariantUpdated) Type() string {
+	return "product_variant_updated"
+}
+
+// ProductVariantRemoved событие об удалении варианта продукта
+type ProductVariantRemoved struct {
+	ProductID uuid.UUID
+	VariantID uuid.UUID
+	Version   int64
+	RemovedAt time.Time
+}
+
+func (e ProductVariantRemoved) Type() string {
+	return "product_variant_removed"
+}
+
+// ProductMediaAdded событие о добавлении медиа в конец списка продукта.
+// Если Media.Primary, остальные медиа продукта перестали быть основными
+type ProductMediaAdded struct {
+	ProductID uuid.UUID
+	Media     Media
+	Version   int64
+	AddedAt   time.Time
+}
+
+func (e ProductMediaAdded) Type() string {
+	return "product_media_added"
+}
+
+// ProductMediaReordered событие об изменении порядка медиа, MediaIDs содержит все медиа продукта в новом порядке
+type ProductMediaReordered struct {
+	ProductID   uuid.UUID
+	MediaIDs    []uuid.UUID
+	Version     int64
+	ReorderedAt time.Time
+}
+
+func (e ProductMediaReordered) Type() string {
+	return "product_media_reordered"
+}
+
+// ProductMediaPrimaryChanged событие о смене основного медиа продукта
+type ProductMediaPrimaryChanged struct {
+	ProductID uuid.UUID
+	MediaID   uuid.UUID
+	Version   int64
+	ChangedAt time.Time
+}
+
+func (e ProductMediaPrimaryChanged) Type() string {
+	return "product_media_primary_changed"
+}
+
+// ProductMediaRemoved событие об удалении медиа. PrimaryMediaID задан, если удалено основное медиа
+// и основным стало первое из оставшихся
+type ProductMediaRemoved struct {
+	ProductID      uuid.UUID
+	MediaID        uuid.UUID
+	PrimaryMediaID *uuid.UUID
+	Version        int64
+	RemovedAt      time.Time
+}
+
+func (e ProductMediaRemoved) Type() string {
+	return "product_media_removed"
+}
+
+// CategoryCreated событие о создании категории
+type CategoryCreated struct {
+	CategoryID uuid.UUID
+	ParentID   *uuid.UUID
+	Name       string
+	CreatedAt  time.Time
+}
+
+func (e CategoryCreated) Type() string {
+	return "category_created"
+}
+
+// CategoryUpdated событие об изменении категории, содержит ее состояние после изменения
+type CategoryUpdated struct {
+	CategoryID uuid.UUID
+	ParentID   *uuid.UUID
+	Name       string
+	UpdatedAt  time.Time
+}
+
+func (e CategoryUpdated) Type() string {
+	return "category_updated"
+}
+
+// CategoryDeleted событие об удалении категории
+type CategoryDeleted struct {
+	CategoryID uuid.UUID
+	DeletedAt  time.Time
+}
+
+func (e CategoryDeleted) Type() string {
+	return "category_deleted"
+}
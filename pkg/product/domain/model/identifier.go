@@ -0,0 +1,103 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidSKU           = errors.New("invalid product sku")
+	ErrInvalidBarcode       = errors.New("invalid product barcode")
+	ErrSKUAlreadyUsed       = errors.New("product sku already used")
+	ErrBarcodeAlreadyUsed   = errors.New("product barcode already used")
+	ErrTooManyBarcodes      = errors.New("too many product barcodes")
+	errBarcodeCheckDigit    = errors.New("check digit mismatch")
+	errBarcodeUnknownFormat = errors.New("must be EAN-13 or UPC-A")
+)
+
+const (
+	// MaxSKULength длина колонки sku VARCHAR(64)
+	MaxSKULength = 64
+	// MaxBarcodes ограничивает число штрихкодов одного продукта
+	MaxBarcodes = 32
+
+	ean13Length = 13
+	upcALength  = 12
+)
+
+// NormalizeSKU убирает пробелы по краям и приводит SKU к верхнему регистру, пустая строка означает отсутствие SKU
+func NormalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
+
+// ValidateSKU проверяет уже нормализованный SKU: латинские буквы, цифры, '-', '_' и '.'
+func ValidateSKU(sku string) error {
+	if len(sku) > MaxSKULength {
+		return fmt.Errorf("%w: must be at most %d characters, got %d", ErrInvalidSKU, MaxSKULength, len(sku))
+	}
+	for _, r := range sku {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' && r != '.' {
+			return fmt.Errorf("%w: unexpected character %q", ErrInvalidSKU, r)
+		}
+	}
+	return nil
+}
+
+// NormalizeBarcode убирает пробелы по краям и дополняет UPC-A ведущим нулем до EAN-13,
+// чтобы один и тот же товар находился по обоим вариантам штрихкода
+func NormalizeBarcode(barcode string) string {
+	barcode = strings.TrimSpace(barcode)
+	if len(barcode) == upcALength {
+		return "0" + barcode
+	}
+	return barcode
+}
+
+// NormalizeBarcodes нормализует штрихкоды, убирает повторы и сортирует их
+func NormalizeBarcodes(barcodes []string) []string {
+	result := make([]string, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		result = append(result, NormalizeBarcode(barcode))
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// ValidateBarcode проверяет длину и контрольную цифру уже нормализованного штрихкода
+func ValidateBarcode(barcode string) error {
+	if len(barcode) != ean13Length {
+		return fmt.Errorf("%w %q: %w", ErrInvalidBarcode, barcode, errBarcodeUnknownFormat)
+	}
+	sum := 0
+	for i := 0; i < ean13Length; i++ {
+		digit := int(barcode[i] - '0')
+		if digit < 0 || digit > 9 {
+			return fmt.Errorf("%w %q: must contain only digits", ErrInvalidBarcode, barcode)
+		}
+		if i == ean13Length-1 {
+			break
+		}
+		// Веса чередуются 1 и 3, начиная с первой цифры EAN-13
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	if checkDigit := (10 - sum%10) % 10; int(barcode[ean13Length-1]-'0') != checkDigit {
+		return fmt.Errorf("%w %q: %w", ErrInvalidBarcode, barcode, errBarcodeCheckDigit)
+	}
+	return nil
+}
+
+func ValidateBarcodes(barcodes []string) error {
+	if len(barcodes) > MaxBarcodes {
+		return fmt.Errorf("%w: max %d, got %d", ErrTooManyBarcodes, MaxBarcodes, len(barcodes))
+	}
+	var errs []error
+	for _, barcode := range barcodes {
+		errs = append(errs, ValidateBarcode(barcode))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,38 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"productservice/pkg/product/domain/model"
+)
+
+func TestValidateBarcode(t *testing.T) {
+	testCases := []struct {
+		name    string
+		barcode string
+		valid   bool
+	}{
+		{name: "EAN-13", barcode: "4006381333931", valid: true},
+		{name: "UPC-A", barcode: "036000291452", valid: true},
+		{name: "EAN-13 wrong check digit", barcode: "4006381333932"},
+		{name: "UPC-A wrong check digit", barcode: "036000291453"},
+		{name: "not digits", barcode: "40063813339A1"},
+		{name: "wrong length", barcode: "12345678"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := model.ValidateBarcode(model.NormalizeBarcode(tc.barcode))
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, model.ErrInvalidBarcode)
+			}
+		})
+	}
+}
+
+func TestNormalizeBarcodes_UPCMatchesEAN(t *testing.T) {
+	assert.Equal(t, []string{"0036000291452"}, model.NormalizeBarcodes([]string{" 036000291452", "0036000291452"}))
+}
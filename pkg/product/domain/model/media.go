@@ -0,0 +1,182 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMediaNotFound      = errors.New("product media not found")
+	ErrInvalidMedia       = errors.New("invalid product media")
+	ErrInvalidMediaOrder  = errors.New("invalid product media order")
+	ErrTooManyMedia       = errors.New("too many product media")
+	errMediaSourceMissing = errors.New("exactly one of url and storage key must be set")
+)
+
+const (
+	// MaxMedia ограничивает число медиа одного продукта
+	MaxMedia              = 32
+	MaxMediaURLLength     = 2048
+	MaxMediaKeyLength     = 255
+	MaxMediaAltTextLength = 512
+	// MaxMediaDimension ограничивает ширину и высоту медиа в пикселях
+	MaxMediaDimension = 65535
+)
+
+// Media изображение или видео продукта. Источник задается ровно одним из URL и StorageKey
+type Media struct {
+	MediaID uuid.UUID
+	// URL внешний адрес с абсолютной схемой http или https
+	URL string
+	// StorageKey ключ файла в хранилище медиа сервиса
+	StorageKey string
+	AltText    string
+	// Width и Height размер в пикселях, 0 если неизвестен
+	Width       int
+	Height      int
+	ContentType string
+	// Primary основное медиа, у продукта с медиа ровно одно основное
+	Primary bool
+}
+
+// MediaCreate данные нового медиа, Primary делает его основным вместо текущего
+type MediaCreate struct {
+	URL         string
+	StorageKey  string
+	AltText     string
+	Width       int
+	Height      int
+	ContentType string
+	Primary     bool
+}
+
+// FindMedia возвращает медиа продукта по идентификатору
+func (p *Product) FindMedia(mediaID uuid.UUID) (*Media, bool) {
+	i := slices.IndexFunc(p.Media, func(m Media) bool {
+		return m.MediaID == mediaID
+	})
+	if i == -1 {
+		return nil, false
+	}
+	return &p.Media[i], true
+}
+
+// PrimaryMedia возвращает основное медиа продукта, false для продукта без медиа
+func (p *Product) PrimaryMedia() (*Media, bool) {
+	i := slices.IndexFunc(p.Media, func(m Media) bool {
+		return m.Primary
+	})
+	if i == -1 {
+		return nil, false
+	}
+	return &p.Media[i], true
+}
+
+// SetPrimaryMedia делает медиа основным и снимает признак с остальных
+func (p *Product) SetPrimaryMedia(mediaID uuid.UUID) {
+	for i := range p.Media {
+		p.Media[i].Primary = p.Media[i].MediaID == mediaID
+	}
+}
+
+// NormalizeMediaCreate убирает пробелы по краям и приводит тип содержимого к каноническому виду
+func NormalizeMediaCreate(media MediaCreate) MediaCreate {
+	media.URL = strings.TrimSpace(media.URL)
+	media.StorageKey = strings.TrimSpace(media.StorageKey)
+	media.AltText = NormalizeName(media.AltText)
+	media.ContentType = strings.ToLower(strings.TrimSpace(media.ContentType))
+	if mediaType, params, err := mime.ParseMediaType(media.ContentType); err == nil {
+		media.ContentType = mime.FormatMediaType(mediaType, params)
+	}
+	return media
+}
+
+// ValidateMediaCreate проверяет уже нормализованные данные медиа
+func ValidateMediaCreate(media MediaCreate) error {
+	var errs []error
+	switch {
+	case (media.URL == "") == (media.StorageKey == ""):
+		errs = append(errs, errMediaSourceMissing)
+	case media.URL != "":
+		errs = append(errs, validateMediaURL(media.URL))
+	case utf8.RuneCountInString(media.StorageKey) > MaxMediaKeyLength:
+		errs = append(errs, fmt.Errorf("storage key must be at most %d characters", MaxMediaKeyLength))
+	}
+	if utf8.RuneCountInString(media.AltText) > MaxMediaAltTextLength {
+		errs = append(errs, fmt.Errorf("alt text must be at most %d characters", MaxMediaAltTextLength))
+	}
+	if media.Width < 0 || media.Width > MaxMediaDimension || media.Height < 0 || media.Height > MaxMediaDimension {
+		errs = append(errs, fmt.Errorf("width and height must be between 0 and %d", MaxMediaDimension))
+	}
+	errs = append(errs, validateMediaContentType(media.ContentType))
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMedia, err)
+	}
+	return nil
+}
+
+// ValidateMediaOrder проверяет, что mediaIDs перечисляют все медиа продукта ровно по одному разу
+func ValidateMediaOrder(media []Media, mediaIDs []uuid.UUID) error {
+	if len(mediaIDs) != len(media) {
+		return fmt.Errorf("%w: expected %d media, got %d", ErrInvalidMediaOrder, len(media), len(mediaIDs))
+	}
+	seen := make(map[uuid.UUID]struct{}, len(mediaIDs))
+	for _, mediaID := range mediaIDs {
+		if _, ok := seen[mediaID]; ok {
+			return fmt.Errorf("%w: duplicate media %s", ErrInvalidMediaOrder, mediaID)
+		}
+		seen[mediaID] = struct{}{}
+		if !slices.ContainsFunc(media, func(m Media) bool { return m.MediaID == mediaID }) {
+			return fmt.Errorf("%w: %s", ErrMediaNotFound, mediaID)
+		}
+	}
+	return nil
+}
+
+func validateMediaURL(rawURL string) error {
+	if utf8.RuneCountInString(rawURL) > MaxMediaURLLength {
+		return fmt.Errorf("url must be at most %d characters", MaxMediaURLLength)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http or https url")
+	}
+	return nil
+}
+
+// mediaExtensions допустимые типы медиа и расширения их файлов. Типы, которые браузер может исполнить,
+// например image/svg+xml, не допускаются: файлы раздаются с домена сервиса
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"video/mp4":  ".mp4",
+	"video/webm": ".webm",
+}
+
+// MediaExtension возвращает расширение файла для допустимого типа медиа, для остальных типов - пустую строку
+func MediaExtension(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mediaExtensions[mediaType]
+}
+
+// validateMediaContentType допускает только перечисленные в mediaExtensions изображения и видео
+func validateMediaContentType(contentType string) error {
+	if MediaExtension(contentType) == "" {
+		return fmt.Errorf("content type must be one of jpeg, png, gif, webp, mp4 or webm, got %q", contentType)
+	}
+	return nil
+}
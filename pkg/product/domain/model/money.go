@@ -0,0 +1,73 @@
+package model
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/currency"
+)
+
+var ErrInvalidCurrency = errors.New("invalid currency")
+
+// DefaultCurrency валюта, в которой хранились цены до появления мультивалютности
+const DefaultCurrency = "RUB"
+
+// Money сумма в минимальных единицах валюты (копейках, центах) с кодом валюты по ISO 4217
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NormalizePrices приводит коды валют к верхнему регистру и сортирует цены по валюте
+func NormalizePrices(prices []Money) []Money {
+	result := make([]Money, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, Money{
+			Amount:   price.Amount,
+			Currency: strings.ToUpper(strings.TrimSpace(price.Currency)),
+		})
+	}
+	slices.SortFunc(result, func(l, r Money) int {
+		return cmp.Compare(l.Currency, r.Currency)
+	})
+	return result
+}
+
+// ValidatePrices проверяет нормализованный набор цен: хотя бы одна цена, не больше одной цены на валюту
+func ValidatePrices(prices []Money) error {
+	if len(prices) == 0 {
+		return fmt.Errorf("%w: at least one price is required", ErrInvalidPrice)
+	}
+	errs := make([]error, 0, len(prices))
+	for i, price := range prices {
+		if i > 0 && prices[i-1].Currency == price.Currency {
+			errs = append(errs, fmt.Errorf("%w: duplicate price in %s", ErrInvalidPrice, price.Currency))
+		}
+		errs = append(errs, ValidateMoney(price))
+	}
+	return errors.Join(errs...)
+}
+
+func ValidateMoney(money Money) error {
+	unit, err := currency.ParseISO(money.Currency)
+	if err != nil || unit.String() != money.Currency {
+		return fmt.Errorf("%w: unknown ISO 4217 code %q", ErrInvalidCurrency, money.Currency)
+	}
+	if money.Amount < 0 {
+		return fmt.Errorf("%w: must not be negative, got %d %s", ErrInvalidPrice, money.Amount, money.Currency)
+	}
+	return nil
+}
+
+// FindPrice возвращает цену в заданной валюте
+func FindPrice(prices []Money, currency string) (Money, bool) {
+	for _, price := range prices {
+		if price.Currency == currency {
+			return price, true
+		}
+	}
+	return Money{}, false
+}
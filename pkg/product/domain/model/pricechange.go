@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledPriceChange цена продукта в валюте Price.Currency, которая вступит в силу в EffectiveFrom
+type ScheduledPriceChange struct {
+	ChangeID      uuid.UUID
+	ProductID     uuid.UUID
+	Price         Money
+	EffectiveFrom time.Time
+	CreatedAt     time.Time
+	AppliedAt     *time.Time
+}
+
+type ScheduledPriceChangeRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(change ScheduledPriceChange) error
+	// FindDue возвращает неприменённые изменения продукта с EffectiveFrom не позже at, упорядоченные по EffectiveFrom
+	FindDue(productID uuid.UUID, at time.Time) ([]ScheduledPriceChange, error)
+	// FindDueProductIDs возвращает продукты, у которых есть неприменённые изменения с EffectiveFrom не позже at,
+	// начиная с продуктов с самым ранним таким изменением
+	FindDueProductIDs(at time.Time, limit int) ([]uuid.UUID, error)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,25 +11,102 @@ import (
 var (
 	ErrProductNotFound        = errors.New("product not found")
 	ErrProductNameAlreadyUsed = errors.New("product name already used")
+	ErrProductVersionMismatch = errors.New("product version mismatch")
 )
 
+// ProductNameAlreadyUsedError уточняет ErrProductNameAlreadyUsed занятым именем
+type ProductNameAlreadyUsedError struct {
+	Name string
+}
+
+func (e ProductNameAlreadyUsedError) Error() string {
+	return fmt.Sprintf("%s: %q", ErrProductNameAlreadyUsed, e.Name)
+}
+
+func (e ProductNameAlreadyUsedError) Unwrap() error {
+	return ErrProductNameAlreadyUsed
+}
+
 // Product представляет доменную модель продукта
 type Product struct {
 	ProductID uuid.UUID
-	Name      string
-	Price     int64
+	// Name имя в локали по умолчанию, уникально среди продуктов
+	Name string
+	// Description описание в локали по умолчанию, пустое, если не задано
+	Description string
+	// Translations переводы имени и описания, не больше одного на локаль, отсортированы по локали
+	Translations []Translation
+	// Prices цены продукта, не больше одной на валюту, отсортированы по валюте
+	Prices []Money
+	// PricesEffectiveFrom моменты вступления в силу изменяемых цен по валютам для истории цен, не хранится.
+	// Для валют без значения используется UpdatedAt
+	PricesEffectiveFrom map[string]time.Time
+	// SKU уникальный артикул продукта, пустой, если не задан
+	SKU string
+	// Barcodes уникальные штрихкоды продукта в формате EAN-13, отсортированы
+	Barcodes []string
+	// CategoryIDs категории продукта без повторов, отсортированы
+	CategoryIDs []uuid.UUID
+	// Attributes значения атрибутов продукта, отсортированы по коду
+	Attributes []AttributeValue
+	// Variants варианты продукта в порядке добавления
+	Variants []Variant
+	// Media медиа продукта в порядке показа
+	Media []Media
+	// Status меняется только через PublishProduct и ArchiveProduct
+	Status ProductStatus
+	// Version увеличивается при каждом изменении продукта
+	Version   int64
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// ProductCreate данные нового продукта
+type ProductCreate struct {
+	Name         string
+	Description  string
+	Translations []Translation
+	Prices       []Money
+	SKU          string
+	Barcodes     []string
+}
+
+// ProductUpdate изменяемые поля продукта, nil означает, что поле не меняется
+type ProductUpdate struct {
+	Name        *string
+	Description *string
+	// Translations заменяет все переводы продукта
+	Translations *[]Translation
+	// Prices заменяет весь набор цен продукта
+	Prices *[]Money
+	// PricesEffectiveFrom моменты вступления в силу новых цен по валютам, по умолчанию момент изменения
+	PricesEffectiveFrom map[string]time.Time
+	// SKU пустая строка убирает артикул
+	SKU *string
+	// Barcodes заменяет весь набор штрихкодов продукта
+	Barcodes *[]string
+	// CategoryIDs заменяет весь набор категорий продукта
+	CategoryIDs *[]uuid.UUID
+	// Attributes заменяет все значения атрибутов продукта, значения должны быть уже проверены по определениям
+	Attributes *[]AttributeValue
 }
 
 type FindSpec struct {
 	ProductID *uuid.UUID
 	Name      *string
+	// SKU ищет продукт по его SKU или SKU одного из его вариантов
+	SKU     *string
+	Barcode *string
+	// IncludeDeleted включает в поиск удаленные продукты
+	IncludeDeleted bool
 }
 
 type ProductRepository interface {
 	NextID() (uuid.UUID, error)
 	Store(product Product) error
 	Find(spec FindSpec) (*Product, error)
-	HardDelete(productID uuid.UUID) error
+	// PurgeDeleted окончательно удаляет продукты, удаленные раньше deletedBefore, вместе с их медиа.
+	// Возвращает число удаленных продуктов и ключи файлов их медиа в хранилище
+	PurgeDeleted(deletedBefore time.Time) (int64, []string, error)
 }
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidProductStatus    = errors.New("invalid product status")
+	ErrInvalidStatusTransition = errors.New("invalid product status transition")
+)
+
+// ProductStatus этап жизненного цикла продукта. Новый продукт создается черновиком,
+// допустимые переходы: draft -> active, active -> archived, archived -> active
+type ProductStatus int
+
+const (
+	ProductStatusDraft ProductStatus = iota
+	ProductStatusActive
+	ProductStatusArchived
+)
+
+var productStatusNames = map[ProductStatus]string{
+	ProductStatusDraft:    "draft",
+	ProductStatusActive:   "active",
+	ProductStatusArchived: "archived",
+}
+
+func (s ProductStatus) String() string {
+	if name, ok := productStatusNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func ParseProductStatus(name string) (ProductStatus, error) {
+	for status, statusName := range productStatusNames {
+		if statusName == name {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidProductStatus, name)
+}
+
+var productStatusTransitions = map[ProductStatus][]ProductStatus{
+	ProductStatusDraft:    {ProductStatusActive},
+	ProductStatusActive:   {ProductStatusArchived},
+	ProductStatusArchived: {ProductStatusActive},
+}
+
+// CheckStatusTransition проверяет, что продукт может перейти из статуса from в статус to
+func CheckStatusTransition(from, to ProductStatus) error {
+	for _, allowed := range productStatusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: from %s to %s", ErrInvalidStatusTransition, from, to)
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrInvalidLocale      = errors.New("invalid locale")
+	ErrInvalidDescription = errors.New("invalid product description")
+	ErrInvalidTranslation = errors.New("invalid product translation")
+)
+
+const (
+	// MaxDescriptionLength ограничивает длину описания в символах, колонка description MEDIUMTEXT
+	MaxDescriptionLength = 50000
+	MaxTranslations      = 64
+)
+
+// Translation перевод имени и описания продукта на локаль, отличную от локали по умолчанию.
+// Name и Description продукта остаются текстами локали по умолчанию
+type Translation struct {
+	// Locale тег BCP 47 в канонической форме, например en или pt-BR
+	Locale      string
+	Name        string
+	Description string
+}
+
+// NormalizeLocale приводит тег локали к канонической форме, некорректный тег возвращается без пробелов по краям
+func NormalizeLocale(locale string) string {
+	locale = strings.TrimSpace(locale)
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return locale
+	}
+	return tag.String()
+}
+
+// ValidateLocale проверяет уже нормализованный тег локали
+func ValidateLocale(locale string) error {
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und {
+		return fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+	return nil
+}
+
+// NormalizeDescription убирает пробелы по краям и приводит описание к NFC
+func NormalizeDescription(description string) string {
+	return norm.NFC.String(strings.TrimSpace(description))
+}
+
+// ValidateDescription проверяет уже нормализованное описание, пустое описание допустимо.
+// Описание может быть размеченным текстом, поэтому переводы строк и табуляция разрешены
+func ValidateDescription(description string) error {
+	if !utf8.ValidString(description) {
+		return fmt.Errorf("%w: must be valid UTF-8", ErrInvalidDescription)
+	}
+	if length := utf8.RuneCountInString(description); length > MaxDescriptionLength {
+		return fmt.Errorf("%w: must be at most %d characters, got %d", ErrInvalidDescription, MaxDescriptionLength, length)
+	}
+	if strings.IndexFunc(description, isForbiddenDescriptionRune) != -1 {
+		return fmt.Errorf("%w: must not contain control characters", ErrInvalidDescription)
+	}
+	return nil
+}
+
+func isForbiddenDescriptionRune(r rune) bool {
+	return unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t'
+}
+
+// NormalizeTranslations нормализует локали и тексты переводов и сортирует их по локали
+func NormalizeTranslations(translations []Translation) []Translation {
+	result := make([]Translation, 0, len(translations))
+	for _, translation := range translations {
+		result = append(result, Translation{
+			Locale:      NormalizeLocale(translation.Locale),
+			Name:        NormalizeName(translation.Name),
+			Description: NormalizeDescription(translation.Description),
+		})
+	}
+	slices.SortFunc(result, func(l, r Translation) int {
+		return strings.Compare(l.Locale, r.Locale)
+	})
+	return result
+}
+
+// ValidateTranslations проверяет уже нормализованные переводы. Имена переводов не обязаны быть уникальными
+func ValidateTranslations(translations []Translation) error {
+	if len(translations) > MaxTranslations {
+		return fmt.Errorf("%w: must be at most %d translations, got %d", ErrInvalidTranslation, MaxTranslations, len(translations))
+	}
+	var errs []error
+	for i, translation := range translations {
+		err := errors.Join(
+			ValidateLocale(translation.Locale),
+			ValidateName(translation.Name),
+			ValidateDescription(translation.Description),
+		)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: locale %q: %w", ErrInvalidTranslation, translation.Locale, err))
+			continue
+		}
+		if i > 0 && translations[i-1].Locale == translation.Locale {
+			errs = append(errs, fmt.Errorf("%w: duplicate locale %q", ErrInvalidTranslation, translation.Locale))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"productservice/pkg/product/domain/model"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	assert.Equal(t, "pt-BR", model.NormalizeLocale(" pt-br "))
+	assert.Equal(t, "en", model.NormalizeLocale("EN"))
+	assert.Equal(t, "not a locale", model.NormalizeLocale("not a locale"))
+}
+
+func TestValidateTranslations(t *testing.T) {
+	testCases := []struct {
+		name         string
+		translations []model.Translation
+		err          error
+	}{
+		{
+			name: "valid",
+			translations: []model.Translation{
+				{Locale: "en", Name: "Green Tea", Description: "Loose leaf\n\n**Organic**"},
+				{Locale: "pt-br", Name: "Chá Verde"},
+			},
+		},
+		{
+			name:         "invalid locale",
+			translations: []model.Translation{{Locale: "not a locale", Name: "Green Tea"}},
+			err:          model.ErrInvalidLocale,
+		},
+		{
+			name:         "undetermined locale",
+			translations: []model.Translation{{Locale: "und", Name: "Green Tea"}},
+			err:          model.ErrInvalidLocale,
+		},
+		{
+			name:         "empty name",
+			translations: []model.Translation{{Locale: "en", Name: " "}},
+			err:          model.ErrInvalidName,
+		},
+		{
+			name:         "control characters in description",
+			translations: []model.Translation{{Locale: "en", Name: "Green Tea", Description: "Green\x00Tea"}},
+			err:          model.ErrInvalidDescription,
+		},
+		{
+			name:         "description too long",
+			translations: []model.Translation{{Locale: "en", Name: "Green Tea", Description: strings.Repeat("a", model.MaxDescriptionLength+1)}},
+			err:          model.ErrInvalidDescription,
+		},
+		{
+			name: "duplicate locale",
+			translations: []model.Translation{
+				{Locale: "en-us", Name: "Green Tea"},
+				{Locale: "en-US", Name: "Tea"},
+			},
+			err: model.ErrInvalidTranslation,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := model.ValidateTranslations(model.NormalizeTranslations(tc.translations))
+			if tc.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.err)
+				assert.ErrorIs(t, err, model.ErrInvalidTranslation)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrInvalidName  = errors.New("invalid product name")
+	ErrInvalidPrice = errors.New("invalid product price")
+)
+
+// MaxNameLength длина колонки name VARCHAR(255) в символах
+const MaxNameLength = 255
+
+// NormalizeName убирает пробелы по краям и приводит имя к NFC,
+// чтобы одинаково выглядящие имена хранились одинаково
+func NormalizeName(name string) string {
+	return norm.NFC.String(strings.TrimSpace(name))
+}
+
+// NameKey возвращает ключ сравнения имен без учета регистра и диакритики,
+// как при сравнении по utf8mb4_unicode_ci
+func NameKey(name string) string {
+	key, _, err := transform.String(
+		transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC),
+		NormalizeName(name),
+	)
+	if err != nil {
+		key = NormalizeName(name)
+	}
+	return cases.Fold().String(key)
+}
+
+// ValidateName проверяет уже нормализованное имя продукта
+func ValidateName(name string) error {
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("%w: must be valid UTF-8", ErrInvalidName)
+	}
+	if name == "" {
+		return fmt.Errorf("%w: must not be empty", ErrInvalidName)
+	}
+	if length := utf8.RuneCountInString(name); length > MaxNameLength {
+		return fmt.Errorf("%w: must be at most %d characters, got %d", ErrInvalidName, MaxNameLength, length)
+	}
+	if strings.IndexFunc(name, unicode.IsControl) != -1 {
+		return fmt.Errorf("%w: must not contain control characters", ErrInvalidName)
+	}
+	return nil
+}
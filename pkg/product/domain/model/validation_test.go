@@ -0,0 +1,14 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"productservice/pkg/product/domain/model"
+)
+
+func TestNameKey_IgnoresCaseAndDiacritics(t *testing.T) {
+	assert.Equal(t, model.NameKey("Crème Brûlée"), model.NameKey("  CREME BRULEE "))
+	assert.NotEqual(t, model.NameKey("Latte"), model.NameKey("Lattes"))
+}
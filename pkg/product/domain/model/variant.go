@@ -0,0 +1,103 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrVariantNotFound            = errors.New("product variant not found")
+	ErrInvalidVariantOptions      = errors.New("invalid product variant options")
+	ErrVariantOptionsAlreadyUsed  = errors.New("product variant options already used")
+	errVariantOptionNameDuplicate = errors.New("duplicate option name")
+)
+
+// MaxVariantOptionLength ограничивает длину имени и значения опции варианта
+const MaxVariantOptionLength = 64
+
+// Variant вариант продукта, например размер или цвет
+type Variant struct {
+	VariantID uuid.UUID
+	// Options опции варианта, имена не повторяются, отсортированы по имени
+	Options []VariantOption
+	// Prices переопределяют цены продукта, пустой список означает цены продукта
+	Prices []Money
+	// SKU уникальный среди продуктов и вариантов артикул, пустой, если не задан
+	SKU string
+}
+
+type VariantOption struct {
+	Name  string
+	Value string
+}
+
+// VariantCreate данные нового варианта
+type VariantCreate struct {
+	Options []VariantOption
+	Prices  []Money
+	SKU     string
+}
+
+// VariantUpdate изменяемые поля варианта, nil означает, что поле не меняется
+type VariantUpdate struct {
+	Options *[]VariantOption
+	Prices  *[]Money
+	SKU     *string
+}
+
+// FindVariant возвращает вариант продукта по идентификатору
+func (p *Product) FindVariant(variantID uuid.UUID) (*Variant, bool) {
+	i := slices.IndexFunc(p.Variants, func(v Variant) bool {
+		return v.VariantID == variantID
+	})
+	if i == -1 {
+		return nil, false
+	}
+	return &p.Variants[i], true
+}
+
+// NormalizeVariantOptions убирает пробелы по краям, приводит имена опций к нижнему регистру и сортирует по имени
+func NormalizeVariantOptions(options []VariantOption) []VariantOption {
+	result := make([]VariantOption, 0, len(options))
+	for _, option := range options {
+		result = append(result, VariantOption{
+			Name:  strings.ToLower(NormalizeName(option.Name)),
+			Value: NormalizeName(option.Value),
+		})
+	}
+	slices.SortFunc(result, func(l, r VariantOption) int {
+		return strings.Compare(l.Name, r.Name)
+	})
+	return result
+}
+
+// ValidateVariantOptions проверяет уже нормализованные опции варианта
+func ValidateVariantOptions(options []VariantOption) error {
+	if len(options) == 0 {
+		return fmt.Errorf("%w: must have at least one option", ErrInvalidVariantOptions)
+	}
+	for i, option := range options {
+		for _, s := range []string{option.Name, option.Value} {
+			if s == "" || utf8.RuneCountInString(s) > MaxVariantOptionLength {
+				return fmt.Errorf("%w: option names and values must be 1 to %d characters", ErrInvalidVariantOptions, MaxVariantOptionLength)
+			}
+		}
+		if i > 0 && options[i-1].Name == option.Name {
+			return fmt.Errorf("%w: %w %q", ErrInvalidVariantOptions, errVariantOptionNameDuplicate, option.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateVariantPrices проверяет уже нормализованные цены варианта, пустой список допустим
+func ValidateVariantPrices(prices []Money) error {
+	if len(prices) == 0 {
+		return nil
+	}
+	return ValidatePrices(prices)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"productservice/pkg/product/domain/model"
+)
+
+type AttributeService interface {
+	CreateAttributeDefinition(definition model.AttributeDefinition) error
+	// UpdateAttributeDefinition меняет имя и значения перечисления, тип атрибута не меняется.
+	// Значения перечисления, которые используются продуктами, удалить нельзя
+	UpdateAttributeDefinition(code, name string, enumValues []string) error
+	// DeleteAttributeDefinition удаляет только атрибуты, которые не используются продуктами
+	DeleteAttributeDefinition(code string) error
+	// SetProductAttributes проверяет значения по определениям атрибутов и заменяет ими атрибуты продукта
+	SetProductAttributes(productID uuid.UUID, values []model.AttributeValue) error
+}
+
+func NewAttributeService(
+	attributeRepository model.AttributeDefinitionRepository,
+	productService ProductService,
+) AttributeService {
+	return &attributeService{
+		attributeRepository: attributeRepository,
+		productService:      productService,
+	}
+}
+
+type attributeService struct {
+	attributeRepository model.AttributeDefinitionRepository
+	productService      ProductService
+}
+
+func (s *attributeService) CreateAttributeDefinition(definition model.AttributeDefinition) error {
+	definition.Code = model.NormalizeAttributeCode(definition.Code)
+	definition.Name = model.NormalizeName(definition.Name)
+	definition.EnumValues = normalizeEnumValues(definition.EnumValues)
+	err := model.ValidateAttributeDefinition(definition)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.attributeRepository.Find(definition.Code)
+	if err == nil {
+		return fmt.Errorf("%w: %q", model.ErrAttributeAlreadyExists, definition.Code)
+	}
+	if !errors.Is(err, model.ErrAttributeNotFound) {
+		return err
+	}
+
+	currentTime := time.Now()
+	definition.CreatedAt = currentTime
+	definition.UpdatedAt = currentTime
+	return s.attributeRepository.Store(definition)
+}
+
+func (s *attributeService) UpdateAttributeDefinition(code, name string, enumValues []string) error {
+	definition, err := s.attributeRepository.Find(model.NormalizeAttributeCode(code))
+	if err != nil {
+		return err
+	}
+
+	enumValues = normalizeEnumValues(enumValues)
+	for _, value := range definition.EnumValues {
+		if slices.Contains(enumValues, value) {
+			continue
+		}
+		used, err := s.attributeRepository.IsUsed(definition.Code, &value)
+		if err != nil {
+			return err
+		}
+		if used {
+			return fmt.Errorf("%w: enum value %q of %s", model.ErrAttributeInUse, value, definition.Code)
+		}
+	}
+
+	definition.Name = model.NormalizeName(name)
+	definition.EnumValues = enumValues
+	err = model.ValidateAttributeDefinition(*definition)
+	if err != nil {
+		return err
+	}
+
+	definition.UpdatedAt = time.Now()
+	return s.attributeRepository.Store(*definition)
+}
+
+func (s *attributeService) DeleteAttributeDefinition(code string) error {
+	definition, err := s.attributeRepository.Find(model.NormalizeAttributeCode(code))
+	if err != nil {
+		return err
+	}
+
+	used, err := s.attributeRepository.IsUsed(definition.Code, nil)
+	if err != nil {
+		return err
+	}
+	if used {
+		return fmt.Errorf("%w: %s", model.ErrAttributeInUse, definition.Code)
+	}
+	return s.attributeRepository.Delete(definition.Code)
+}
+
+func (s *attributeService) SetProductAttributes(productID uuid.UUID, values []model.AttributeValue) error {
+	normalized := make([]model.AttributeValue, 0, len(values))
+	for _, value := range values {
+		definition, err := s.attributeRepository.Find(model.NormalizeAttributeCode(value.Code))
+		if err != nil {
+			return err
+		}
+		v, err := model.NormalizeAttributeValue(*definition, value.Value)
+		if err != nil {
+			return err
+		}
+		normalized = append(normalized, model.AttributeValue{Code: definition.Code, Value: v})
+	}
+
+	normalized, err := model.SortAttributeValues(normalized)
+	if err != nil {
+		return err
+	}
+	return s.productService.UpdateProduct(productID, model.ProductUpdate{Attributes: &normalized}, nil)
+}
+
+func normalizeEnumValues(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, model.NormalizeName(value))
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/product/domain/model"
+	"productservice/pkg/product/domain/service"
+)
+
+// --- Mocks ---
+type mockAttributeDefinitionRepository struct {
+	definitions map[string]model.AttributeDefinition
+	products    *mockProductRepository
+}
+
+func (m *mockAttributeDefinitionRepository) Store(definition model.AttributeDefinition) error {
+	m.definitions[definition.Code] = definition
+	return nil
+}
+
+func (m *mockAttributeDefinitionRepository) Find(code string) (*model.AttributeDefinition, error) {
+	definition, ok := m.definitions[code]
+	if !ok {
+		return nil, model.ErrAttributeNotFound
+	}
+	return &definition, nil
+}
+
+func (m *mockAttributeDefinitionRepository) Delete(code string) error {
+	delete(m.definitions, code)
+	return nil
+}
+
+func (m *mockAttributeDefinitionRepository) IsUsed(code string, value *string) (bool, error) {
+	for _, product := range m.products.products {
+		for _, attribute := range product.Attributes {
+			if attribute.Code == code && (value == nil || attribute.Value == *value) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func newAttributeService(t *testing.T) (service.AttributeService, *mockProductRepository, *mockEventDispatcher) {
+	t.Helper()
+	productRepo := newMockProductRepository()
+	attributeRepo := &mockAttributeDefinitionRepository{
+		definitions: make(map[string]model.AttributeDefinition),
+		products:    productRepo,
+	}
+	dispatcher := &mockEventDispatcher{}
+	attributeService := service.NewAttributeService(attributeRepo, service.NewProductService(productRepo, dispatcher))
+
+	require.NoError(t, attributeService.CreateAttributeDefinition(model.AttributeDefinition{Code: "Weight", Name: "Weight, kg", Type: model.AttributeTypeNumber}))
+	require.NoError(t, attributeService.CreateAttributeDefinition(model.AttributeDefinition{Code: "organic", Name: "Organic", Type: model.AttributeTypeBool}))
+	require.NoError(t, attributeService.CreateAttributeDefinition(model.AttributeDefinition{
+		Code:       "color",
+		Name:       "Color",
+		Type:       model.AttributeTypeEnum,
+		EnumValues: []string{"red", "green"},
+	}))
+	return attributeService, productRepo, dispatcher
+}
+
+// --- Tests ---
+
+func TestAttributeService_SetProductAttributes(t *testing.T) {
+	// Arrange
+	attributeService, productRepo, dispatcher := newAttributeService(t)
+	productID, err := service.NewProductService(productRepo, dispatcher).CreateProduct(model.ProductCreate{Name: "Apple", Prices: rub(100)})
+	require.NoError(t, err)
+
+	// Act
+	err = attributeService.SetProductAttributes(productID, []model.AttributeValue{
+		{Code: "weight", Value: " 0.250 "},
+		{Code: "Organic", Value: "1"},
+		{Code: "color", Value: "red"},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	expected := []model.AttributeValue{
+		{Code: "color", Value: "red"},
+		{Code: "organic", Value: "true"},
+		{Code: "weight", Value: "0.25"},
+	}
+	assert.Equal(t, expected, productRepo.products[productID].Attributes)
+	updatedEvent, ok := dispatcher.dispatchedEvents[len(dispatcher.dispatchedEvents)-1].(*model.ProductUpdated)
+	require.True(t, ok)
+	require.NotNil(t, updatedEvent.UpdatedFields.Attributes)
+	assert.Equal(t, expected, *updatedEvent.UpdatedFields.Attributes)
+}
+
+func TestAttributeService_SetProductAttributes_InvalidValues(t *testing.T) {
+	attributeService, productRepo, dispatcher := newAttributeService(t)
+	productID, err := service.NewProductService(productRepo, dispatcher).CreateProduct(model.ProductCreate{Name: "Apple", Prices: rub(100)})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		values      []model.AttributeValue
+		expectedErr error
+	}{
+		{name: "not a number", values: []model.AttributeValue{{Code: "weight", Value: "heavy"}}, expectedErr: model.ErrInvalidAttributeValue},
+		{name: "not a bool", values: []model.AttributeValue{{Code: "organic", Value: "maybe"}}, expectedErr: model.ErrInvalidAttributeValue},
+		{name: "unknown enum value", values: []model.AttributeValue{{Code: "color", Value: "blue"}}, expectedErr: model.ErrInvalidAttributeValue},
+		{name: "duplicate code", values: []model.AttributeValue{{Code: "color", Value: "red"}, {Code: "COLOR", Value: "green"}}, expectedErr: model.ErrInvalidAttributeValue},
+		{name: "unknown attribute", values: []model.AttributeValue{{Code: "brand", Value: "Acme"}}, expectedErr: model.ErrAttributeNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := attributeService.SetProductAttributes(productID, tc.values)
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Empty(t, productRepo.products[productID].Attributes)
+		})
+	}
+}
+
+func TestAttributeService_DefinitionInUse(t *testing.T) {
+	// Arrange
+	attributeService, productRepo, dispatcher := newAttributeService(t)
+	productID, err := service.NewProductService(productRepo, dispatcher).CreateProduct(model.ProductCreate{Name: "Apple", Prices: rub(100)})
+	require.NoError(t, err)
+	require.NoError(t, attributeService.SetProductAttributes(productID, []model.AttributeValue{{Code: "color", Value: "red"}}))
+
+	// Act & Assert
+	assert.ErrorIs(t, attributeService.UpdateAttributeDefinition("color", "Color", []string{"green"}), model.ErrAttributeInUse)
+	assert.NoError(t, attributeService.UpdateAttributeDefinition("color", "Colour", []string{"red", "blue"}))
+	assert.ErrorIs(t, attributeService.DeleteAttributeDefinition("color"), model.ErrAttributeInUse)
+	assert.NoError(t, attributeService.DeleteAttributeDefinition("weight"))
+	assert.ErrorIs(t, attributeService.CreateAttributeDefinition(model.AttributeDefinition{Code: "color", Name: "Color", Type: model.AttributeTypeString}), model.ErrAttributeAlreadyExists)
+}
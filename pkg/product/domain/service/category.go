@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"productservice/pkg/common/domain"
+	"productservice/pkg/product/domain/model"
+)
+
+type CategoryService interface {
+	CreateCategory(name string, parentID *uuid.UUID) (uuid.UUID, error)
+	// UpdateCategory переименовывает категорию и переносит ее к parentID, nil делает категорию корневой
+	UpdateCategory(categoryID uuid.UUID, name string, parentID *uuid.UUID) error
+	// DeleteCategory удаляет только категории без подкатегорий и продуктов
+	DeleteCategory(categoryID uuid.UUID) error
+	// SetProductCategories заменяет набор категорий продукта
+	SetProductCategories(productID uuid.UUID, categoryIDs []uuid.UUID) error
+}
+
+func NewCategoryService(
+	categoryRepository model.CategoryRepository,
+	eventDispatcher domain.EventDispatcher,
+	productService ProductService,
+) CategoryService {
+	return &categoryService{
+		categoryRepository: categoryRepository,
+		eventDispatcher:    eventDispatcher,
+		productService:     productService,
+	}
+}
+
+type categoryService struct {
+	categoryRepository model.CategoryRepository
+	eventDispatcher    domain.EventDispatcher
+	productService     ProductService
+}
+
+func (s *categoryService) CreateCategory(name string, parentID *uuid.UUID) (uuid.UUID, error) {
+	name = model.NormalizeName(name)
+	err := model.ValidateName(name)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if parentID != nil {
+		_, err = s.categoryRepository.Find(*parentID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	categoryID, err := s.categoryRepository.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	currentTime := time.Now()
+	err = s.categoryRepository.Store(model.Category{
+		CategoryID: categoryID,
+		ParentID:   parentID,
+		Name:       name,
+		CreatedAt:  currentTime,
+		UpdatedAt:  currentTime,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return categoryID, s.eventDispatcher.Dispatch(&model.CategoryCreated{
+		CategoryID: categoryID,
+		ParentID:   parentID,
+		Name:       name,
+		CreatedAt:  currentTime,
+	})
+}
+
+func (s *categoryService) UpdateCategory(categoryID uuid.UUID, name string, parentID *uuid.UUID) error {
+	name = model.NormalizeName(name)
+	err := model.ValidateName(name)
+	if err != nil {
+		return err
+	}
+
+	category, err := s.categoryRepository.Find(categoryID)
+	if err != nil {
+		return err
+	}
+	if parentID != nil {
+		err = s.checkParent(categoryID, *parentID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if category.Name == name && equalIDs(category.ParentID, parentID) {
+		return nil
+	}
+
+	currentTime := time.Now()
+	category.Name = name
+	category.ParentID = parentID
+	category.UpdatedAt = currentTime
+	err = s.categoryRepository.Store(*category)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.CategoryUpdated{
+		CategoryID: categoryID,
+		ParentID:   parentID,
+		Name:       name,
+		UpdatedAt:  currentTime,
+	})
+}
+
+func (s *categoryService) DeleteCategory(categoryID uuid.UUID) error {
+	_, err := s.categoryRepository.Find(categoryID)
+	if err != nil {
+		return err
+	}
+
+	hasChildren, err := s.categoryRepository.HasChildren(categoryID)
+	if err != nil {
+		return err
+	}
+	hasProducts, err := s.categoryRepository.HasProducts(categoryID)
+	if err != nil {
+		return err
+	}
+	if hasChildren || hasProducts {
+		return model.ErrCategoryNotEmpty
+	}
+
+	err = s.categoryRepository.Delete(categoryID)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.CategoryDeleted{
+		CategoryID: categoryID,
+		DeletedAt:  time.Now(),
+	})
+}
+
+func (s *categoryService) SetProductCategories(productID uuid.UUID, categoryIDs []uuid.UUID) error {
+	for _, categoryID := range categoryIDs {
+		_, err := s.categoryRepository.Find(categoryID)
+		if err != nil {
+			return err
+		}
+	}
+	return s.productService.UpdateProduct(productID, model.ProductUpdate{CategoryIDs: &categoryIDs}, nil)
+}
+
+// checkParent проверяет, что parentID существует и не входит в поддерево категории
+func (s *categoryService) checkParent(categoryID, parentID uuid.UUID) error {
+	ancestorID := &parentID
+	for ancestorID != nil {
+		if *ancestorID == categoryID {
+			return fmt.Errorf("%w: category %s can not be moved into its own subtree", model.ErrInvalidCategoryParent, categoryID)
+		}
+		ancestor, err := s.categoryRepository.Find(*ancestorID)
+		if err != nil {
+			return err
+		}
+		ancestorID = ancestor.ParentID
+	}
+	return nil
+}
+
+func equalIDs(l, r *uuid.UUID) bool {
+	if l == nil || r == nil {
+		return l == r
+	}
+	return *l == *r
+}
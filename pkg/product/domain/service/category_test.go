@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/product/domain/model"
+	"productservice/pkg/product/domain/service"
+)
+
+// --- Mocks ---
+type mockCategoryRepository struct {
+	categories map[uuid.UUID]model.Category
+	products   *mockProductRepository
+}
+
+func newMockCategoryRepository(products *mockProductRepository) *mockCategoryRepository {
+	return &mockCategoryRepository{
+		categories: make(map[uuid.UUID]model.Category),
+		products:   products,
+	}
+}
+
+func (m *mockCategoryRepository) NextID() (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+func (m *mockCategoryRepository) Store(category model.Category) error {
+	m.categories[category.CategoryID] = category
+	return nil
+}
+
+func (m *mockCategoryRepository) Find(categoryID uuid.UUID) (*model.Category, error) {
+	category, ok := m.categories[categoryID]
+	if !ok {
+		return nil, model.ErrCategoryNotFound
+	}
+	return &category, nil
+}
+
+func (m *mockCategoryRepository) Delete(categoryID uuid.UUID) error {
+	delete(m.categories, categoryID)
+	return nil
+}
+
+func (m *mockCategoryRepository) HasChildren(categoryID uuid.UUID) (bool, error) {
+	for _, category := range m.categories {
+		if category.ParentID != nil && *category.ParentID == categoryID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockCategoryRepository) HasProducts(categoryID uuid.UUID) (bool, error) {
+	for _, product := range m.products.products {
+		for _, id := range product.CategoryIDs {
+			if id == categoryID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func newCategoryService() (service.CategoryService, *mockCategoryRepository, *mockEventDispatcher) {
+	productRepo := newMockProductRepository()
+	categoryRepo := newMockCategoryRepository(productRepo)
+	dispatcher := &mockEventDispatcher{}
+	return service.NewCategoryService(categoryRepo, dispatcher, service.NewProductService(productRepo, dispatcher)), categoryRepo, dispatcher
+}
+
+// --- Tests ---
+
+func TestCategoryService_UpdateCategory_RejectsCycle(t *testing.T) {
+	// Arrange
+	categoryService, _, _ := newCategoryService()
+	rootID, err := categoryService.CreateCategory("Drinks", nil)
+	require.NoError(t, err)
+	childID, err := categoryService.CreateCategory("Coffee", &rootID)
+	require.NoError(t, err)
+	grandchildID, err := categoryService.CreateCategory("Espresso", &childID)
+	require.NoError(t, err)
+
+	// Act & Assert
+	err = categoryService.UpdateCategory(rootID, "Drinks", &grandchildID)
+	assert.ErrorIs(t, err, model.ErrInvalidCategoryParent)
+	err = categoryService.UpdateCategory(rootID, "Drinks", &rootID)
+	assert.ErrorIs(t, err, model.ErrInvalidCategoryParent)
+
+	// Перенос в соседнюю ветку допустим
+	err = categoryService.UpdateCategory(grandchildID, "Espresso", &rootID)
+	assert.NoError(t, err)
+}
+
+func TestCategoryService_DeleteCategory_NotEmpty(t *testing.T) {
+	// Arrange
+	categoryService, categoryRepo, dispatcher := newCategoryService()
+	rootID, err := categoryService.CreateCategory("Drinks", nil)
+	require.NoError(t, err)
+	childID, err := categoryService.CreateCategory("Tea", &rootID)
+	require.NoError(t, err)
+
+	productID := uuid.New()
+	categoryRepo.products.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Prices: rub(100)}
+	require.NoError(t, categoryService.SetProductCategories(productID, []uuid.UUID{childID, childID}))
+	assert.Equal(t, []uuid.UUID{childID}, categoryRepo.products.products[productID].CategoryIDs)
+
+	// Act & Assert
+	assert.ErrorIs(t, categoryService.DeleteCategory(rootID), model.ErrCategoryNotEmpty)
+	assert.ErrorIs(t, categoryService.DeleteCategory(childID), model.ErrCategoryNotEmpty)
+
+	require.NoError(t, categoryService.SetProductCategories(productID, nil))
+	require.NoError(t, categoryService.DeleteCategory(childID))
+	_, ok := categoryRepo.categories[childID]
+	assert.False(t, ok)
+	_, ok = dispatcher.dispatchedEvents[len(dispatcher.dispatchedEvents)-1].(*model.CategoryDeleted)
+	assert.True(t, ok)
+}
+
+func TestCategoryService_SetProductCategories_UnknownCategory(t *testing.T) {
+	categoryService, categoryRepo, _ := newCategoryService()
+	productID := uuid.New()
+	categoryRepo.products.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Prices: rub(100)}
+
+	err := categoryService.SetProductCategories(productID, []uuid.UUID{uuid.New()})
+	assert.ErrorIs(t, err, model.ErrCategoryNotFound)
+}
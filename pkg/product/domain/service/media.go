@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"productservice/pkg/product/domain/model"
+)
+
+func (s *productService) AddMedia(productID uuid.UUID, media model.MediaCreate) (uuid.UUID, error) {
+	media = model.NormalizeMediaCreate(media)
+	err := model.ValidateMediaCreate(media)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(product.Media) >= model.MaxMedia {
+		return uuid.Nil, fmt.Errorf("%w: at most %d per product", model.ErrTooManyMedia, model.MaxMedia)
+	}
+
+	mediaID, err := s.productRepository.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	newMedia := model.Media{
+		MediaID:     mediaID,
+		URL:         media.URL,
+		StorageKey:  media.StorageKey,
+		AltText:     media.AltText,
+		Width:       media.Width,
+		Height:      media.Height,
+		ContentType: media.ContentType,
+		Primary:     media.Primary || len(product.Media) == 0,
+	}
+
+	currentTime := time.Now()
+	product.Media = append(product.Media, newMedia)
+	if newMedia.Primary {
+		product.SetPrimaryMedia(mediaID)
+	}
+	product.Version++
+	product.UpdatedAt = currentTime
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return mediaID, s.eventDispatcher.Dispatch(&model.ProductMediaAdded{
+		ProductID: productID,
+		Media:     newMedia,
+		Version:   product.Version,
+		AddedAt:   currentTime,
+	})
+}
+
+func (s *productService) ReorderMedia(productID uuid.UUID, mediaIDs []uuid.UUID) error {
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return err
+	}
+	err = model.ValidateMediaOrder(product.Media, mediaIDs)
+	if err != nil {
+		return err
+	}
+
+	media := make([]model.Media, 0, len(mediaIDs))
+	for _, mediaID := range mediaIDs {
+		m, _ := product.FindMedia(mediaID)
+		media = append(media, *m)
+	}
+	if slices.Equal(media, product.Media) {
+		return nil
+	}
+
+	currentTime := time.Now()
+	product.Media = media
+	product.Version++
+	product.UpdatedAt = currentTime
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.ProductMediaReordered{
+		ProductID:   productID,
+		MediaIDs:    slices.Clone(mediaIDs),
+		Version:     product.Version,
+		ReorderedAt: currentTime,
+	})
+}
+
+func (s *productService) SetPrimaryMedia(productID, mediaID uuid.UUID) error {
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return err
+	}
+	media, ok := product.FindMedia(mediaID)
+	if !ok {
+		return fmt.Errorf("%w: %s", model.ErrMediaNotFound, mediaID)
+	}
+	if media.Primary {
+		return nil
+	}
+
+	currentTime := time.Now()
+	product.SetPrimaryMedia(mediaID)
+	product.Version++
+	product.UpdatedAt = currentTime
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.ProductMediaPrimaryChanged{
+		ProductID: productID,
+		MediaID:   mediaID,
+		Version:   product.Version,
+		ChangedAt: currentTime,
+	})
+}
+
+func (s *productService) RemoveMedia(productID, mediaID uuid.UUID) error {
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return err
+	}
+	media, ok := product.FindMedia(mediaID)
+	if !ok {
+		return fmt.Errorf("%w: %s", model.ErrMediaNotFound, mediaID)
+	}
+	wasPrimary := media.Primary
+
+	currentTime := time.Now()
+	product.Media = slices.DeleteFunc(product.Media, func(m model.Media) bool {
+		return m.MediaID == mediaID
+	})
+	var primaryMediaID *uuid.UUID
+	if wasPrimary && len(product.Media) > 0 {
+		product.Media[0].Primary = true
+		primaryMediaID = &product.Media[0].MediaID
+	}
+	product.Version++
+	product.UpdatedAt = currentTime
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.ProductMediaRemoved{
+		ProductID:      productID,
+		MediaID:        mediaID,
+		PrimaryMediaID: primaryMediaID,
+		Version:        product.Version,
+		RemovedAt:      currentTime,
+	})
+}
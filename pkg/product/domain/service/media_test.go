@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/product/domain/model"
+	"productservice/pkg/product/domain/service"
+)
+
+func image(url string) model.MediaCreate {
+	return model.MediaCreate{URL: url, ContentType: "image/jpeg"}
+}
+
+func TestProductService_AddMedia(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID, err := productService.CreateProduct(model.ProductCreate{Name: "T-Shirt", Prices: rub(1000)})
+	require.NoError(t, err)
+
+	// Act
+	firstID, err := productService.AddMedia(productID, image("https://cdn.example.com/front.jpg"))
+	require.NoError(t, err)
+	secondID, err := productService.AddMedia(productID, model.MediaCreate{
+		URL:         " https://cdn.example.com/back.jpg ",
+		AltText:     " Back ",
+		Width:       800,
+		Height:      600,
+		ContentType: "Image/JPEG",
+		Primary:     true,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	product := repo.products[productID]
+	assert.Equal(t, int64(3), product.Version)
+	assert.Equal(t, []model.Media{
+		{MediaID: firstID, URL: "https://cdn.example.com/front.jpg", ContentType: "image/jpeg"},
+		{
+			MediaID:     secondID,
+			URL:         "https://cdn.example.com/back.jpg",
+			AltText:     "Back",
+			Width:       800,
+			Height:      600,
+			ContentType: "image/jpeg",
+			Primary:     true,
+		},
+	}, product.Media)
+
+	addedEvent, ok := dispatcher.dispatchedEvents[len(dispatcher.dispatchedEvents)-1].(*model.ProductMediaAdded)
+	require.True(t, ok)
+	assert.Equal(t, secondID, addedEvent.Media.MediaID)
+	assert.Equal(t, product.Version, addedEvent.Version)
+}
+
+func TestProductService_AddMedia_Invalid(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	productService := service.NewProductService(repo, &mockEventDispatcher{})
+	productID, err := productService.CreateProduct(model.ProductCreate{Name: "T-Shirt", Prices: rub(1000)})
+	require.NoError(t, err)
+
+	// Act & Assert
+	for _, media := range []model.MediaCreate{
+		{ContentType: "image/png"},
+		{URL: "https://cdn.example.com/a.png", StorageKey: "a.png", ContentType: "image/png"},
+		{URL: "ftp://cdn.example.com/a.png", ContentType: "image/png"},
+		{URL: "https://cdn.example.com/a.pdf", ContentType: "application/pdf"},
+		{URL: "https://cdn.example.com/a.svg", ContentType: "image/svg+xml"},
+		{URL: "https://cdn.example.com/a.png", ContentType: "image/png", Width: -1},
+	} {
+		_, err = productService.AddMedia(productID, media)
+		assert.ErrorIs(t, err, model.ErrInvalidMedia)
+	}
+	assert.Empty(t, repo.products[productID].Media)
+}
+
+func TestProductService_ReorderAndRemoveMedia(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID, err := productService.CreateProduct(model.ProductCreate{Name: "T-Shirt", Prices: rub(1000)})
+	require.NoError(t, err)
+	var mediaIDs []uuid.UUID
+	for _, url := range []string{"https://cdn.example.com/1.jpg", "https://cdn.example.com/2.jpg", "https://cdn.example.com/3.jpg"} {
+		mediaID, err := productService.AddMedia(productID, image(url))
+		require.NoError(t, err)
+		mediaIDs = append(mediaIDs, mediaID)
+	}
+
+	// Act & Assert
+	err = productService.ReorderMedia(productID, mediaIDs[:2])
+	assert.ErrorIs(t, err, model.ErrInvalidMediaOrder)
+	err = productService.ReorderMedia(productID, []uuid.UUID{mediaIDs[0], mediaIDs[0], mediaIDs[1]})
+	assert.ErrorIs(t, err, model.ErrInvalidMediaOrder)
+	err = productService.ReorderMedia(productID, []uuid.UUID{mediaIDs[0], mediaIDs[1], uuid.New()})
+	assert.ErrorIs(t, err, model.ErrMediaNotFound)
+
+	err = productService.ReorderMedia(productID, []uuid.UUID{mediaIDs[2], mediaIDs[0], mediaIDs[1]})
+	require.NoError(t, err)
+	product := repo.products[productID]
+	assert.Equal(t, mediaIDs[2], product.Media[0].MediaID)
+	primary, ok := product.PrimaryMedia()
+	require.True(t, ok)
+	assert.Equal(t, mediaIDs[0], primary.MediaID)
+
+	err = productService.RemoveMedia(productID, mediaIDs[0])
+	require.NoError(t, err)
+	product = repo.products[productID]
+	require.Len(t, product.Media, 2)
+	primary, ok = product.PrimaryMedia()
+	require.True(t, ok)
+	assert.Equal(t, mediaIDs[2], primary.MediaID)
+	removedEvent, ok := dispatcher.dispatchedEvents[len(dispatcher.dispatchedEvents)-1].(*model.ProductMediaRemoved)
+	require.True(t, ok)
+	require.NotNil(t, removedEvent.PrimaryMediaID)
+	assert.Equal(t, mediaIDs[2], *removedEvent.PrimaryMediaID)
+
+	err = productService.SetPrimaryMedia(productID, mediaIDs[1])
+	require.NoError(t, err)
+	product = repo.products[productID]
+	primary, _ = product.PrimaryMedia()
+	assert.Equal(t, mediaIDs[1], primary.MediaID)
+
+	err = productService.RemoveMedia(productID, mediaIDs[0])
+	assert.ErrorIs(t, err, model.ErrMediaNotFound)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"productservice/pkg/product/domain/model"
+)
+
+type PriceChangeService interface {
+	SchedulePriceChange(productID uuid.UUID, price model.Money, effectiveFrom time.Time) (uuid.UUID, error)
+	// ApplyDuePriceChanges применяет наступившие изменения цен продукта одним обновлением
+	ApplyDuePriceChanges(productID uuid.UUID) error
+}
+
+func NewPriceChangeService(
+	productRepository model.ProductRepository,
+	priceChangeRepository model.ScheduledPriceChangeRepository,
+	productService ProductService,
+) PriceChangeService {
+	return &priceChangeService{
+		productRepository:     productRepository,
+		priceChangeRepository: priceChangeRepository,
+		productService:        productService,
+	}
+}
+
+type priceChangeService struct {
+	productRepository     model.ProductRepository
+	priceChangeRepository model.ScheduledPriceChangeRepository
+	productService        ProductService
+}
+
+func (s *priceChangeService) SchedulePriceChange(productID uuid.UUID, price model.Money, effectiveFrom time.Time) (uuid.UUID, error) {
+	price = model.NormalizePrices([]model.Money{price})[0]
+	err := model.ValidateMoney(price)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	_, err = s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	changeID, err := s.priceChangeRepository.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return changeID, s.priceChangeRepository.Store(model.ScheduledPriceChange{
+		ChangeID:      changeID,
+		ProductID:     productID,
+		Price:         price,
+		EffectiveFrom: effectiveFrom,
+		CreatedAt:     time.Now(),
+	})
+}
+
+func (s *priceChangeService) ApplyDuePriceChanges(productID uuid.UUID) error {
+	currentTime := time.Now()
+	changes, err := s.priceChangeRepository.FindDue(productID, currentTime)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return err
+	}
+
+	prices := make(map[string]int64, len(product.Prices))
+	for _, price := range product.Prices {
+		prices[price.Currency] = price.Amount
+	}
+	// Изменения упорядочены по EffectiveFrom, поэтому более позднее перекрывает более раннее
+	effectiveFrom := make(map[string]time.Time, len(changes))
+	for _, change := range changes {
+		prices[change.Price.Currency] = change.Price.Amount
+		effectiveFrom[change.Price.Currency] = change.EffectiveFrom
+	}
+	newPrices := make([]model.Money, 0, len(prices))
+	for currency, amount := range prices {
+		newPrices = append(newPrices, model.Money{Amount: amount, Currency: currency})
+	}
+
+	err = s.productService.UpdateProduct(productID, model.ProductUpdate{Prices: &newPrices, PricesEffectiveFrom: effectiveFrom}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		change.AppliedAt = &currentTime
+		err = s.priceChangeRepository.Store(change)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
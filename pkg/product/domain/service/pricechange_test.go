@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/product/domain/model"
+	"productservice/pkg/product/domain/service"
+)
+
+// --- Mocks ---
+type mockScheduledPriceChangeRepository struct {
+	changes map[uuid.UUID]model.ScheduledPriceChange
+}
+
+func newMockScheduledPriceChangeRepository() *mockScheduledPriceChangeRepository {
+	return &mockScheduledPriceChangeRepository{
+		changes: make(map[uuid.UUID]model.ScheduledPriceChange),
+	}
+}
+
+func (m *mockScheduledPriceChangeRepository) NextID() (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+func (m *mockScheduledPriceChangeRepository) Store(change model.ScheduledPriceChange) error {
+	m.changes[change.ChangeID] = change
+	return nil
+}
+
+func (m *mockScheduledPriceChangeRepository) FindDue(productID uuid.UUID, at time.Time) ([]model.ScheduledPriceChange, error) {
+	var result []model.ScheduledPriceChange
+	for _, change := range m.changes {
+		if change.ProductID == productID && change.AppliedAt == nil && !change.EffectiveFrom.After(at) {
+			result = append(result, change)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].EffectiveFrom.Before(result[j].EffectiveFrom)
+	})
+	return result, nil
+}
+
+func (m *mockScheduledPriceChangeRepository) FindDueProductIDs(at time.Time, limit int) ([]uuid.UUID, error) {
+	var result []uuid.UUID
+	for _, change := range m.changes {
+		if change.AppliedAt == nil && !change.EffectiveFrom.After(at) && len(result) < limit {
+			result = append(result, change.ProductID)
+		}
+	}
+	return result, nil
+}
+
+// --- Tests ---
+
+func TestPriceChangeService_ApplyDuePriceChanges(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	changeRepo := newMockScheduledPriceChangeRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	priceChangeService := service.NewPriceChangeService(repo, changeRepo, productService)
+
+	productID := uuid.New()
+	repo.products[productID] = model.Product{
+		ProductID: productID,
+		Name:      "Coffee",
+		Prices:    []model.Money{{Amount: 300, Currency: "RUB"}, {Amount: 4, Currency: "USD"}},
+		Version:   1,
+	}
+
+	now := time.Now()
+	_, err := priceChangeService.SchedulePriceChange(productID, model.Money{Amount: 350, Currency: "rub"}, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	_, err = priceChangeService.SchedulePriceChange(productID, model.Money{Amount: 400, Currency: "RUB"}, now.Add(-time.Hour))
+	require.NoError(t, err)
+	futureChangeID, err := priceChangeService.SchedulePriceChange(productID, model.Money{Amount: 500, Currency: "RUB"}, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	// Act
+	err = priceChangeService.ApplyDuePriceChanges(productID)
+
+	// Assert
+	require.NoError(t, err)
+	// Более позднее изменение перекрывает более раннее, цены в других валютах не меняются
+	assert.Equal(t, []model.Money{{Amount: 400, Currency: "RUB"}, {Amount: 4, Currency: "USD"}}, repo.products[productID].Prices)
+	assert.Equal(t, int64(2), repo.products[productID].Version)
+	// В историю цен попадает момент вступления изменения в силу, а не момент применения
+	assert.Equal(t, map[string]time.Time{"RUB": now.Add(-time.Hour)}, repo.products[productID].PricesEffectiveFrom)
+
+	require.Len(t, dispatcher.dispatchedEvents, 1)
+	updatedEvent, ok := dispatcher.dispatchedEvents[0].(*model.ProductUpdated)
+	require.True(t, ok)
+	require.NotNil(t, updatedEvent.UpdatedFields.Prices)
+
+	for changeID, change := range changeRepo.changes {
+		assert.Equal(t, changeID != futureChangeID, change.AppliedAt != nil)
+	}
+
+	// Повторное применение ничего не меняет
+	err = priceChangeService.ApplyDuePriceChanges(productID)
+	require.NoError(t, err)
+	assert.Len(t, dispatcher.dispatchedEvents, 1)
+}
+
+func TestPriceChangeService_SchedulePriceChange_InvalidPrice(t *testing.T) {
+	repo := newMockProductRepository()
+	priceChangeService := service.NewPriceChangeService(repo, newMockScheduledPriceChangeRepository(), service.NewProductService(repo, &mockEventDispatcher{}))
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Coffee", Prices: rub(300)}
+
+	_, err := priceChangeService.SchedulePriceChange(productID, model.Money{Amount: -1, Currency: "RUB"}, time.Now())
+	assert.ErrorIs(t, err, model.ErrInvalidPrice)
+
+	_, err = priceChangeService.SchedulePriceChange(uuid.New(), model.Money{Amount: 100, Currency: "RUB"}, time.Now())
+	assert.ErrorIs(t, err, model.ErrProductNotFound)
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,9 +13,28 @@ import (
 )
 
 type ProductService interface {
-	CreateProduct(name string, price int64) (uuid.UUID, error)
-	UpdateProduct(productID uuid.UUID, name string, price int64) error
+	CreateProduct(product model.ProductCreate) (uuid.UUID, error)
+	// UpdateProduct меняет только заданные в update поля.
+	// При непустом expectedVersion обновляет продукт, только если его версия совпадает
+	UpdateProduct(productID uuid.UUID, update model.ProductUpdate, expectedVersion *int64) error
 	DeleteProduct(productID uuid.UUID) error
+	RestoreProduct(productID uuid.UUID) error
+	// PublishProduct переводит черновик или архивный продукт в статус active,
+	// ArchiveProduct переводит активный продукт в архив. Повторный перевод в текущий статус ничего не меняет
+	PublishProduct(productID uuid.UUID) error
+	ArchiveProduct(productID uuid.UUID) error
+
+	AddVariant(productID uuid.UUID, variant model.VariantCreate) (uuid.UUID, error)
+	UpdateVariant(productID, variantID uuid.UUID, update model.VariantUpdate) error
+	RemoveVariant(productID, variantID uuid.UUID) error
+
+	// AddMedia добавляет медиа в конец списка, первое медиа продукта всегда становится основным
+	AddMedia(productID uuid.UUID, media model.MediaCreate) (uuid.UUID, error)
+	// ReorderMedia задает новый порядок, mediaIDs должны перечислять все медиа продукта
+	ReorderMedia(productID uuid.UUID, mediaIDs []uuid.UUID) error
+	SetPrimaryMedia(productID, mediaID uuid.UUID) error
+	// RemoveMedia удаляет медиа, при удалении основного основным становится первое из оставшихся
+	RemoveMedia(productID, mediaID uuid.UUID) error
 }
 
 func NewProductService(
@@ -31,15 +52,40 @@ type productService struct {
 	eventDispatcher   domain.EventDispatcher
 }
 
-func (s *productService) CreateProduct(name string, price int64) (uuid.UUID, error) {
-	_, err := s.productRepository.Find(model.FindSpec{
-		Name: &name,
+func (s *productService) CreateProduct(product model.ProductCreate) (uuid.UUID, error) {
+	name := model.NormalizeName(product.Name)
+	description := model.NormalizeDescription(product.Description)
+	translations := model.NormalizeTranslations(product.Translations)
+	prices := model.NormalizePrices(product.Prices)
+	sku := model.NormalizeSKU(product.SKU)
+	barcodes := model.NormalizeBarcodes(product.Barcodes)
+	err := errors.Join(
+		model.ValidateName(name),
+		model.ValidateDescription(description),
+		model.ValidateTranslations(translations),
+		model.ValidatePrices(prices),
+		model.ValidateSKU(sku),
+		model.ValidateBarcodes(barcodes),
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// Имя удаленного продукта остается занятым, чтобы его можно было восстановить
+	_, err = s.productRepository.Find(model.FindSpec{
+		Name:           &name,
+		IncludeDeleted: true,
 	})
 	if err != nil && !errors.Is(err, model.ErrProductNotFound) {
 		return uuid.Nil, err
 	}
 	if err == nil {
-		return uuid.Nil, model.ErrProductNameAlreadyUsed
+		return uuid.Nil, model.ProductNameAlreadyUsedError{Name: name}
+	}
+
+	err = s.checkIdentifiers(uuid.Nil, sku, barcodes)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
 	productID, err := s.productRepository.NextID()
@@ -49,25 +95,69 @@ func (s *productService) CreateProduct(name string, price int64) (uuid.UUID, err
 
 	currentTime := time.Now()
 	err = s.productRepository.Store(model.Product{
-		ProductID: productID,
-		Name:      name,
-		Price:     price,
-		CreatedAt: currentTime,
-		UpdatedAt: currentTime,
+		ProductID:    productID,
+		Name:         name,
+		Description:  description,
+		Translations: translations,
+		Prices:       prices,
+		SKU:          sku,
+		Barcodes:     barcodes,
+		Status:       model.ProductStatusDraft,
+		Version:      1,
+		CreatedAt:    currentTime,
+		UpdatedAt:    currentTime,
 	})
 	if err != nil {
 		return uuid.Nil, err
 	}
 
 	return productID, s.eventDispatcher.Dispatch(&model.ProductCreated{
-		ProductID: productID,
-		Name:      name,
-		Price:     price,
-		CreatedAt: currentTime,
+		ProductID:    productID,
+		Name:         name,
+		Description:  description,
+		Translations: translations,
+		Prices:       prices,
+		SKU:          sku,
+		Barcodes:     barcodes,
+		Status:       model.ProductStatusDraft,
+		CreatedAt:    currentTime,
 	})
 }
 
-func (s *productService) UpdateProduct(productID uuid.UUID, name string, price int64) error {
+func (s *productService) UpdateProduct(productID uuid.UUID, update model.ProductUpdate, expectedVersion *int64) error {
+	if update.Name != nil {
+		name := model.NormalizeName(*update.Name)
+		update.Name = &name
+	}
+	if update.Description != nil {
+		description := model.NormalizeDescription(*update.Description)
+		update.Description = &description
+	}
+	if update.Translations != nil {
+		translations := model.NormalizeTranslations(*update.Translations)
+		update.Translations = &translations
+	}
+	if update.Prices != nil {
+		prices := model.NormalizePrices(*update.Prices)
+		update.Prices = &prices
+	}
+	if update.SKU != nil {
+		sku := model.NormalizeSKU(*update.SKU)
+		update.SKU = &sku
+	}
+	if update.Barcodes != nil {
+		barcodes := model.NormalizeBarcodes(*update.Barcodes)
+		update.Barcodes = &barcodes
+	}
+	if update.CategoryIDs != nil {
+		categoryIDs := model.NormalizeCategoryIDs(*update.CategoryIDs)
+		update.CategoryIDs = &categoryIDs
+	}
+	err := validateProductUpdate(update)
+	if err != nil {
+		return err
+	}
+
 	product, err := s.productRepository.Find(model.FindSpec{
 		ProductID: &productID,
 	})
@@ -75,23 +165,112 @@ func (s *productService) UpdateProduct(productID uuid.UUID, name string, price i
 		return err
 	}
 
-	if product.Name == name && product.Price == price {
-		return nil
+	if expectedVersion != nil && *expectedVersion != product.Version {
+		return fmt.Errorf("%w: expected %d, actual %d", model.ErrProductVersionMismatch, *expectedVersion, product.Version)
 	}
 
-	if product.Name != name {
-		existing, err := s.productRepository.Find(model.FindSpec{Name: &name})
+	updatedEvent := &model.ProductUpdated{
+		ProductID: productID,
+	}
+	changed := false
+
+	if update.Name != nil && *update.Name != product.Name {
+		name := *update.Name
+		existing, err := s.productRepository.Find(model.FindSpec{Name: &name, IncludeDeleted: true})
 		if err != nil && !errors.Is(err, model.ErrProductNotFound) {
 			return err
 		}
 		if existing != nil && existing.ProductID != productID {
-			return model.ErrProductNameAlreadyUsed
+			return model.ProductNameAlreadyUsedError{Name: name}
+		}
+
+		previousName := product.Name
+		updatedEvent.PreviousFields.Name = &previousName
+		updatedEvent.UpdatedFields.Name = &name
+		product.Name = name
+		changed = true
+	}
+
+	if update.Description != nil && *update.Description != product.Description {
+		description := *update.Description
+		previousDescription := product.Description
+		updatedEvent.PreviousFields.Description = &previousDescription
+		updatedEvent.UpdatedFields.Description = &description
+		product.Description = description
+		changed = true
+	}
+
+	if update.Translations != nil && !slices.Equal(*update.Translations, product.Translations) {
+		translations := *update.Translations
+		previousTranslations := product.Translations
+		updatedEvent.PreviousFields.Translations = &previousTranslations
+		updatedEvent.UpdatedFields.Translations = &translations
+		product.Translations = translations
+		changed = true
+	}
+
+	if update.Prices != nil && !slices.Equal(*update.Prices, product.Prices) {
+		prices := *update.Prices
+		previousPrices := product.Prices
+		updatedEvent.PreviousFields.Prices = &previousPrices
+		updatedEvent.UpdatedFields.Prices = &prices
+		product.Prices = prices
+		product.PricesEffectiveFrom = update.PricesEffectiveFrom
+		changed = true
+	}
+
+	if update.SKU != nil && *update.SKU != product.SKU {
+		sku := *update.SKU
+		err = s.checkSKU(product, nil, sku)
+		if err != nil {
+			return err
+		}
+
+		previousSKU := product.SKU
+		updatedEvent.PreviousFields.SKU = &previousSKU
+		updatedEvent.UpdatedFields.SKU = &sku
+		product.SKU = sku
+		changed = true
+	}
+
+	if update.Barcodes != nil && !slices.Equal(*update.Barcodes, product.Barcodes) {
+		barcodes := *update.Barcodes
+		err = s.checkIdentifiers(productID, "", barcodes)
+		if err != nil {
+			return err
 		}
+
+		previousBarcodes := product.Barcodes
+		updatedEvent.PreviousFields.Barcodes = &previousBarcodes
+		updatedEvent.UpdatedFields.Barcodes = &barcodes
+		product.Barcodes = barcodes
+		changed = true
+	}
+
+	if update.Attributes != nil && !slices.Equal(*update.Attributes, product.Attributes) {
+		attributes := *update.Attributes
+		previousAttributes := product.Attributes
+		updatedEvent.PreviousFields.Attributes = &previousAttributes
+		updatedEvent.UpdatedFields.Attributes = &attributes
+		product.Attributes = attributes
+		changed = true
+	}
+
+	if update.CategoryIDs != nil && !slices.Equal(*update.CategoryIDs, product.CategoryIDs) {
+		categoryIDs := *update.CategoryIDs
+		previousCategoryIDs := product.CategoryIDs
+		updatedEvent.PreviousFields.CategoryIDs = &previousCategoryIDs
+		updatedEvent.UpdatedFields.CategoryIDs = &categoryIDs
+		product.CategoryIDs = categoryIDs
+		changed = true
+	}
+
+	if !changed {
+		return nil
 	}
 
 	currentTime := time.Now()
-	product.Name = name
-	product.Price = price
+	product.Version++
 	product.UpdatedAt = currentTime
 
 	err = s.productRepository.Store(*product)
@@ -99,31 +278,356 @@ func (s *productService) UpdateProduct(productID uuid.UUID, name string, price i
 		return err
 	}
 
-	updatedEvent := &model.ProductUpdated{
-		ProductID: productID,
-		UpdatedAt: currentTime,
+	updatedEvent.Version = product.Version
+	updatedEvent.UpdatedAt = currentTime
+	return s.eventDispatcher.Dispatch(updatedEvent)
+}
+
+func (s *productService) PublishProduct(productID uuid.UUID) error {
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return err
+	}
+	if product.Status == model.ProductStatusActive {
+		return nil
+	}
+	err = model.CheckStatusTransition(product.Status, model.ProductStatusActive)
+	if err != nil {
+		return err
 	}
-	updatedEvent.UpdatedFields.Name = &name
-	updatedEvent.UpdatedFields.Price = &price
 
-	return s.eventDispatcher.Dispatch(updatedEvent)
+	previousStatus := product.Status
+	currentTime := time.Now()
+	product.Status = model.ProductStatusActive
+	product.Version++
+	product.UpdatedAt = currentTime
+
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.ProductPublished{
+		ProductID:      productID,
+		PreviousStatus: previousStatus,
+		Version:        product.Version,
+		PublishedAt:    currentTime,
+	})
+}
+
+func (s *productService) ArchiveProduct(productID uuid.UUID) error {
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return err
+	}
+	if product.Status == model.ProductStatusArchived {
+		return nil
+	}
+	err = model.CheckStatusTransition(product.Status, model.ProductStatusArchived)
+	if err != nil {
+		return err
+	}
+
+	currentTime := time.Now()
+	product.Status = model.ProductStatusArchived
+	product.Version++
+	product.UpdatedAt = currentTime
+
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.ProductArchived{
+		ProductID:  productID,
+		Version:    product.Version,
+		ArchivedAt: currentTime,
+	})
 }
 
 func (s *productService) DeleteProduct(productID uuid.UUID) error {
-	_, err := s.productRepository.Find(model.FindSpec{
+	product, err := s.productRepository.Find(model.FindSpec{
 		ProductID: &productID,
 	})
 	if err != nil {
 		return err
 	}
 
-	err = s.productRepository.HardDelete(productID)
+	currentTime := time.Now()
+	product.DeletedAt = &currentTime
+	product.Version++
+	product.UpdatedAt = currentTime
+
+	err = s.productRepository.Store(*product)
 	if err != nil {
 		return err
 	}
 
 	return s.eventDispatcher.Dispatch(&model.ProductDeleted{
 		ProductID: productID,
-		DeletedAt: time.Now(),
+		DeletedAt: currentTime,
 	})
 }
+
+func (s *productService) RestoreProduct(productID uuid.UUID) error {
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID:      &productID,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if product.DeletedAt == nil {
+		return nil
+	}
+
+	currentTime := time.Now()
+	product.DeletedAt = nil
+	product.Version++
+	product.UpdatedAt = currentTime
+
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.ProductRestored{
+		ProductID:  productID,
+		RestoredAt: currentTime,
+	})
+}
+
+func (s *productService) AddVariant(productID uuid.UUID, variant model.VariantCreate) (uuid.UUID, error) {
+	options := model.NormalizeVariantOptions(variant.Options)
+	prices := model.NormalizePrices(variant.Prices)
+	sku := model.NormalizeSKU(variant.SKU)
+	err := errors.Join(
+		model.ValidateVariantOptions(options),
+		model.ValidateVariantPrices(prices),
+		model.ValidateSKU(sku),
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	err = errors.Join(checkVariantOptions(product, uuid.Nil, options), s.checkSKU(product, &uuid.Nil, sku))
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	variantID, err := s.productRepository.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	newVariant := model.Variant{
+		VariantID: variantID,
+		Options:   options,
+		Prices:    prices,
+		SKU:       sku,
+	}
+
+	currentTime := time.Now()
+	product.Variants = append(product.Variants, newVariant)
+	product.Version++
+	product.UpdatedAt = currentTime
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return variantID, s.eventDispatcher.Dispatch(&model.ProductVariantAdded{
+		ProductID: productID,
+		Variant:   newVariant,
+		Version:   product.Version,
+		AddedAt:   currentTime,
+	})
+}
+
+func (s *productService) UpdateVariant(productID, variantID uuid.UUID, update model.VariantUpdate) error {
+	var errs []error
+	if update.Options != nil {
+		options := model.NormalizeVariantOptions(*update.Options)
+		update.Options = &options
+		errs = append(errs, model.ValidateVariantOptions(options))
+	}
+	if update.Prices != nil {
+		prices := model.NormalizePrices(*update.Prices)
+		update.Prices = &prices
+		errs = append(errs, model.ValidateVariantPrices(prices))
+	}
+	if update.SKU != nil {
+		sku := model.NormalizeSKU(*update.SKU)
+		update.SKU = &sku
+		errs = append(errs, model.ValidateSKU(sku))
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		return err
+	}
+
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return err
+	}
+	variant, ok := product.FindVariant(variantID)
+	if !ok {
+		return fmt.Errorf("%w: %s", model.ErrVariantNotFound, variantID)
+	}
+
+	changed := false
+	if update.Options != nil && !slices.Equal(*update.Options, variant.Options) {
+		err = checkVariantOptions(product, variantID, *update.Options)
+		if err != nil {
+			return err
+		}
+		variant.Options = *update.Options
+		changed = true
+	}
+	if update.Prices != nil && !slices.Equal(*update.Prices, variant.Prices) {
+		variant.Prices = *update.Prices
+		changed = true
+	}
+	if update.SKU != nil && *update.SKU != variant.SKU {
+		err = s.checkSKU(product, &variantID, *update.SKU)
+		if err != nil {
+			return err
+		}
+		variant.SKU = *update.SKU
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	currentTime := time.Now()
+	product.Version++
+	product.UpdatedAt = currentTime
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.ProductVariantUpdated{
+		ProductID: productID,
+		Variant:   *variant,
+		Version:   product.Version,
+		UpdatedAt: currentTime,
+	})
+}
+
+func (s *productService) RemoveVariant(productID, variantID uuid.UUID) error {
+	product, err := s.productRepository.Find(model.FindSpec{
+		ProductID: &productID,
+	})
+	if err != nil {
+		return err
+	}
+	if _, ok := product.FindVariant(variantID); !ok {
+		return fmt.Errorf("%w: %s", model.ErrVariantNotFound, variantID)
+	}
+
+	currentTime := time.Now()
+	product.Variants = slices.DeleteFunc(product.Variants, func(v model.Variant) bool {
+		return v.VariantID == variantID
+	})
+	product.Version++
+	product.UpdatedAt = currentTime
+	err = s.productRepository.Store(*product)
+	if err != nil {
+		return err
+	}
+
+	return s.eventDispatcher.Dispatch(&model.ProductVariantRemoved{
+		ProductID: productID,
+		VariantID: variantID,
+		Version:   product.Version,
+		RemovedAt: currentTime,
+	})
+}
+
+// checkVariantOptions проверяет, что у продукта нет другого варианта с теми же опциями
+func checkVariantOptions(product *model.Product, variantID uuid.UUID, options []model.VariantOption) error {
+	for _, variant := range product.Variants {
+		if variant.VariantID != variantID && slices.Equal(variant.Options, options) {
+			return fmt.Errorf("%w: variant %s", model.ErrVariantOptionsAlreadyUsed, variant.VariantID)
+		}
+	}
+	return nil
+}
+
+// checkSKU проверяет, что SKU не занят самим продуктом, другим его вариантом или другим продуктом.
+// variantID nil означает проверку SKU самого продукта
+func (s *productService) checkSKU(product *model.Product, variantID *uuid.UUID, sku string) error {
+	if sku == "" {
+		return nil
+	}
+	used := variantID != nil && product.SKU == sku
+	for _, variant := range product.Variants {
+		if (variantID == nil || variant.VariantID != *variantID) && variant.SKU == sku {
+			used = true
+		}
+	}
+	if used {
+		return fmt.Errorf("%w: %q", model.ErrSKUAlreadyUsed, sku)
+	}
+	return s.checkIdentifiers(product.ProductID, sku, nil)
+}
+
+// checkIdentifiers проверяет, что SKU и штрихкоды не заняты другими продуктами, в том числе удаленными
+func (s *productService) checkIdentifiers(productID uuid.UUID, sku string, barcodes []string) error {
+	if sku != "" {
+		existing, err := s.productRepository.Find(model.FindSpec{SKU: &sku, IncludeDeleted: true})
+		if err != nil && !errors.Is(err, model.ErrProductNotFound) {
+			return err
+		}
+		if existing != nil && existing.ProductID != productID {
+			return fmt.Errorf("%w: %q", model.ErrSKUAlreadyUsed, sku)
+		}
+	}
+	for _, barcode := range barcodes {
+		existing, err := s.productRepository.Find(model.FindSpec{Barcode: &barcode, IncludeDeleted: true})
+		if err != nil && !errors.Is(err, model.ErrProductNotFound) {
+			return err
+		}
+		if existing != nil && existing.ProductID != productID {
+			return fmt.Errorf("%w: %q", model.ErrBarcodeAlreadyUsed, barcode)
+		}
+	}
+	return nil
+}
+
+func validateProductUpdate(update model.ProductUpdate) error {
+	var errs []error
+	if update.Name != nil {
+		errs = append(errs, model.ValidateName(*update.Name))
+	}
+	if update.Description != nil {
+		errs = append(errs, model.ValidateDescription(*update.Description))
+	}
+	if update.Translations != nil {
+		errs = append(errs, model.ValidateTranslations(*update.Translations))
+	}
+	if update.Prices != nil {
+		errs = append(errs, model.ValidatePrices(*update.Prices))
+	}
+	if update.SKU != nil {
+		errs = append(errs, model.ValidateSKU(*update.SKU))
+	}
+	if update.Barcodes != nil {
+		errs = append(errs, model.ValidateBarcodes(*update.Barcodes))
+	}
+	return errors.Join(errs...)
+}
@@ -2,6 +2,8 @@ package service_test
 
 import (
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -47,26 +49,43 @@ func (m *mockProductRepository) Find(spec model.FindSpec) (*model.Product, error
 		return nil, m.simulateError
 	}
 	if spec.ProductID != nil {
-		if p, ok := m.products[*spec.ProductID]; ok {
+		if p, ok := m.products[*spec.ProductID]; ok && (spec.IncludeDeleted || p.DeletedAt == nil) {
 			return &p, nil
 		}
 	}
-	if spec.Name != nil {
-		for _, p := range m.products {
-			if p.Name == *spec.Name {
-				return &p, nil
-			}
+	for _, p := range m.products {
+		if !spec.IncludeDeleted && p.DeletedAt != nil {
+			continue
+		}
+		if spec.Name != nil && p.Name == *spec.Name ||
+			spec.SKU != nil && (p.SKU == *spec.SKU || slices.ContainsFunc(p.Variants, func(v model.Variant) bool { return v.SKU == *spec.SKU })) ||
+			spec.Barcode != nil && slices.Contains(p.Barcodes, *spec.Barcode) {
+			return &p, nil
 		}
 	}
 	return nil, model.ErrProductNotFound
 }
 
-func (m *mockProductRepository) HardDelete(productID uuid.UUID) error {
+func (m *mockProductRepository) PurgeDeleted(deletedBefore time.Time) (int64, []string, error) {
 	if m.simulateError != nil {
-		return m.simulateError
+		return 0, nil, m.simulateError
 	}
-	delete(m.products, productID)
-	return nil
+	var (
+		count       int64
+		storageKeys []string
+	)
+	for id, p := range m.products {
+		if p.DeletedAt != nil && p.DeletedAt.Before(deletedBefore) {
+			for _, media := range p.Media {
+				if media.StorageKey != "" {
+					storageKeys = append(storageKeys, media.StorageKey)
+				}
+			}
+			delete(m.products, id)
+			count++
+		}
+	}
+	return count, storageKeys, nil
 }
 
 type mockEventDispatcher struct {
@@ -78,6 +97,14 @@ func (m *mockEventDispatcher) Dispatch(event domain.Event) error {
 	return nil
 }
 
+func toPtr[T any](v T) *T {
+	return &v
+}
+
+func rub(amount int64) []model.Money {
+	return []model.Money{{Amount: amount, Currency: "RUB"}}
+}
+
 // --- Tests ---
 
 func TestProductService_CreateProduct_Success(t *testing.T) {
@@ -86,10 +113,10 @@ func TestProductService_CreateProduct_Success(t *testing.T) {
 	dispatcher := &mockEventDispatcher{}
 	productService := service.NewProductService(repo, dispatcher)
 	productName := "Test Coffee"
-	productPrice := int64(300)
+	productPrices := []model.Money{{Amount: 300, Currency: "rub"}, {Amount: 4, Currency: "USD"}}
 
 	// Act
-	productID, err := productService.CreateProduct(productName, productPrice)
+	productID, err := productService.CreateProduct(model.ProductCreate{Name: productName, Prices: productPrices})
 
 	// Assert
 	require.NoError(t, err)
@@ -99,7 +126,7 @@ func TestProductService_CreateProduct_Success(t *testing.T) {
 	storedProduct, ok := repo.products[productID]
 	require.True(t, ok)
 	assert.Equal(t, productName, storedProduct.Name)
-	assert.Equal(t, productPrice, storedProduct.Price)
+	assert.Equal(t, []model.Money{{Amount: 300, Currency: "RUB"}, {Amount: 4, Currency: "USD"}}, storedProduct.Prices)
 
 	// Проверяем, что было отправлено правильное событие
 	require.Len(t, dispatcher.dispatchedEvents, 1)
@@ -121,10 +148,290 @@ func TestProductService_CreateProduct_NameAlreadyExists(t *testing.T) {
 	repo.products[uuid.New()] = model.Product{Name: existingProductName}
 
 	// Act
-	_, err := productService.CreateProduct(existingProductName, 500)
+	_, err := productService.CreateProduct(model.ProductCreate{Name: existingProductName, Prices: rub(500)})
 
 	// Assert
 	require.Error(t, err)
 	assert.True(t, errors.Is(err, model.ErrProductNameAlreadyUsed))
 	assert.Empty(t, dispatcher.dispatchedEvents)
 }
+
+func TestProductService_DeleteProduct_SoftDeletes(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea"}
+
+	// Act
+	err := productService.DeleteProduct(productID)
+
+	// Assert
+	require.NoError(t, err)
+	storedProduct, ok := repo.products[productID]
+	require.True(t, ok)
+	require.NotNil(t, storedProduct.DeletedAt)
+
+	_, err = repo.Find(model.FindSpec{ProductID: &productID})
+	assert.True(t, errors.Is(err, model.ErrProductNotFound))
+
+	require.Len(t, dispatcher.dispatchedEvents, 1)
+	deletedEvent, ok := dispatcher.dispatchedEvents[0].(*model.ProductDeleted)
+	require.True(t, ok)
+	assert.Equal(t, productID, deletedEvent.ProductID)
+}
+
+func TestProductService_RestoreProduct_Success(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	deletedAt := time.Now().Add(-time.Hour)
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", DeletedAt: &deletedAt}
+
+	// Act
+	err := productService.RestoreProduct(productID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Nil(t, repo.products[productID].DeletedAt)
+
+	require.Len(t, dispatcher.dispatchedEvents, 1)
+	restoredEvent, ok := dispatcher.dispatchedEvents[0].(*model.ProductRestored)
+	require.True(t, ok)
+	assert.Equal(t, productID, restoredEvent.ProductID)
+}
+
+func TestProductService_CreateProduct_NameOfDeletedProduct(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	deletedAt := time.Now()
+	repo.products[uuid.New()] = model.Product{Name: "Black Tea", DeletedAt: &deletedAt}
+
+	// Act
+	_, err := productService.CreateProduct(model.ProductCreate{Name: "Black Tea", Prices: rub(500)})
+
+	// Assert
+	assert.True(t, errors.Is(err, model.ErrProductNameAlreadyUsed))
+	assert.Empty(t, dispatcher.dispatchedEvents)
+}
+
+func TestProductService_CreateProduct_NormalizesName(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+
+	// Act
+	productID, err := productService.CreateProduct(model.ProductCreate{Name: "  Café Latte \t", Prices: rub(250)})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Café Latte", repo.products[productID].Name)
+}
+
+func TestProductService_CreateProduct_InvalidInput(t *testing.T) {
+	testCases := []struct {
+		name        string
+		productName string
+		prices      []model.Money
+		expectedErr error
+	}{
+		{name: "empty name", productName: "", prices: rub(100), expectedErr: model.ErrInvalidName},
+		{name: "whitespace-only name", productName: " \t\n ", prices: rub(100), expectedErr: model.ErrInvalidName},
+		{name: "too long name", productName: strings.Repeat("я", model.MaxNameLength+1), prices: rub(100), expectedErr: model.ErrInvalidName},
+		{name: "control characters", productName: "Tea\x00", prices: rub(100), expectedErr: model.ErrInvalidName},
+		{name: "negative price", productName: "Tea", prices: rub(-1), expectedErr: model.ErrInvalidPrice},
+		{name: "no prices", productName: "Tea", prices: nil, expectedErr: model.ErrInvalidPrice},
+		{name: "duplicate currency", productName: "Tea", prices: append(rub(100), rub(200)...), expectedErr: model.ErrInvalidPrice},
+		{name: "unknown currency", productName: "Tea", prices: []model.Money{{Amount: 100, Currency: "ABC"}}, expectedErr: model.ErrInvalidCurrency},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := newMockProductRepository()
+			dispatcher := &mockEventDispatcher{}
+			productService := service.NewProductService(repo, dispatcher)
+
+			// Act
+			_, err := productService.CreateProduct(model.ProductCreate{Name: tc.productName, Prices: tc.prices})
+
+			// Assert
+			assert.True(t, errors.Is(err, tc.expectedErr))
+			assert.Empty(t, repo.products)
+			assert.Empty(t, dispatcher.dispatchedEvents)
+		})
+	}
+}
+
+func TestProductService_UpdateProduct_InvalidPrice(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Prices: rub(100)}
+
+	// Act
+	err := productService.UpdateProduct(productID, model.ProductUpdate{Prices: toPtr(rub(-100))}, nil)
+
+	// Assert
+	assert.True(t, errors.Is(err, model.ErrInvalidPrice))
+	assert.Equal(t, rub(100), repo.products[productID].Prices)
+	assert.Empty(t, dispatcher.dispatchedEvents)
+}
+
+func TestProductService_UpdateProduct_IncrementsVersion(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Prices: rub(100), Version: 3}
+	expectedVersion := int64(3)
+
+	// Act
+	err := productService.UpdateProduct(productID, model.ProductUpdate{Prices: toPtr(rub(150))}, &expectedVersion)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), repo.products[productID].Version)
+
+	require.Len(t, dispatcher.dispatchedEvents, 1)
+	updatedEvent, ok := dispatcher.dispatchedEvents[0].(*model.ProductUpdated)
+	require.True(t, ok)
+	assert.Equal(t, int64(4), updatedEvent.Version)
+}
+
+func TestProductService_UpdateProduct_VersionMismatch(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Prices: rub(100), Version: 3}
+	staleVersion := int64(2)
+
+	// Act
+	err := productService.UpdateProduct(productID, model.ProductUpdate{Prices: toPtr(rub(150))}, &staleVersion)
+
+	// Assert
+	assert.True(t, errors.Is(err, model.ErrProductVersionMismatch))
+	assert.Equal(t, rub(100), repo.products[productID].Prices)
+	assert.Empty(t, dispatcher.dispatchedEvents)
+}
+
+func TestProductService_UpdateProduct_EmitsOnlyChangedFields(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Prices: rub(100), Version: 1}
+
+	// Act
+	err := productService.UpdateProduct(productID, model.ProductUpdate{
+		Name:   toPtr("Green Tea"),
+		Prices: toPtr(rub(120)),
+	}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Green Tea", repo.products[productID].Name)
+	assert.Equal(t, rub(120), repo.products[productID].Prices)
+
+	require.Len(t, dispatcher.dispatchedEvents, 1)
+	updatedEvent, ok := dispatcher.dispatchedEvents[0].(*model.ProductUpdated)
+	require.True(t, ok)
+	assert.Nil(t, updatedEvent.UpdatedFields.Name)
+	assert.Nil(t, updatedEvent.PreviousFields.Name)
+	require.NotNil(t, updatedEvent.UpdatedFields.Prices)
+	assert.Equal(t, rub(120), *updatedEvent.UpdatedFields.Prices)
+	require.NotNil(t, updatedEvent.PreviousFields.Prices)
+	assert.Equal(t, rub(100), *updatedEvent.PreviousFields.Prices)
+}
+
+func TestProductService_UpdateProduct_NoChanges(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Prices: rub(100), Version: 1}
+
+	// Act
+	err := productService.UpdateProduct(productID, model.ProductUpdate{Name: toPtr(" Green Tea ")}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), repo.products[productID].Version)
+	assert.Empty(t, dispatcher.dispatchedEvents)
+}
+
+func TestProductService_CreateProduct_IdentifiersAlreadyUsed(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	_, err := productService.CreateProduct(model.ProductCreate{
+		Name:     "Green Tea",
+		Prices:   rub(100),
+		SKU:      "tea-001",
+		Barcodes: []string{"036000291452"},
+	})
+	require.NoError(t, err)
+
+	// Act & Assert
+	// SKU сравнивается без учета регистра, UPC-A совпадает с EAN-13 с ведущим нулем
+	_, err = productService.CreateProduct(model.ProductCreate{Name: "Black Tea", Prices: rub(100), SKU: "TEA-001"})
+	assert.ErrorIs(t, err, model.ErrSKUAlreadyUsed)
+	_, err = productService.CreateProduct(model.ProductCreate{Name: "Black Tea", Prices: rub(100), Barcodes: []string{"0036000291452"}})
+	assert.ErrorIs(t, err, model.ErrBarcodeAlreadyUsed)
+	_, err = productService.CreateProduct(model.ProductCreate{Name: "Black Tea", Prices: rub(100), Barcodes: []string{"036000291453"}})
+	assert.ErrorIs(t, err, model.ErrInvalidBarcode)
+	assert.Len(t, repo.products, 1)
+}
+
+func TestProductService_UpdateProduct_Translations(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{
+		ProductID:    productID,
+		Name:         "Зеленый чай",
+		Translations: []model.Translation{{Locale: "en", Name: "Green Tea"}},
+		Version:      1,
+	}
+
+	// Act
+	err := productService.UpdateProduct(productID, model.ProductUpdate{
+		Translations: &[]model.Translation{
+			{Locale: "pt-br", Name: "Chá Verde"},
+			{Locale: "en", Name: "Green Tea", Description: " Loose leaf "},
+		},
+	}, nil)
+
+	// Assert
+	require.NoError(t, err)
+	expected := []model.Translation{
+		{Locale: "en", Name: "Green Tea", Description: "Loose leaf"},
+		{Locale: "pt-BR", Name: "Chá Verde"},
+	}
+	assert.Equal(t, expected, repo.products[productID].Translations)
+	assert.Equal(t, "Зеленый чай", repo.products[productID].Name)
+
+	require.Len(t, dispatcher.dispatchedEvents, 1)
+	updatedEvent, ok := dispatcher.dispatchedEvents[0].(*model.ProductUpdated)
+	require.True(t, ok)
+	assert.Nil(t, updatedEvent.UpdatedFields.Name)
+	require.NotNil(t, updatedEvent.UpdatedFields.Translations)
+	assert.Equal(t, expected, *updatedEvent.UpdatedFields.Translations)
+	require.NotNil(t, updatedEvent.PreviousFields.Translations)
+	assert.Equal(t, []model.Translation{{Locale: "en", Name: "Green Tea"}}, *updatedEvent.PreviousFields.Translations)
+}
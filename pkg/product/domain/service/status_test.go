@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/product/domain/model"
+	"productservice/pkg/product/domain/service"
+)
+
+func TestProductService_CreateProduct_CreatesDraft(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+
+	// Act
+	productID, err := productService.CreateProduct(model.ProductCreate{Name: "Green Tea", Prices: rub(100)})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, model.ProductStatusDraft, repo.products[productID].Status)
+	require.Len(t, dispatcher.dispatchedEvents, 1)
+	assert.Equal(t, model.ProductStatusDraft, dispatcher.dispatchedEvents[0].(*model.ProductCreated).Status)
+}
+
+func TestProductService_PublishProduct(t *testing.T) {
+	for _, status := range []model.ProductStatus{model.ProductStatusDraft, model.ProductStatusArchived} {
+		t.Run(status.String(), func(t *testing.T) {
+			// Arrange
+			repo := newMockProductRepository()
+			dispatcher := &mockEventDispatcher{}
+			productService := service.NewProductService(repo, dispatcher)
+			productID := uuid.New()
+			repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Status: status, Version: 1}
+
+			// Act
+			err := productService.PublishProduct(productID)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, model.ProductStatusActive, repo.products[productID].Status)
+			assert.Equal(t, int64(2), repo.products[productID].Version)
+			require.Len(t, dispatcher.dispatchedEvents, 1)
+			publishedEvent, ok := dispatcher.dispatchedEvents[0].(*model.ProductPublished)
+			require.True(t, ok)
+			assert.Equal(t, status, publishedEvent.PreviousStatus)
+			assert.Equal(t, int64(2), publishedEvent.Version)
+		})
+	}
+}
+
+func TestProductService_ArchiveProduct(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Status: model.ProductStatusActive, Version: 1}
+
+	// Act
+	err := productService.ArchiveProduct(productID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, model.ProductStatusArchived, repo.products[productID].Status)
+	require.Len(t, dispatcher.dispatchedEvents, 1)
+	_, ok := dispatcher.dispatchedEvents[0].(*model.ProductArchived)
+	assert.True(t, ok)
+}
+
+func TestProductService_ArchiveProduct_DraftNotAllowed(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Status: model.ProductStatusDraft, Version: 1}
+
+	// Act
+	err := productService.ArchiveProduct(productID)
+
+	// Assert
+	assert.ErrorIs(t, err, model.ErrInvalidStatusTransition)
+	assert.Equal(t, model.ProductStatusDraft, repo.products[productID].Status)
+	assert.Empty(t, dispatcher.dispatchedEvents)
+}
+
+func TestProductService_PublishProduct_AlreadyActive(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID := uuid.New()
+	repo.products[productID] = model.Product{ProductID: productID, Name: "Green Tea", Status: model.ProductStatusActive, Version: 1}
+
+	// Act
+	err := productService.PublishProduct(productID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), repo.products[productID].Version)
+	assert.Empty(t, dispatcher.dispatchedEvents)
+}
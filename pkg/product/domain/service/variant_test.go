@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/product/domain/model"
+	"productservice/pkg/product/domain/service"
+)
+
+func size(value string) []model.VariantOption {
+	return []model.VariantOption{{Name: "Size", Value: value}}
+}
+
+func TestProductService_AddVariant(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID, err := productService.CreateProduct(model.ProductCreate{Name: "T-Shirt", Prices: rub(1000), SKU: "TS"})
+	require.NoError(t, err)
+
+	// Act
+	variantID, err := productService.AddVariant(productID, model.VariantCreate{
+		Options: []model.VariantOption{{Name: " Color", Value: "Red "}, {Name: "size", Value: "M"}},
+		Prices:  rub(1200),
+		SKU:     "ts-red-m",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	product := repo.products[productID]
+	assert.Equal(t, int64(2), product.Version)
+	require.Len(t, product.Variants, 1)
+	assert.Equal(t, model.Variant{
+		VariantID: variantID,
+		Options:   []model.VariantOption{{Name: "color", Value: "Red"}, {Name: "size", Value: "M"}},
+		Prices:    rub(1200),
+		SKU:       "TS-RED-M",
+	}, product.Variants[0])
+
+	addedEvent, ok := dispatcher.dispatchedEvents[len(dispatcher.dispatchedEvents)-1].(*model.ProductVariantAdded)
+	require.True(t, ok)
+	assert.Equal(t, variantID, addedEvent.Variant.VariantID)
+	assert.Equal(t, product.Version, addedEvent.Version)
+}
+
+func TestProductService_AddVariant_Conflicts(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	productService := service.NewProductService(repo, &mockEventDispatcher{})
+	productID, err := productService.CreateProduct(model.ProductCreate{Name: "T-Shirt", Prices: rub(1000), SKU: "TS"})
+	require.NoError(t, err)
+	_, err = productService.AddVariant(productID, model.VariantCreate{Options: size("M"), SKU: "TS-M"})
+	require.NoError(t, err)
+	otherProductID, err := productService.CreateProduct(model.ProductCreate{Name: "Hoodie", Prices: rub(3000)})
+	require.NoError(t, err)
+
+	// Act & Assert
+	_, err = productService.AddVariant(productID, model.VariantCreate{Options: size("M")})
+	assert.ErrorIs(t, err, model.ErrVariantOptionsAlreadyUsed)
+	_, err = productService.AddVariant(productID, model.VariantCreate{Options: size("L"), SKU: "TS"})
+	assert.ErrorIs(t, err, model.ErrSKUAlreadyUsed)
+	_, err = productService.AddVariant(otherProductID, model.VariantCreate{Options: size("L"), SKU: "ts-m"})
+	assert.ErrorIs(t, err, model.ErrSKUAlreadyUsed)
+	_, err = productService.AddVariant(productID, model.VariantCreate{})
+	assert.ErrorIs(t, err, model.ErrInvalidVariantOptions)
+	assert.Len(t, repo.products[productID].Variants, 1)
+}
+
+func TestProductService_UpdateAndRemoveVariant(t *testing.T) {
+	// Arrange
+	repo := newMockProductRepository()
+	dispatcher := &mockEventDispatcher{}
+	productService := service.NewProductService(repo, dispatcher)
+	productID, err := productService.CreateProduct(model.ProductCreate{Name: "T-Shirt", Prices: rub(1000)})
+	require.NoError(t, err)
+	variantID, err := productService.AddVariant(productID, model.VariantCreate{Options: size("M")})
+	require.NoError(t, err)
+
+	// Act
+	err = productService.UpdateVariant(productID, variantID, model.VariantUpdate{Prices: toPtr(rub(900))})
+	require.NoError(t, err)
+
+	// Assert
+	product := repo.products[productID]
+	variant, ok := product.FindVariant(variantID)
+	require.True(t, ok)
+	assert.Equal(t, rub(900), variant.Prices)
+	assert.Equal(t, size("M")[0].Value, variant.Options[0].Value)
+	_, ok = dispatcher.dispatchedEvents[len(dispatcher.dispatchedEvents)-1].(*model.ProductVariantUpdated)
+	assert.True(t, ok)
+
+	err = productService.RemoveVariant(productID, variantID)
+	require.NoError(t, err)
+	assert.Empty(t, repo.products[productID].Variants)
+	assert.Equal(t, int64(4), repo.products[productID].Version)
+
+	err = productService.RemoveVariant(productID, uuid.New())
+	assert.ErrorIs(t, err, model.ErrVariantNotFound)
+}
@@ -0,0 +1,87 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+const (
+	// csvListSeparator разделяет значения списков в одной ячейке, как при импорте
+	csvListSeparator = ";"
+	// csvPairSeparator разделяет ключ и значение элемента списка, например валюту и сумму
+	csvPairSeparator = ":"
+)
+
+var csvHeader = []string{
+	"product_id", "name", "description", "status", "sku", "barcodes",
+	"prices", "category_ids", "attributes", "version", "created_at", "updated_at",
+}
+
+// NewCSVWriter пишет CSV с заголовком, по строке на продукт. Списки пишутся через ";":
+// prices как <валюта>:<сумма>, attributes как <код>:<значение>. Переводы, варианты и медиа в CSV не попадают
+func NewCSVWriter(w io.Writer) RecordWriter {
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(product appmodel.Product) error {
+	err := w.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	prices := make([]string, 0, len(product.Prices))
+	for _, price := range product.Prices {
+		prices = append(prices, price.Currency+csvPairSeparator+strconv.FormatInt(price.Amount, 10))
+	}
+	categoryIDs := make([]string, 0, len(product.CategoryIDs))
+	for _, categoryID := range product.CategoryIDs {
+		categoryIDs = append(categoryIDs, categoryID.String())
+	}
+	attributes := make([]string, 0, len(product.Attributes))
+	for _, attribute := range product.Attributes {
+		attributes = append(attributes, attribute.Code+csvPairSeparator+attribute.Value)
+	}
+
+	return w.writer.Write([]string{
+		product.ProductID.String(),
+		product.Name,
+		product.Description,
+		product.Status,
+		product.SKU,
+		strings.Join(product.Barcodes, csvListSeparator),
+		strings.Join(prices, csvListSeparator),
+		strings.Join(categoryIDs, csvListSeparator),
+		strings.Join(attributes, csvListSeparator),
+		strconv.FormatInt(product.Version, 10),
+		product.CreatedAt.UTC().Format(time.RFC3339Nano),
+		product.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// Close пишет заголовок и для пустого каталога, чтобы файл оставался корректным CSV
+func (w *csvWriter) Close() error {
+	err := w.writeHeader()
+	if err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.writer.Write(csvHeader)
+}
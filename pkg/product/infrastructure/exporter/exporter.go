@@ -0,0 +1,126 @@
+package exporter
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/application/query"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+type Format int
+
+const (
+	FormatJSONLines Format = iota
+	FormatCSV
+)
+
+// ParseFormat разбирает формат по имени: jsonl или csv
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "jsonl":
+		return FormatJSONLines, nil
+	case "csv":
+		return FormatCSV, nil
+	default:
+		return 0, fmt.Errorf("%w %q, expected jsonl or csv", ErrUnknownFormat, name)
+	}
+}
+
+type Options struct {
+	Format Format
+	Gzip   bool
+	// PageSize размер страницы чтения, по умолчанию query.MaxListLimit
+	PageSize int
+}
+
+// RecordWriter пишет продукты в выходной формат, Close дописывает буферизованные данные
+type RecordWriter interface {
+	Write(product appmodel.Product) error
+	Close() error
+}
+
+// Export пишет все продукты каталога в w, читая их страницами из одного согласованного снимка.
+// Водяной знак снимка передается в watermark до записи первого продукта. Возвращает число записанных продуктов
+func Export(
+	ctx context.Context,
+	snapshots query.ProductSnapshotService,
+	w io.Writer,
+	options Options,
+	watermark func(query.SnapshotWatermark) error,
+) (int, error) {
+	pageSize := options.PageSize
+	if pageSize <= 0 || pageSize > query.MaxListLimit {
+		pageSize = query.MaxListLimit
+	}
+
+	count := 0
+	err := snapshots.InSnapshot(ctx, func(snapshotWatermark query.SnapshotWatermark, products query.ProductQueryService) error {
+		err := watermark(snapshotWatermark)
+		if err != nil {
+			return err
+		}
+
+		output := w
+		var gzipWriter *gzip.Writer
+		if options.Gzip {
+			gzipWriter = gzip.NewWriter(w)
+			output = gzipWriter
+		}
+		writer, err := newRecordWriter(options.Format, output)
+		if err != nil {
+			return err
+		}
+
+		spec := query.ListProductsSpec{
+			SortBy: query.SortByCreatedAt,
+			Limit:  pageSize,
+		}
+		for {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+			page, err := products.ListProducts(ctx, spec)
+			if err != nil {
+				return err
+			}
+			for _, product := range page.Products {
+				err = writer.Write(product)
+				if err != nil {
+					return err
+				}
+				count++
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			spec.Cursor = page.NextCursor
+		}
+
+		err = writer.Close()
+		if err != nil {
+			return err
+		}
+		if gzipWriter != nil {
+			return gzipWriter.Close()
+		}
+		return nil
+	})
+	return count, err
+}
+
+func newRecordWriter(format Format, w io.Writer) (RecordWriter, error) {
+	switch format {
+	case FormatJSONLines:
+		return NewJSONLinesWriter(w), nil
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w %d", ErrUnknownFormat, format)
+	}
+}
@@ -0,0 +1,147 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+// NewJSONLinesWriter пишет продукт объектом на строку со всеми полями, включая переводы, атрибуты, варианты и медиа
+func NewJSONLinesWriter(w io.Writer) RecordWriter {
+	buffer := bufio.NewWriter(w)
+	return &jsonLinesWriter{
+		buffer:  buffer,
+		encoder: json.NewEncoder(buffer),
+	}
+}
+
+type jsonLinesWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+type jsonProduct struct {
+	ProductID    string            `json:"product_id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description,omitempty"`
+	Translations []jsonTranslation `json:"translations,omitempty"`
+	Prices       []jsonMoney       `json:"prices"`
+	SKU          string            `json:"sku,omitempty"`
+	Barcodes     []string          `json:"barcodes"`
+	CategoryIDs  []string          `json:"category_ids"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Variants     []jsonVariant     `json:"variants,omitempty"`
+	Media        []jsonMedia       `json:"media,omitempty"`
+	Status       string            `json:"status"`
+	Version      int64             `json:"version"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+type jsonTranslation struct {
+	Locale      string `json:"locale"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type jsonMoney struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type jsonVariant struct {
+	VariantID string            `json:"variant_id"`
+	Options   map[string]string `json:"options"`
+	Prices    []jsonMoney       `json:"prices,omitempty"`
+	SKU       string            `json:"sku,omitempty"`
+}
+
+type jsonMedia struct {
+	MediaID     string `json:"media_id"`
+	URL         string `json:"url,omitempty"`
+	StorageKey  string `json:"storage_key,omitempty"`
+	AltText     string `json:"alt_text,omitempty"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	ContentType string `json:"content_type"`
+	Primary     bool   `json:"primary"`
+}
+
+func (w *jsonLinesWriter) Write(product appmodel.Product) error {
+	return w.encoder.Encode(toJSONProduct(product))
+}
+
+func (w *jsonLinesWriter) Close() error {
+	return w.buffer.Flush()
+}
+
+func toJSONProduct(product appmodel.Product) jsonProduct {
+	result := jsonProduct{
+		ProductID:   product.ProductID.String(),
+		Name:        product.Name,
+		Description: product.Description,
+		Prices:      toJSONMoney(product.Prices),
+		SKU:         product.SKU,
+		Barcodes:    product.Barcodes,
+		CategoryIDs: make([]string, 0, len(product.CategoryIDs)),
+		Status:      product.Status,
+		Version:     product.Version,
+		CreatedAt:   product.CreatedAt.UTC(),
+		UpdatedAt:   product.UpdatedAt.UTC(),
+	}
+	if result.Barcodes == nil {
+		result.Barcodes = []string{}
+	}
+	for _, translation := range product.Translations {
+		result.Translations = append(result.Translations, jsonTranslation{
+			Locale:      translation.Locale,
+			Name:        translation.Name,
+			Description: translation.Description,
+		})
+	}
+	for _, categoryID := range product.CategoryIDs {
+		result.CategoryIDs = append(result.CategoryIDs, categoryID.String())
+	}
+	if len(product.Attributes) > 0 {
+		result.Attributes = make(map[string]string, len(product.Attributes))
+		for _, attribute := range product.Attributes {
+			result.Attributes[attribute.Code] = attribute.Value
+		}
+	}
+	for _, variant := range product.Variants {
+		options := make(map[string]string, len(variant.Options))
+		for _, option := range variant.Options {
+			options[option.Name] = option.Value
+		}
+		result.Variants = append(result.Variants, jsonVariant{
+			VariantID: variant.VariantID.String(),
+			Options:   options,
+			Prices:    toJSONMoney(variant.Prices),
+			SKU:       variant.SKU,
+		})
+	}
+	for _, media := range product.Media {
+		result.Media = append(result.Media, jsonMedia{
+			MediaID:     media.MediaID.String(),
+			URL:         media.URL,
+			StorageKey:  media.StorageKey,
+			AltText:     media.AltText,
+			Width:       media.Width,
+			Height:      media.Height,
+			ContentType: media.ContentType,
+			Primary:     media.Primary,
+		})
+	}
+	return result
+}
+
+func toJSONMoney(prices []appmodel.Money) []jsonMoney {
+	result := make([]jsonMoney, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, jsonMoney{Amount: price.Amount, Currency: price.Currency})
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+const (
+	csvPricePrefix = "price_"
+	// csvListSeparator разделяет значения списков в одной ячейке, например штрихкоды
+	csvListSeparator = ";"
+)
+
+// NewCSVReader читает CSV с заголовком. Колонки: name, description, sku, barcodes через ";"
+// и price_<валюта> с суммой в минимальных единицах, например price_rub. Отсутствующая колонка не меняет поле,
+// пустая ячейка очищает его, пустые ячейки цен означают отсутствие цены в этой валюте
+func NewCSVReader(r io.Reader) RowReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return &csvReader{reader: reader}
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+	row    int
+}
+
+func (r *csvReader) Read() (int, appmodel.ProductImport, error) {
+	if r.header == nil {
+		header, err := r.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return 0, appmodel.ProductImport{}, io.EOF
+		}
+		if err != nil {
+			return 0, appmodel.ProductImport{}, fmt.Errorf("read csv header: %w", err)
+		}
+		err = r.setHeader(header)
+		if err != nil {
+			return 0, appmodel.ProductImport{}, err
+		}
+	}
+
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, appmodel.ProductImport{}, io.EOF
+	}
+	r.row++
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return r.row, appmodel.ProductImport{}, RowError{Row: r.row, Err: err}
+	}
+	if err != nil {
+		return r.row, appmodel.ProductImport{}, err
+	}
+	if len(record) != len(r.header) {
+		return r.row, appmodel.ProductImport{}, RowError{
+			Row: r.row,
+			Err: fmt.Errorf("expected %d fields, got %d", len(r.header), len(record)),
+		}
+	}
+
+	product, err := r.parseRecord(record)
+	if err != nil {
+		return r.row, appmodel.ProductImport{}, RowError{Row: r.row, Err: err}
+	}
+	return r.row, product, nil
+}
+
+func (r *csvReader) setHeader(header []string) error {
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		switch {
+		case column == "name", column == "description", column == "sku", column == "barcodes":
+		case strings.HasPrefix(column, csvPricePrefix) && len(column) > len(csvPricePrefix):
+		default:
+			return fmt.Errorf("unknown csv column %q", header[i])
+		}
+		for _, previous := range header[:i] {
+			if strings.ToLower(strings.TrimSpace(previous)) == column {
+				return fmt.Errorf("duplicate csv column %q", header[i])
+			}
+		}
+		header[i] = column
+	}
+	r.header = header
+	return nil
+}
+
+func (r *csvReader) parseRecord(record []string) (appmodel.ProductImport, error) {
+	var product appmodel.ProductImport
+	for i, column := range r.header {
+		value := record[i]
+		switch {
+		case column == "name":
+			product.Name = &value
+		case column == "description":
+			product.Description = &value
+		case column == "sku":
+			product.SKU = &value
+		case column == "barcodes":
+			barcodes := splitList(value)
+			product.Barcodes = &barcodes
+		case strings.HasPrefix(column, csvPricePrefix):
+			if product.Prices == nil {
+				product.Prices = &[]appmodel.Money{}
+			}
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			amount, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return appmodel.ProductImport{}, fmt.Errorf("column %q: amount must be an integer in minor units, got %q", column, value)
+			}
+			*product.Prices = append(*product.Prices, appmodel.Money{
+				Amount:   amount,
+				Currency: strings.ToUpper(strings.TrimPrefix(column, csvPricePrefix)),
+			})
+		}
+	}
+	return product, nil
+}
+
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, csvListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
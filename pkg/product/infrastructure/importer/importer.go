@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/application/service"
+)
+
+type Options struct {
+	Key    appmodel.ImportKey
+	DryRun bool
+}
+
+// RowResult результат строки, Err заполнен для строки, которую не удалось разобрать или импортировать
+type RowResult struct {
+	Row       int
+	ProductID uuid.UUID
+	Action    appmodel.ImportAction
+	Err       error
+}
+
+type Summary struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Failed    int
+}
+
+func (s *Summary) Add(result RowResult) {
+	switch {
+	case result.Err != nil:
+		s.Failed++
+	case result.Action == appmodel.ImportCreated:
+		s.Created++
+	case result.Action == appmodel.ImportUpdated:
+		s.Updated++
+	default:
+		s.Unchanged++
+	}
+}
+
+// ImportRow импортирует одну строку через ProductService, так что к ней применяются те же проверки,
+// блокировки и события, что и к StoreProduct
+func ImportRow(ctx context.Context, productService service.ProductService, row int, product appmodel.ProductImport, options Options) RowResult {
+	result, err := productService.ImportProduct(ctx, product, options.Key, options.DryRun)
+	return RowResult{
+		Row:       row,
+		ProductID: result.ProductID,
+		Action:    result.Action,
+		Err:       err,
+	}
+}
+
+// Import импортирует строки reader по одной. Ошибка строки передается в report и не прерывает импорт,
+// импорт прерывается ошибкой чтения или отменой ctx
+func Import(ctx context.Context, reader RowReader, productService service.ProductService, options Options, report func(RowResult)) (Summary, error) {
+	var summary Summary
+	for {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		row, product, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return summary, nil
+		}
+		var result RowResult
+		var rowErr RowError
+		switch {
+		case errors.As(err, &rowErr):
+			result = RowResult{Row: row, Err: err}
+		case err != nil:
+			return summary, err
+		default:
+			result = ImportRow(ctx, productService, row, product, options)
+		}
+		summary.Add(result)
+		report(result)
+	}
+}
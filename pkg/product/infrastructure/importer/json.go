@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+// NewJSONReader читает JSON-массив объектов или поток объектов, например JSON Lines.
+// Поля объекта: name, description, sku, barcodes и prices со списком {"amount", "currency"},
+// отсутствующее поле не меняет поле продукта
+func NewJSONReader(r io.Reader) RowReader {
+	return &jsonReader{reader: bufio.NewReader(r)}
+}
+
+type jsonReader struct {
+	reader  *bufio.Reader
+	decoder *json.Decoder
+	array   bool
+	row     int
+}
+
+type jsonRow struct {
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	SKU         *string   `json:"sku"`
+	Barcodes    *[]string `json:"barcodes"`
+	Prices      *[]struct {
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+	} `json:"prices"`
+}
+
+func (r *jsonReader) Read() (int, appmodel.ProductImport, error) {
+	if r.decoder == nil {
+		err := r.start()
+		if err != nil {
+			return 0, appmodel.ProductImport{}, err
+		}
+	}
+	if r.array && !r.decoder.More() {
+		_, err := r.decoder.Token()
+		if err != nil {
+			return 0, appmodel.ProductImport{}, fmt.Errorf("read json: %w", err)
+		}
+		return 0, appmodel.ProductImport{}, io.EOF
+	}
+
+	var row jsonRow
+	err := r.decoder.Decode(&row)
+	if errors.Is(err, io.EOF) && !r.array {
+		return 0, appmodel.ProductImport{}, io.EOF
+	}
+	r.row++
+	if err != nil {
+		// Decoder читает объект целиком до разбора, поэтому после ошибки типа или лишнего поля можно читать дальше
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			return r.row, appmodel.ProductImport{}, fmt.Errorf("read json row %d: %w", r.row, err)
+		}
+		return r.row, appmodel.ProductImport{}, RowError{Row: r.row, Err: err}
+	}
+
+	product := appmodel.ProductImport{
+		Name:        row.Name,
+		Description: row.Description,
+		SKU:         row.SKU,
+		Barcodes:    row.Barcodes,
+	}
+	if row.Prices != nil {
+		prices := make([]appmodel.Money, 0, len(*row.Prices))
+		for _, price := range *row.Prices {
+			prices = append(prices, appmodel.Money{
+				Amount:   price.Amount,
+				Currency: price.Currency,
+			})
+		}
+		product.Prices = &prices
+	}
+	return r.row, product, nil
+}
+
+// start определяет формат по первому значащему символу
+func (r *jsonReader) start() error {
+	for {
+		b, err := r.reader.Peek(1)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read json: %w", err)
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			r.array = b[0] == '['
+			break
+		}
+		_, _ = r.reader.ReadByte()
+	}
+
+	r.decoder = json.NewDecoder(r.reader)
+	r.decoder.DisallowUnknownFields()
+	if r.array {
+		_, err := r.decoder.Token()
+		if err != nil {
+			return fmt.Errorf("read json: %w", err)
+		}
+	}
+	return nil
+}
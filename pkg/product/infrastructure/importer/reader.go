@@ -0,0 +1,30 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+var ErrInvalidRow = errors.New("invalid import row")
+
+// RowError ошибка разбора одной строки, после нее чтение можно продолжить
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %s: %s", e.Row, ErrInvalidRow, e.Err)
+}
+
+func (e RowError) Unwrap() []error {
+	return []error{ErrInvalidRow, e.Err}
+}
+
+// RowReader читает строки импорта. Read возвращает номер строки, начиная с 1, io.EOF после последней строки,
+// RowError для строки, которую не удалось разобрать, и любую другую ошибку, после которой чтение невозможно
+type RowReader interface {
+	Read() (int, appmodel.ProductImport, error)
+}
@@ -0,0 +1,191 @@
+package importer
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/application/service"
+)
+
+type readResult struct {
+	row     int
+	product appmodel.ProductImport
+	err     error
+}
+
+// readAll читает строки до io.EOF или ошибки, после которой чтение невозможно
+func readAll(t *testing.T, reader RowReader) []readResult {
+	t.Helper()
+	var results []readResult
+	for {
+		row, product, err := reader.Read()
+		if err == io.EOF {
+			return results
+		}
+		results = append(results, readResult{row: row, product: product, err: err})
+		var rowErr RowError
+		if err != nil && !assert.ErrorAs(t, err, &rowErr) {
+			return results
+		}
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestCSVReader_ListColumns(t *testing.T) {
+	reader := NewCSVReader(strings.NewReader(
+		"Name, SKU, Barcodes, Price_RUB, price_usd\n" +
+			"Coffee, COF-1, 4006381333931; 5901234123457 ;, 300, \n" +
+			"Tea, , , , 4\n",
+	))
+
+	results := readAll(t, reader)
+
+	require.Len(t, results, 2)
+	require.NoError(t, results[0].err)
+	assert.Equal(t, 1, results[0].row)
+	assert.Equal(t, appmodel.ProductImport{
+		Name:     ptr("Coffee"),
+		SKU:      ptr("COF-1"),
+		Barcodes: &[]string{"4006381333931", "5901234123457"},
+		// Пустая ячейка цены означает отсутствие цены в этой валюте
+		Prices: &[]appmodel.Money{{Amount: 300, Currency: "RUB"}},
+	}, results[0].product)
+
+	require.NoError(t, results[1].err)
+	assert.Equal(t, 2, results[1].row)
+	assert.Equal(t, appmodel.ProductImport{
+		Name:     ptr("Tea"),
+		SKU:      ptr(""),
+		Barcodes: &[]string{},
+		Prices:   &[]appmodel.Money{{Amount: 4, Currency: "USD"}},
+	}, results[1].product)
+}
+
+func TestCSVReader_MalformedRows(t *testing.T) {
+	reader := NewCSVReader(strings.NewReader(
+		"name,price_rub\n" +
+			"Coffee,300,extra\n" +
+			"Tea,cheap\n" +
+			"\"Cocoa,100\n",
+	))
+
+	results := readAll(t, reader)
+
+	require.Len(t, results, 3)
+	// Лишняя ячейка и нечисловая цена портят только свою строку, чтение продолжается
+	assert.ErrorIs(t, results[0].err, ErrInvalidRow)
+	assert.Equal(t, 1, results[0].row)
+	assert.ErrorContains(t, results[0].err, "expected 2 fields, got 3")
+	assert.ErrorIs(t, results[1].err, ErrInvalidRow)
+	assert.Equal(t, 2, results[1].row)
+	assert.ErrorContains(t, results[1].err, `column "price_rub"`)
+	// Незакрытая кавычка тоже ошибка строки
+	assert.ErrorIs(t, results[2].err, ErrInvalidRow)
+	assert.Equal(t, 3, results[2].row)
+}
+
+func TestCSVReader_InvalidHeader(t *testing.T) {
+	testCases := []struct {
+		name   string
+		header string
+	}{
+		{name: "unknown column", header: "name,weight"},
+		{name: "duplicate column", header: "name, Name"},
+		{name: "price without currency", header: "name,price_"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := NewCSVReader(strings.NewReader(tc.header + "\nCoffee,1\n")).Read()
+
+			require.Error(t, err)
+			var rowErr RowError
+			assert.NotErrorAs(t, err, &rowErr)
+		})
+	}
+}
+
+func TestJSONReader(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "json lines",
+			input: `{"name": "Coffee", "barcodes": ["4006381333931"], "prices": [{"amount": 300, "currency": "RUB"}]}
+{"name": "Tea", "weight": 100}
+{"name": 5}
+{"sku": "COC-1"}
+`,
+		},
+		{
+			name: "array",
+			input: ` [
+	{"name": "Coffee", "barcodes": ["4006381333931"], "prices": [{"amount": 300, "currency": "RUB"}]},
+	{"name": "Tea", "weight": 100},
+	{"name": 5},
+	{"sku": "COC-1"}
+]`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results := readAll(t, NewJSONReader(strings.NewReader(tc.input)))
+
+			require.Len(t, results, 4)
+			require.NoError(t, results[0].err)
+			assert.Equal(t, appmodel.ProductImport{
+				Name:     ptr("Coffee"),
+				Barcodes: &[]string{"4006381333931"},
+				Prices:   &[]appmodel.Money{{Amount: 300, Currency: "RUB"}},
+			}, results[0].product)
+			// Лишнее поле и неверный тип портят только свою строку
+			assert.ErrorIs(t, results[1].err, ErrInvalidRow)
+			assert.Equal(t, 2, results[1].row)
+			assert.ErrorIs(t, results[2].err, ErrInvalidRow)
+			assert.Equal(t, 3, results[2].row)
+			require.NoError(t, results[3].err)
+			assert.Equal(t, 4, results[3].row)
+			assert.Equal(t, appmodel.ProductImport{SKU: ptr("COC-1")}, results[3].product)
+		})
+	}
+}
+
+func TestJSONReader_SyntaxErrorStopsReading(t *testing.T) {
+	reader := NewJSONReader(strings.NewReader(`{"name": "Coffee"} {"name": `))
+
+	_, _, err := reader.Read()
+	require.NoError(t, err)
+	_, _, err = reader.Read()
+
+	require.Error(t, err)
+	var rowErr RowError
+	assert.NotErrorAs(t, err, &rowErr)
+}
+
+func TestImport_MissingKeyColumn(t *testing.T) {
+	reader := NewCSVReader(strings.NewReader("name,price_rub\nCoffee,300\nTea,100\n"))
+	// Строка без ключа отклоняется до обращения к хранилищу
+	productService := service.NewProductService(nil, nil, nil)
+
+	var results []RowResult
+	summary, err := Import(context.Background(), reader, productService, Options{Key: appmodel.ImportBySKU}, func(result RowResult) {
+		results = append(results, result)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Failed: 2}, summary)
+	require.Len(t, results, 2)
+	for i, result := range results {
+		assert.Equal(t, i+1, result.Row)
+		assert.ErrorIs(t, result.Err, service.ErrImportKeyMissing)
+	}
+}
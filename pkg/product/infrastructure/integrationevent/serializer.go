@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
 	"productservice/pkg/product/domain/model"
@@ -19,53 +20,453 @@ func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
 	switch e := event.(type) {
 	case *model.ProductCreated:
 		b, err := json.Marshal(ProductCreated{
-			ProductID: e.ProductID.String(),
-			Name:      e.Name,
-			Price:     e.Price,
-			CreatedAt: e.CreatedAt.Unix(),
+			ProductID:    e.ProductID.String(),
+			Name:         e.Name,
+			Description:  e.Description,
+			Translations: toTranslations(e.Translations),
+			Prices:       toMoney(e.Prices),
+			Price:        defaultCurrencyAmount(e.Prices),
+			SKU:          e.SKU,
+			Barcodes:     nonNilStrings(e.Barcodes),
+			Status:       e.Status.String(),
+			CreatedAt:    e.CreatedAt.Unix(),
 		})
 		return string(b), errors.WithStack(err)
 	case *model.ProductUpdated:
 		ie := ProductUpdated{
 			ProductID: e.ProductID.String(),
+			Version:   e.Version,
 			UpdatedAt: e.UpdatedAt.Unix(),
 		}
 		if e.UpdatedFields.Name != nil {
 			ie.UpdatedFields.Name = e.UpdatedFields.Name
 		}
-		if e.UpdatedFields.Price != nil {
-			ie.UpdatedFields.Price = e.UpdatedFields.Price
+		if e.PreviousFields.Name != nil {
+			ie.PreviousFields.Name = e.PreviousFields.Name
+		}
+		if e.UpdatedFields.Description != nil {
+			ie.UpdatedFields.Description = e.UpdatedFields.Description
+		}
+		if e.PreviousFields.Description != nil {
+			ie.PreviousFields.Description = e.PreviousFields.Description
+		}
+		if e.UpdatedFields.Translations != nil && e.PreviousFields.Translations != nil {
+			translations, previousTranslations := toTranslations(*e.UpdatedFields.Translations), toTranslations(*e.PreviousFields.Translations)
+			ie.UpdatedFields.Translations = &translations
+			ie.PreviousFields.Translations = &previousTranslations
+		}
+		if e.UpdatedFields.Prices != nil && e.PreviousFields.Prices != nil {
+			prices, previousPrices := toMoney(*e.UpdatedFields.Prices), toMoney(*e.PreviousFields.Prices)
+			ie.UpdatedFields.Prices = &prices
+			ie.PreviousFields.Prices = &previousPrices
+
+			price, previousPrice := defaultCurrencyAmount(*e.UpdatedFields.Prices), defaultCurrencyAmount(*e.PreviousFields.Prices)
+			if !equalAmounts(price, previousPrice) {
+				ie.UpdatedFields.Price = price
+				ie.PreviousFields.Price = previousPrice
+			}
+		}
+		if e.UpdatedFields.SKU != nil {
+			ie.UpdatedFields.SKU = e.UpdatedFields.SKU
+		}
+		if e.PreviousFields.SKU != nil {
+			ie.PreviousFields.SKU = e.PreviousFields.SKU
+		}
+		if e.UpdatedFields.Barcodes != nil && e.PreviousFields.Barcodes != nil {
+			barcodes, previousBarcodes := nonNilStrings(*e.UpdatedFields.Barcodes), nonNilStrings(*e.PreviousFields.Barcodes)
+			ie.UpdatedFields.Barcodes = &barcodes
+			ie.PreviousFields.Barcodes = &previousBarcodes
+		}
+		if e.UpdatedFields.Attributes != nil && e.PreviousFields.Attributes != nil {
+			attributes, previousAttributes := toAttributes(*e.UpdatedFields.Attributes), toAttributes(*e.PreviousFields.Attributes)
+			ie.UpdatedFields.Attributes = &attributes
+			ie.PreviousFields.Attributes = &previousAttributes
+		}
+		if e.UpdatedFields.CategoryIDs != nil && e.PreviousFields.CategoryIDs != nil {
+			categoryIDs, previousCategoryIDs := toIDStrings(*e.UpdatedFields.CategoryIDs), toIDStrings(*e.PreviousFields.CategoryIDs)
+			ie.UpdatedFields.CategoryIDs = &categoryIDs
+			ie.PreviousFields.CategoryIDs = &previousCategoryIDs
 		}
 		b, err := json.Marshal(ie)
 		return string(b), errors.WithStack(err)
+	case *model.ProductPublished:
+		b, err := json.Marshal(ProductPublished{
+			ProductID:      e.ProductID.String(),
+			PreviousStatus: e.PreviousStatus.String(),
+			Version:        e.Version,
+			PublishedAt:    e.PublishedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductArchived:
+		b, err := json.Marshal(ProductArchived{
+			ProductID:  e.ProductID.String(),
+			Version:    e.Version,
+			ArchivedAt: e.ArchivedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
 	case *model.ProductDeleted:
 		b, err := json.Marshal(ProductDeleted{
 			ProductID: e.ProductID.String(),
 			DeletedAt: e.DeletedAt.Unix(),
 		})
 		return string(b), errors.WithStack(err)
+	case *model.ProductRestored:
+		b, err := json.Marshal(ProductRestored{
+			ProductID:  e.ProductID.String(),
+			RestoredAt: e.RestoredAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductVariantAdded:
+		b, err := json.Marshal(ProductVariantAdded{
+			ProductID: e.ProductID.String(),
+			Variant:   toVariant(e.Variant),
+			Version:   e.Version,
+			AddedAt:   e.AddedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductVariantUpdated:
+		b, err := json.Marshal(ProductVariantUpdated{
+			ProductID: e.ProductID.String(),
+			Variant:   toVariant(e.Variant),
+			Version:   e.Version,
+			UpdatedAt: e.UpdatedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductVariantRemoved:
+		b, err := json.Marshal(ProductVariantRemoved{
+			ProductID: e.ProductID.String(),
+			VariantID: e.VariantID.String(),
+			Version:   e.Version,
+			RemovedAt: e.RemovedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductMediaAdded:
+		b, err := json.Marshal(ProductMediaAdded{
+			ProductID: e.ProductID.String(),
+			Media:     toMedia(e.Media),
+			Version:   e.Version,
+			AddedAt:   e.AddedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductMediaReordered:
+		b, err := json.Marshal(ProductMediaReordered{
+			ProductID:   e.ProductID.String(),
+			MediaIDs:    toIDStrings(e.MediaIDs),
+			Version:     e.Version,
+			ReorderedAt: e.ReorderedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductMediaPrimaryChanged:
+		b, err := json.Marshal(ProductMediaPrimaryChanged{
+			ProductID: e.ProductID.String(),
+			MediaID:   e.MediaID.String(),
+			Version:   e.Version,
+			ChangedAt: e.ChangedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductMediaRemoved:
+		b, err := json.Marshal(ProductMediaRemoved{
+			ProductID:      e.ProductID.String(),
+			MediaID:        e.MediaID.String(),
+			PrimaryMediaID: toIDString(e.PrimaryMediaID),
+			Version:        e.Version,
+			RemovedAt:      e.RemovedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.CategoryCreated:
+		b, err := json.Marshal(CategoryCreated{
+			CategoryID: e.CategoryID.String(),
+			ParentID:   toIDString(e.ParentID),
+			Name:       e.Name,
+			CreatedAt:  e.CreatedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.CategoryUpdated:
+		b, err := json.Marshal(CategoryUpdated{
+			CategoryID: e.CategoryID.String(),
+			ParentID:   toIDString(e.ParentID),
+			Name:       e.Name,
+			UpdatedAt:  e.UpdatedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.CategoryDeleted:
+		b, err := json.Marshal(CategoryDeleted{
+			CategoryID: e.CategoryID.String(),
+			DeletedAt:  e.DeletedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
 	default:
 		return "", errors.Errorf("unknown event %q", event.Type())
 	}
 }
 
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Translation перевод имени и описания, в событиях переводы передаются объектом локаль - перевод
+type Translation struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ProductCreated поле price дублирует цену в model.DefaultCurrency для потребителей, не знающих о prices.
+// Name и description в локали по умолчанию, translations содержит все переводы
 type ProductCreated struct {
-	ProductID string `json:"product_id"`
-	Name      string `json:"name"`
-	Price     int64  `json:"price"`
-	CreatedAt int64  `json:"created_at"`
+	ProductID    string                 `json:"product_id"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	Translations map[string]Translation `json:"translations"`
+	Prices       []Money                `json:"prices"`
+	Price        *int64                 `json:"price,omitempty"`
+	SKU          string                 `json:"sku,omitempty"`
+	Barcodes     []string               `json:"barcodes"`
+	Status       string                 `json:"status"`
+	CreatedAt    int64                  `json:"created_at"`
 }
 
+// ProductUpdated поле price заполняется, только если изменилась цена в model.DefaultCurrency.
+// При изменении любого перевода translations содержит все переводы до и после изменения
 type ProductUpdated struct {
 	ProductID     string `json:"product_id"`
 	UpdatedFields struct {
-		Name  *string `json:"name,omitempty"`
-		Price *int64  `json:"price,omitempty"`
+		Name         *string                 `json:"name,omitempty"`
+		Description  *string                 `json:"description,omitempty"`
+		Translations *map[string]Translation `json:"translations,omitempty"`
+		Prices       *[]Money                `json:"prices,omitempty"`
+		Price        *int64                  `json:"price,omitempty"`
+		SKU          *string                 `json:"sku,omitempty"`
+		Barcodes     *[]string               `json:"barcodes,omitempty"`
+		CategoryIDs  *[]string               `json:"category_ids,omitempty"`
+		Attributes   *map[string]string      `json:"attributes,omitempty"`
 	} `json:"updated_fields"`
+	PreviousFields struct {
+		Name         *string                 `json:"name,omitempty"`
+		Description  *string                 `json:"description,omitempty"`
+		Translations *map[string]Translation `json:"translations,omitempty"`
+		Prices       *[]Money                `json:"prices,omitempty"`
+		Price        *int64                  `json:"price,omitempty"`
+		SKU          *string                 `json:"sku,omitempty"`
+		Barcodes     *[]string               `json:"barcodes,omitempty"`
+		CategoryIDs  *[]string               `json:"category_ids,omitempty"`
+		Attributes   *map[string]string      `json:"attributes,omitempty"`
+	} `json:"previous_fields"`
+	Version   int64 `json:"version"`
 	UpdatedAt int64 `json:"updated_at"`
 }
 
+// ProductPublished previous_status draft при первой публикации и archived при возврате из архива
+type ProductPublished struct {
+	ProductID      string `json:"product_id"`
+	PreviousStatus string `json:"previous_status"`
+	Version        int64  `json:"version"`
+	PublishedAt    int64  `json:"published_at"`
+}
+
+type ProductArchived struct {
+	ProductID  string `json:"product_id"`
+	Version    int64  `json:"version"`
+	ArchivedAt int64  `json:"archived_at"`
+}
+
 type ProductDeleted struct {
 	ProductID string `json:"product_id"`
 	DeletedAt int64  `json:"deleted_at"`
 }
+
+type ProductRestored struct {
+	ProductID  string `json:"product_id"`
+	RestoredAt int64  `json:"restored_at"`
+}
+
+// Variant пустой prices означает, что вариант продается по ценам продукта
+type Variant struct {
+	VariantID string            `json:"variant_id"`
+	Options   map[string]string `json:"options"`
+	Prices    []Money           `json:"prices"`
+	SKU       string            `json:"sku,omitempty"`
+}
+
+type ProductVariantAdded struct {
+	ProductID string  `json:"product_id"`
+	Variant   Variant `json:"variant"`
+	Version   int64   `json:"version"`
+	AddedAt   int64   `json:"added_at"`
+}
+
+// ProductVariantUpdated содержит вариант после изменения
+type ProductVariantUpdated struct {
+	ProductID string  `json:"product_id"`
+	Variant   Variant `json:"variant"`
+	Version   int64   `json:"version"`
+	UpdatedAt int64   `json:"updated_at"`
+}
+
+type ProductVariantRemoved struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id"`
+	Version   int64  `json:"version"`
+	RemovedAt int64  `json:"removed_at"`
+}
+
+// Media задан ровно один из url и storage_key, файлы хранилища доступны по его базовому адресу
+type Media struct {
+	MediaID     string `json:"media_id"`
+	URL         string `json:"url,omitempty"`
+	StorageKey  string `json:"storage_key,omitempty"`
+	AltText     string `json:"alt_text,omitempty"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	ContentType string `json:"content_type"`
+	Primary     bool   `json:"primary"`
+}
+
+// ProductMediaAdded медиа добавлено в конец списка, если primary, остальные медиа перестали быть основными
+type ProductMediaAdded struct {
+	ProductID string `json:"product_id"`
+	Media     Media  `json:"media"`
+	Version   int64  `json:"version"`
+	AddedAt   int64  `json:"added_at"`
+}
+
+// ProductMediaReordered содержит все медиа продукта в новом порядке
+type ProductMediaReordered struct {
+	ProductID   string   `json:"product_id"`
+	MediaIDs    []string `json:"media_ids"`
+	Version     int64    `json:"version"`
+	ReorderedAt int64    `json:"reordered_at"`
+}
+
+type ProductMediaPrimaryChanged struct {
+	ProductID string `json:"product_id"`
+	MediaID   string `json:"media_id"`
+	Version   int64  `json:"version"`
+	ChangedAt int64  `json:"changed_at"`
+}
+
+// ProductMediaRemoved primary_media_id задан, если удалено основное медиа и основным стало другое
+type ProductMediaRemoved struct {
+	ProductID      string  `json:"product_id"`
+	MediaID        string  `json:"media_id"`
+	PrimaryMediaID *string `json:"primary_media_id,omitempty"`
+	Version        int64   `json:"version"`
+	RemovedAt      int64   `json:"removed_at"`
+}
+
+// CategoryCreated parent_id отсутствует у корневых категорий
+type CategoryCreated struct {
+	CategoryID string  `json:"category_id"`
+	ParentID   *string `json:"parent_id,omitempty"`
+	Name       string  `json:"name"`
+	CreatedAt  int64   `json:"created_at"`
+}
+
+// CategoryUpdated содержит состояние категории после изменения
+type CategoryUpdated struct {
+	CategoryID string  `json:"category_id"`
+	ParentID   *string `json:"parent_id,omitempty"`
+	Name       string  `json:"name"`
+	UpdatedAt  int64   `json:"updated_at"`
+}
+
+type CategoryDeleted struct {
+	CategoryID string `json:"category_id"`
+	DeletedAt  int64  `json:"deleted_at"`
+}
+
+func toIDString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func toIDStrings(ids []uuid.UUID) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, id.String())
+	}
+	return result
+}
+
+// nonNilStrings сериализует пустой список как [], а не null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func toVariant(variant model.Variant) Variant {
+	options := make(map[string]string, len(variant.Options))
+	for _, option := range variant.Options {
+		options[option.Name] = option.Value
+	}
+	return Variant{
+		VariantID: variant.VariantID.String(),
+		Options:   options,
+		Prices:    toMoney(variant.Prices),
+		SKU:       variant.SKU,
+	}
+}
+
+func toMedia(media model.Media) Media {
+	return Media{
+		MediaID:     media.MediaID.String(),
+		URL:         media.URL,
+		StorageKey:  media.StorageKey,
+		AltText:     media.AltText,
+		Width:       media.Width,
+		Height:      media.Height,
+		ContentType: media.ContentType,
+		Primary:     media.Primary,
+	}
+}
+
+func toTranslations(translations []model.Translation) map[string]Translation {
+	result := make(map[string]Translation, len(translations))
+	for _, translation := range translations {
+		result[translation.Locale] = Translation{
+			Name:        translation.Name,
+			Description: translation.Description,
+		}
+	}
+	return result
+}
+
+// toAttributes сериализует атрибуты объектом код - каноническое значение
+func toAttributes(attributes []model.AttributeValue) map[string]string {
+	result := make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		result[attribute.Code] = attribute.Value
+	}
+	return result
+}
+
+func toMoney(prices []model.Money) []Money {
+	result := make([]Money, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, Money{
+			Amount:   price.Amount,
+			Currency: price.Currency,
+		})
+	}
+	return result
+}
+
+func defaultCurrencyAmount(prices []model.Money) *int64 {
+	price, ok := model.FindPrice(prices, model.DefaultCurrency)
+	if !ok {
+		return nil
+	}
+	return &price.Amount
+}
+
+func equalAmounts(l, r *int64) bool {
+	if l == nil || r == nil {
+		return l == r
+	}
+	return *l == *r
+}
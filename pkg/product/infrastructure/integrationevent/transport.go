@@ -2,10 +2,14 @@ package integrationevent
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/amqp"
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+
+	"productservice/pkg/common/infrastructure/deadletter"
 )
 
 const (
@@ -16,16 +20,33 @@ const (
 	ContentType      = "application/json"
 )
 
-func NewTransport(logger logging.Logger, producer amqp.Producer) outbox.Transport {
+// publishNackedMessage текст ошибки golib, когда брокер отказался принять публикацию (basic.nack)
+const publishNackedMessage = "failed to publish delivery"
+
+// RoutingKeyResolver возвращает ключ маршрутизации события, ok false для событий чужого модуля
+type RoutingKeyResolver func(eventType string) (routingKey string, ok bool)
+
+// RoutingKey возвращает ключ маршрутизации события продуктов
+func RoutingKey(eventType string) string {
+	return RoutingKeyPrefix + eventType
+}
+
+// NewTransport публикует события outbox. Через outbox продуктов идут и события других модулей, например остатков:
+// их ключи маршрутизации возвращают resolvers, остальные события публикуются с ключом RoutingKey.
+// Некорректное содержимое и отказ брокера принять публикацию возвращаются как deadletter.ErrUndeliverable,
+// остальные ошибки считаются временными
+func NewTransport(logger logging.Logger, producer amqp.Producer, resolvers ...RoutingKeyResolver) outbox.Transport {
 	return &transport{
-		logger:   logger,
-		producer: producer,
+		logger:    logger,
+		producer:  producer,
+		resolvers: resolvers,
 	}
 }
 
 type transport struct {
-	logger   logging.Logger
-	producer amqp.Producer
+	logger    logging.Logger
+	producer  amqp.Producer
+	resolvers []RoutingKeyResolver
 }
 
 func (t *transport) HandleEvents(ctx context.Context, correlationID, eventType, payload string) error {
@@ -35,17 +56,35 @@ func (t *transport) HandleEvents(ctx context.Context, correlationID, eventType,
 		"payload":       payload,
 	})
 
+	if !json.Valid([]byte(payload)) {
+		err := fmt.Errorf("%w: payload is not valid JSON", deadletter.ErrUndeliverable)
+		l.Error(err, "failed to publish event")
+		return err
+	}
+
 	err := t.producer.Publish(ctx, amqp.Delivery{
-		RoutingKey:    RoutingKeyPrefix + eventType,
+		RoutingKey:    t.routingKey(eventType),
 		CorrelationID: correlationID,
 		ContentType:   ContentType,
 		Type:          eventType,
 		Body:          []byte(payload),
 	})
 	if err != nil {
+		if err.Error() == publishNackedMessage {
+			err = fmt.Errorf("%w: %w", deadletter.ErrUndeliverable, err)
+		}
 		l.Error(err, "failed to publish event")
 		return err
 	}
 	l.Info("successfully published event")
 	return nil
 }
+
+func (t *transport) routingKey(eventType string) string {
+	for _, resolver := range t.resolvers {
+		if routingKey, ok := resolver(eventType); ok {
+			return routingKey
+		}
+	}
+	return RoutingKey(eventType)
+}
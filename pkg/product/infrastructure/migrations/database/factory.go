@@ -44,4 +44,20 @@ func NewDatabaseMigrator(
 
 var builderFunctions = []MigrationBuilderFunc{
 	NewVersion1722266003,
+	NewVersion1792317600,
+	NewVersion1792321200,
+	NewVersion1792324800,
+	NewVersion1792328400,
+	NewVersion1792332000,
+	NewVersion1792335600,
+	NewVersion1792339200,
+	NewVersion1792342800,
+	NewVersion1792346400,
+	NewVersion1792350000,
+	NewVersion1792353600,
+	NewVersion1792357200,
+	NewVersion1792360800,
+	NewVersion1792364400,
+	NewVersion1792368000,
+	NewVersion1792371600,
 }
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792317600(client mysql.ClientContext) migrator.Migration {
+	return &version1792317600{
+		client: client,
+	}
+}
+
+type version1792317600 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792317600) Version() int64 {
+	return 1792317600
+}
+
+func (v version1792317600) Description() string {
+	return "Add 'deleted_at' column to 'product' table"
+}
+
+func (v version1792317600) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			ADD COLUMN deleted_at DATETIME NULL AFTER updated_at,
+			ADD INDEX deleted_at_idx (deleted_at)
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792321200(client mysql.ClientContext) migrator.Migration {
+	return &version1792321200{
+		client: client,
+	}
+}
+
+type version1792321200 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792321200) Version() int64 {
+	return 1792321200
+}
+
+func (v version1792321200) Description() string {
+	return "Add sorting indexes to 'product' table"
+}
+
+func (v version1792321200) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			ADD INDEX price_idx (price, product_id),
+			ADD INDEX created_at_idx (created_at, product_id),
+			ADD INDEX updated_at_idx (updated_at)
+	`)
+	return errors.WithStack(err)
+}
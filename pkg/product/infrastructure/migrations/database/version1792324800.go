@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792324800(client mysql.ClientContext) migrator.Migration {
+	return &version1792324800{
+		client: client,
+	}
+}
+
+type version1792324800 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792324800) Version() int64 {
+	return 1792324800
+}
+
+func (v version1792324800) Description() string {
+	return "Add 'version' column to 'product' table"
+}
+
+func (v version1792324800) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			ADD COLUMN version BIGINT NOT NULL DEFAULT 1 AFTER price
+	`)
+	return errors.WithStack(err)
+}
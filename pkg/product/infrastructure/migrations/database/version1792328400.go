@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792328400(client mysql.ClientContext) migrator.Migration {
+	return &version1792328400{
+		client: client,
+	}
+}
+
+type version1792328400 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792328400) Version() int64 {
+	return 1792328400
+}
+
+func (v version1792328400) Description() string {
+	return "Move prices from 'product' to 'product_price' table"
+}
+
+func (v version1792328400) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE product_price
+		(
+			product_id    VARCHAR(64)  NOT NULL,
+			currency      CHAR(3)      NOT NULL,
+			amount        BIGINT       NOT NULL,
+			PRIMARY KEY (product_id, currency),
+			INDEX currency_amount_idx (currency, amount, product_id),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		INSERT INTO product_price (product_id, currency, amount)
+		SELECT product_id, 'RUB', price FROM product
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			DROP INDEX price_idx,
+			DROP COLUMN price
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792332000(client mysql.ClientContext) migrator.Migration {
+	return &version1792332000{
+		client: client,
+	}
+}
+
+type version1792332000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792332000) Version() int64 {
+	return 1792332000
+}
+
+func (v version1792332000) Description() string {
+	return "Create 'product_price_history' and 'product_scheduled_price_change' tables"
+}
+
+func (v version1792332000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE product_price_history
+		(
+			history_id     BIGINT       NOT NULL AUTO_INCREMENT,
+			product_id     VARCHAR(64)  NOT NULL,
+			currency       CHAR(3)      NOT NULL,
+			amount         BIGINT       NULL,
+			effective_from DATETIME     NOT NULL,
+			PRIMARY KEY (history_id),
+			INDEX product_currency_effective_from_idx (product_id, currency, effective_from),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		INSERT INTO product_price_history (product_id, currency, amount, effective_from)
+		SELECT pp.product_id, pp.currency, pp.amount, p.updated_at
+		FROM product_price pp
+			INNER JOIN product p ON p.product_id = pp.product_id
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE product_scheduled_price_change
+		(
+			change_id      VARCHAR(64)  NOT NULL,
+			product_id     VARCHAR(64)  NOT NULL,
+			currency       CHAR(3)      NOT NULL,
+			amount         BIGINT       NOT NULL,
+			effective_from DATETIME     NOT NULL,
+			created_at     DATETIME     NOT NULL,
+			applied_at     DATETIME     NULL,
+			PRIMARY KEY (change_id),
+			INDEX applied_at_effective_from_idx (applied_at, effective_from),
+			INDEX product_id_idx (product_id),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792335600(client mysql.ClientContext) migrator.Migration {
+	return &version1792335600{
+		client: client,
+	}
+}
+
+type version1792335600 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792335600) Version() int64 {
+	return 1792335600
+}
+
+func (v version1792335600) Description() string {
+	return "Create 'category' and 'product_category' tables"
+}
+
+func (v version1792335600) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE category
+		(
+			category_id   VARCHAR(64)  NOT NULL,
+			parent_id     VARCHAR(64)  NULL,
+			name          VARCHAR(255) NOT NULL,
+			created_at    DATETIME     NOT NULL,
+			updated_at    DATETIME     NOT NULL,
+			PRIMARY KEY (category_id),
+			INDEX parent_id_idx (parent_id),
+			FOREIGN KEY (parent_id) REFERENCES category (category_id)
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE product_category
+		(
+			product_id    VARCHAR(64)  NOT NULL,
+			category_id   VARCHAR(64)  NOT NULL,
+			PRIMARY KEY (product_id, category_id),
+			INDEX category_id_idx (category_id, product_id),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE,
+			FOREIGN KEY (category_id) REFERENCES category (category_id)
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792339200(client mysql.ClientContext) migrator.Migration {
+	return &version1792339200{
+		client: client,
+	}
+}
+
+type version1792339200 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792339200) Version() int64 {
+	return 1792339200
+}
+
+func (v version1792339200) Description() string {
+	return "Add 'sku' column to 'product' and create 'product_barcode' table"
+}
+
+func (v version1792339200) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			ADD COLUMN sku VARCHAR(64) NULL AFTER name,
+			ADD UNIQUE KEY sku_idx (sku)
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE product_barcode
+		(
+			barcode       VARCHAR(13)  NOT NULL,
+			product_id    VARCHAR(64)  NOT NULL,
+			PRIMARY KEY (barcode),
+			INDEX product_id_idx (product_id),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792342800(client mysql.ClientContext) migrator.Migration {
+	return &version1792342800{
+		client: client,
+	}
+}
+
+type version1792342800 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792342800) Version() int64 {
+	return 1792342800
+}
+
+func (v version1792342800) Description() string {
+	return "Create 'product_variant' and 'product_variant_price' tables"
+}
+
+func (v version1792342800) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE product_variant
+		(
+			variant_id    VARCHAR(64)  NOT NULL,
+			product_id    VARCHAR(64)  NOT NULL,
+			position      INT          NOT NULL,
+			options       JSON         NOT NULL,
+			sku           VARCHAR(64)  NULL,
+			PRIMARY KEY (variant_id),
+			UNIQUE KEY sku_idx (sku),
+			INDEX product_id_position_idx (product_id, position),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE product_variant_price
+		(
+			variant_id    VARCHAR(64)  NOT NULL,
+			currency      CHAR(3)      NOT NULL,
+			amount        BIGINT       NOT NULL,
+			PRIMARY KEY (variant_id, currency),
+			FOREIGN KEY (variant_id) REFERENCES product_variant (variant_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
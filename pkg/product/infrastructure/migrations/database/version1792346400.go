@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792346400(client mysql.ClientContext) migrator.Migration {
+	return &version1792346400{
+		client: client,
+	}
+}
+
+type version1792346400 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792346400) Version() int64 {
+	return 1792346400
+}
+
+func (v version1792346400) Description() string {
+	return "Create 'attribute_definition' and 'product_attribute' tables"
+}
+
+func (v version1792346400) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE attribute_definition
+		(
+			code          VARCHAR(64)  NOT NULL,
+			name          VARCHAR(255) NOT NULL,
+			type          VARCHAR(16)  NOT NULL,
+			enum_values   JSON         NOT NULL,
+			created_at    DATETIME     NOT NULL,
+			updated_at    DATETIME     NOT NULL,
+			PRIMARY KEY (code)
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE product_attribute
+		(
+			product_id    VARCHAR(64)  NOT NULL,
+			code          VARCHAR(64)  NOT NULL,
+			value         VARCHAR(255) NOT NULL,
+			value_number  DOUBLE       NULL,
+			PRIMARY KEY (product_id, code),
+			INDEX code_value_idx (code, value),
+			INDEX code_value_number_idx (code, value_number),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE,
+			FOREIGN KEY (code) REFERENCES attribute_definition (code)
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
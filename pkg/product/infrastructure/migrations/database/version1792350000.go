@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792350000(client mysql.ClientContext) migrator.Migration {
+	return &version1792350000{
+		client: client,
+	}
+}
+
+type version1792350000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792350000) Version() int64 {
+	return 1792350000
+}
+
+func (v version1792350000) Description() string {
+	return "Add 'description' to 'product' and create 'product_translation' table"
+}
+
+func (v version1792350000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			ADD COLUMN description MEDIUMTEXT NULL AFTER name
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE product_translation
+		(
+			product_id    VARCHAR(64)  NOT NULL,
+			locale        VARCHAR(35)  NOT NULL,
+			name          VARCHAR(255) NOT NULL,
+			description   MEDIUMTEXT   NOT NULL,
+			PRIMARY KEY (product_id, locale),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
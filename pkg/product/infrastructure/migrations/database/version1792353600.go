@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792353600(client mysql.ClientContext) migrator.Migration {
+	return &version1792353600{
+		client: client,
+	}
+}
+
+type version1792353600 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792353600) Version() int64 {
+	return 1792353600
+}
+
+func (v version1792353600) Description() string {
+	return "Add 'status' to 'product'"
+}
+
+func (v version1792353600) Up(ctx context.Context) error {
+	// Существующие продукты уже продаются, поэтому получают статус active, новые пишутся со статусом явно
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active' AFTER sku,
+			ADD INDEX status_idx (status)
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			ALTER COLUMN status DROP DEFAULT
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792357200(client mysql.ClientContext) migrator.Migration {
+	return &version1792357200{
+		client: client,
+	}
+}
+
+type version1792357200 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792357200) Version() int64 {
+	return 1792357200
+}
+
+func (v version1792357200) Description() string {
+	return "Add FULLTEXT indexes on 'product' and 'product_translation' names and descriptions"
+}
+
+func (v version1792357200) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			ADD FULLTEXT INDEX name_description_ft (name, description)
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		ALTER TABLE product_translation
+			ADD FULLTEXT INDEX name_description_ft (name, description)
+	`)
+	return errors.WithStack(err)
+}
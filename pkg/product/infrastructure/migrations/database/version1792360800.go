@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792360800(client mysql.ClientContext) migrator.Migration {
+	return &version1792360800{
+		client: client,
+	}
+}
+
+type version1792360800 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792360800) Version() int64 {
+	return 1792360800
+}
+
+func (v version1792360800) Description() string {
+	return "Create 'product_media' table"
+}
+
+func (v version1792360800) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE product_media
+		(
+			media_id      VARCHAR(64)   NOT NULL,
+			product_id    VARCHAR(64)   NOT NULL,
+			position      INT           NOT NULL,
+			url           VARCHAR(2048) NULL,
+			storage_key   VARCHAR(255)  NULL,
+			alt_text      VARCHAR(512)  NOT NULL,
+			width         INT           NOT NULL,
+			height        INT           NOT NULL,
+			content_type  VARCHAR(255)  NOT NULL,
+			is_primary    TINYINT(1)    NOT NULL,
+			PRIMARY KEY (media_id),
+			INDEX product_id_position_idx (product_id, position),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792364400(client mysql.ClientContext) migrator.Migration {
+	return &version1792364400{
+		client: client,
+	}
+}
+
+type version1792364400 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792364400) Version() int64 {
+	return 1792364400
+}
+
+func (v version1792364400) Description() string {
+	return "Create 'stock', 'stock_reservation' and 'stock_reservation_item' tables"
+}
+
+func (v version1792364400) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE stock
+		(
+			product_id    VARCHAR(64)  NOT NULL,
+			warehouse_id  VARCHAR(64)  NOT NULL,
+			on_hand       BIGINT       NOT NULL,
+			reserved      BIGINT       NOT NULL,
+			version       BIGINT       NOT NULL,
+			updated_at    DATETIME     NOT NULL,
+			PRIMARY KEY (product_id, warehouse_id),
+			FOREIGN KEY (product_id) REFERENCES product (product_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE stock_reservation
+		(
+			reservation_id  VARCHAR(64)  NOT NULL,
+			reference       VARCHAR(128) NULL,
+			status          VARCHAR(16)  NOT NULL,
+			expires_at      DATETIME     NOT NULL,
+			created_at      DATETIME     NOT NULL,
+			updated_at      DATETIME     NOT NULL,
+			PRIMARY KEY (reservation_id),
+			INDEX status_expires_at_idx (status, expires_at)
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE stock_reservation_item
+		(
+			reservation_id  VARCHAR(64)  NOT NULL,
+			product_id      VARCHAR(64)  NOT NULL,
+			warehouse_id    VARCHAR(64)  NOT NULL,
+			quantity        BIGINT       NOT NULL,
+			PRIMARY KEY (reservation_id, product_id, warehouse_id),
+			FOREIGN KEY (reservation_id) REFERENCES stock_reservation (reservation_id) ON DELETE CASCADE
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
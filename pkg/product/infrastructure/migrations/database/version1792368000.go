@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792368000(client mysql.ClientContext) migrator.Migration {
+	return &version1792368000{
+		client: client,
+	}
+}
+
+type version1792368000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792368000) Version() int64 {
+	return 1792368000
+}
+
+func (v version1792368000) Description() string {
+	return "Create 'inbox_message' table and 'stock_reservation' reference index"
+}
+
+func (v version1792368000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE inbox_message
+		(
+			message_id    VARCHAR(255) NOT NULL,
+			message_type  VARCHAR(255) NOT NULL,
+			processed_at  DATETIME     NOT NULL,
+			PRIMARY KEY (message_id),
+			INDEX processed_at_idx (processed_at)
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		ALTER TABLE stock_reservation
+			ADD INDEX reference_idx (reference)
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792371600(client mysql.ClientContext) migrator.Migration {
+	return &version1792371600{
+		client: client,
+	}
+}
+
+type version1792371600 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792371600) Version() int64 {
+	return 1792371600
+}
+
+func (v version1792371600) Description() string {
+	return "Create 'outbox_dead_letter' table"
+}
+
+func (v version1792371600) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE outbox_dead_letter
+		(
+			dead_letter_id  BIGINT          NOT NULL AUTO_INCREMENT,
+			transport_name  VARCHAR(128)    NOT NULL,
+			correlation_id  VARBINARY(128)  NOT NULL,
+			event_type      VARBINARY(128)  NOT NULL,
+			payload         TEXT            NOT NULL,
+			attempts        INT             NOT NULL,
+			last_error      TEXT            NOT NULL,
+			failed_at       DATETIME        NOT NULL,
+			PRIMARY KEY (dead_letter_id),
+			UNIQUE INDEX correlation_id_idx (correlation_id),
+			INDEX transport_name_idx (transport_name, dead_letter_id)
+		)
+			ENGINE = InnoDB
+			CHARACTER SET = utf8mb4
+			COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
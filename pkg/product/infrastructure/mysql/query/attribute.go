@@ -0,0 +1,88 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/application/query"
+	"productservice/pkg/product/domain/model"
+)
+
+func NewAttributeQueryService(client mysql.ClientContext) query.AttributeQueryService {
+	return &attributeQueryService{
+		client: client,
+	}
+}
+
+type attributeQueryService struct {
+	client mysql.ClientContext
+}
+
+func (q *attributeQueryService) ListAttributeDefinitions(ctx context.Context) ([]appmodel.AttributeDefinition, error) {
+	var dtos []struct {
+		Code       string    `db:"code"`
+		Name       string    `db:"name"`
+		Type       string    `db:"type"`
+		EnumValues []byte    `db:"enum_values"`
+		CreatedAt  time.Time `db:"created_at"`
+		UpdatedAt  time.Time `db:"updated_at"`
+	}
+	err := q.client.SelectContext(
+		ctx,
+		&dtos,
+		`SELECT code, name, type, enum_values, created_at, updated_at FROM attribute_definition ORDER BY code`,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	definitions := make([]appmodel.AttributeDefinition, 0, len(dtos))
+	for _, dto := range dtos {
+		var enumValues []string
+		err = json.Unmarshal(dto.EnumValues, &enumValues)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		definitions = append(definitions, appmodel.AttributeDefinition{
+			Code:       dto.Code,
+			Name:       dto.Name,
+			Type:       dto.Type,
+			EnumValues: enumValues,
+			CreatedAt:  dto.CreatedAt,
+			UpdatedAt:  dto.UpdatedAt,
+		})
+	}
+	return definitions, nil
+}
+
+// findAttributeDefinition возвращает nil, если определения с таким кодом нет
+func findAttributeDefinition(ctx context.Context, client mysql.ClientContext, code string) (*model.AttributeDefinition, error) {
+	var dto struct {
+		Type       string `db:"type"`
+		EnumValues []byte `db:"enum_values"`
+	}
+	err := client.GetContext(ctx, &dto, `SELECT type, enum_values FROM attribute_definition WHERE code = ?`, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	definition := &model.AttributeDefinition{Code: code}
+	definition.Type, err = model.ParseAttributeType(dto.Type)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = json.Unmarshal(dto.EnumValues, &definition.EnumValues)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return definition, nil
+}
@@ -0,0 +1,89 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/application/query"
+)
+
+func NewCategoryQueryService(client mysql.ClientContext) query.CategoryQueryService {
+	return &categoryQueryService{
+		client: client,
+	}
+}
+
+type categoryQueryService struct {
+	client mysql.ClientContext
+}
+
+type categoryDTO struct {
+	CategoryID uuid.UUID     `db:"category_id"`
+	ParentID   uuid.NullUUID `db:"parent_id"`
+	Name       string        `db:"name"`
+	CreatedAt  time.Time     `db:"created_at"`
+	UpdatedAt  time.Time     `db:"updated_at"`
+}
+
+const categoryColumns = `category_id, parent_id, name, created_at, updated_at`
+
+func (q *categoryQueryService) FindCategory(ctx context.Context, categoryID uuid.UUID) (*appmodel.Category, error) {
+	var dto categoryDTO
+	err := q.client.GetContext(
+		ctx,
+		&dto,
+		`SELECT `+categoryColumns+` FROM category WHERE category_id = ?`,
+		categoryID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	category := toAppCategory(dto)
+	return &category, nil
+}
+
+func (q *categoryQueryService) ListCategories(ctx context.Context, parentID *uuid.UUID) ([]appmodel.Category, error) {
+	condition, args := "parent_id IS NULL", []interface{}(nil)
+	if parentID != nil {
+		condition, args = "parent_id = ?", []interface{}{*parentID}
+	}
+
+	var dtos []categoryDTO
+	err := q.client.SelectContext(
+		ctx,
+		&dtos,
+		`SELECT `+categoryColumns+` FROM category WHERE `+condition+` ORDER BY name, category_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	categories := make([]appmodel.Category, 0, len(dtos))
+	for _, dto := range dtos {
+		categories = append(categories, toAppCategory(dto))
+	}
+	return categories, nil
+}
+
+func toAppCategory(dto categoryDTO) appmodel.Category {
+	category := appmodel.Category{
+		CategoryID: dto.CategoryID,
+		Name:       dto.Name,
+		CreatedAt:  dto.CreatedAt,
+		UpdatedAt:  dto.UpdatedAt,
+	}
+	if dto.ParentID.Valid {
+		category.ParentID = &dto.ParentID.UUID
+	}
+	return category
+}
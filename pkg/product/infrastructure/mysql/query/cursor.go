@@ -0,0 +1,61 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/product/application/query"
+)
+
+// productCursor позиция последнего продукта страницы при keyset-пагинации
+type productCursor struct {
+	SortBy     query.ProductSortField `json:"sort_by"`
+	Descending bool                   `json:"descending"`
+	Name       string                 `json:"name,omitempty"`
+	Price      int64                  `json:"price,omitempty"`
+	Currency   string                 `json:"currency,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	ProductID  uuid.UUID              `json:"product_id"`
+}
+
+func (c productCursor) sortValue() interface{} {
+	switch c.SortBy {
+	case query.SortByName:
+		return c.Name
+	case query.SortByPrice:
+		return c.Price
+	default:
+		return c.CreatedAt
+	}
+}
+
+func encodeCursor(c productCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string, spec query.ListProductsSpec, priceCurrency string) (productCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return productCursor{}, errors.WithStack(query.ErrInvalidCursor)
+	}
+	var c productCursor
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return productCursor{}, errors.WithStack(query.ErrInvalidCursor)
+	}
+	if c.SortBy != spec.SortBy || c.Descending != spec.Descending {
+		return productCursor{}, errors.Wrap(query.ErrInvalidCursor, "cursor was issued for another sort order")
+	}
+	if c.SortBy == query.SortByPrice && c.Currency != priceCurrency {
+		return productCursor{}, errors.Wrap(query.ErrInvalidCursor, "cursor was issued for another price currency")
+	}
+	return c, nil
+}
@@ -0,0 +1,83 @@
+package query
+
+import (
+	"strings"
+
+	"productservice/pkg/product/application/query"
+	"productservice/pkg/product/domain/model"
+)
+
+// maxHighlightLength длина фрагмента длинного текста вокруг первого совпадения в символах
+const maxHighlightLength = 200
+
+// appendHighlight добавляет фрагмент text с совпадениями terms, если совпадения есть
+func appendHighlight(highlights []query.Highlight, field, locale, text string, terms []string) []query.Highlight {
+	runes := []rune(text)
+	matches := findMatches(runes, terms)
+	if len(matches) == 0 {
+		return highlights
+	}
+
+	start, end := snippetBounds(runes, matches[0].Start)
+	var snippetMatches []query.TextRange
+	for _, match := range matches {
+		if match.Start >= start && match.End <= end {
+			snippetMatches = append(snippetMatches, query.TextRange{Start: match.Start - start, End: match.End - start})
+		}
+	}
+	return append(highlights, query.Highlight{
+		Field:   field,
+		Locale:  locale,
+		Text:    string(runes[start:end]),
+		Matches: snippetMatches,
+	})
+}
+
+// findMatches находит слова, начинающиеся с одного из terms. Сравнение без учета регистра и диакритики,
+// как в FULLTEXT индексе с сопоставлением utf8mb4_unicode_ci
+func findMatches(runes []rune, terms []string) []query.TextRange {
+	keys := make([]string, 0, len(terms))
+	for _, term := range terms {
+		keys = append(keys, model.NameKey(term))
+	}
+
+	var matches []query.TextRange
+	for i := 0; i < len(runes); {
+		if isNotWordRune(runes[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && !isNotWordRune(runes[i]) {
+			i++
+		}
+		word := model.NameKey(string(runes[start:i]))
+		for _, key := range keys {
+			if strings.HasPrefix(word, key) {
+				matches = append(matches, query.TextRange{Start: start, End: i})
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// snippetBounds выбирает окно не длиннее maxHighlightLength, в котором совпадение firstMatch
+// стоит ближе к началу, и сдвигает границы окна к границам слов
+func snippetBounds(runes []rune, firstMatch int) (int, int) {
+	if len(runes) <= maxHighlightLength {
+		return 0, len(runes)
+	}
+	start := max(0, firstMatch-maxHighlightLength/4)
+	for start > 0 && start < firstMatch && !isNotWordRune(runes[start-1]) {
+		start++
+	}
+	end := min(len(runes), start+maxHighlightLength)
+	for end < len(runes) && end > firstMatch && !isNotWordRune(runes[end]) {
+		end--
+	}
+	if end <= firstMatch {
+		end = min(len(runes), start+maxHighlightLength)
+	}
+	return start, end
+}
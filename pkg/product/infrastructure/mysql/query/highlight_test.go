@@ -0,0 +1,117 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/product/application/query"
+)
+
+func TestFindMatches(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		terms    []string
+		expected []query.TextRange
+	}{
+		{
+			name:     "prefix of word",
+			text:     "Coffee beans",
+			terms:    []string{"cof", "bean"},
+			expected: []query.TextRange{{Start: 0, End: 6}, {Start: 7, End: 12}},
+		},
+		{
+			name:     "not a prefix",
+			text:     "decaf coffee",
+			terms:    []string{"caf"},
+			expected: nil,
+		},
+		{
+			name:     "multibyte text",
+			text:     "Зелёный чай с жасмином",
+			terms:    []string{"ЧАЙ", "жасмин"},
+			expected: []query.TextRange{{Start: 8, End: 11}, {Start: 14, End: 22}},
+		},
+		{
+			name:     "diacritics in text",
+			text:     "Crème brûlée",
+			terms:    []string{"creme", "BRULEE"},
+			expected: []query.TextRange{{Start: 0, End: 5}, {Start: 6, End: 12}},
+		},
+		{
+			name:     "diacritics in term",
+			text:     "Зеленый чай",
+			terms:    []string{"зелёный"},
+			expected: []query.TextRange{{Start: 0, End: 7}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, findMatches([]rune(tc.text), tc.terms))
+		})
+	}
+}
+
+func TestSnippetBounds(t *testing.T) {
+	testCases := []struct {
+		name          string
+		text          string
+		firstMatch    int
+		expectedStart int
+		expectedEnd   int
+	}{
+		{
+			name:          "short text is returned whole",
+			text:          "Зелёный чай",
+			firstMatch:    8,
+			expectedStart: 0,
+			expectedEnd:   len([]rune("Зелёный чай")),
+		},
+		{
+			// 60 слов по 6 символов, совпадение на 360 символе: окно начинается за 50 символов до него
+			// и сдвигается вперед к началу слова
+			name:          "match near end of long text",
+			text:          strings.Repeat("слово ", 60) + "чай",
+			firstMatch:    360,
+			expectedStart: 312,
+			expectedEnd:   363,
+		},
+		{
+			// Конец окна сдвигается назад к границе слова
+			name:          "end snapped to word boundary",
+			text:          strings.Repeat("слово ", 60),
+			firstMatch:    0,
+			expectedStart: 0,
+			expectedEnd:   197,
+		},
+		{
+			// Слово длиннее окна: сдвиг конца прошел бы совпадение, поэтому окно обрезается посреди слова
+			name:          "word longer than window",
+			text:          strings.Repeat("я", 300),
+			firstMatch:    0,
+			expectedStart: 0,
+			expectedEnd:   maxHighlightLength,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end := snippetBounds([]rune(tc.text), tc.firstMatch)
+			assert.Equal(t, tc.expectedStart, start)
+			assert.Equal(t, tc.expectedEnd, end)
+		})
+	}
+}
+
+func TestAppendHighlight_MatchNearEndOfLongText(t *testing.T) {
+	text := strings.Repeat("слово ", 60) + "чай"
+
+	highlights := appendHighlight(nil, "name", "ru", text, []string{"чай"})
+
+	require.Len(t, highlights, 1)
+	assert.Equal(t, strings.Repeat("слово ", 8)+"чай", highlights[0].Text)
+	// Совпадения считаются в символах от начала фрагмента
+	assert.Equal(t, []query.TextRange{{Start: 48, End: 51}}, highlights[0].Matches)
+}
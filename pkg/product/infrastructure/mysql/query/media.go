@@ -0,0 +1,55 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+// findMedia возвращает медиа продуктов, сгруппированные по продуктам, в порядке показа
+func (q *productQueryService) findMedia(ctx context.Context, placeholders string, productArgs []interface{}) (map[uuid.UUID][]appmodel.Media, error) {
+	var mediaDTOs []struct {
+		MediaID     uuid.UUID      `db:"media_id"`
+		ProductID   uuid.UUID      `db:"product_id"`
+		URL         sql.NullString `db:"url"`
+		StorageKey  sql.NullString `db:"storage_key"`
+		AltText     string         `db:"alt_text"`
+		Width       int            `db:"width"`
+		Height      int            `db:"height"`
+		ContentType string         `db:"content_type"`
+		Primary     bool           `db:"is_primary"`
+	}
+	err := q.client.SelectContext(
+		ctx,
+		&mediaDTOs,
+		`
+		SELECT media_id, product_id, url, storage_key, alt_text, width, height, content_type, is_primary
+		FROM product_media
+		WHERE product_id IN (`+placeholders+`)
+		ORDER BY position
+		`,
+		productArgs...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	media := make(map[uuid.UUID][]appmodel.Media)
+	for _, dto := range mediaDTOs {
+		media[dto.ProductID] = append(media[dto.ProductID], appmodel.Media{
+			MediaID:     dto.MediaID,
+			URL:         dto.URL.String,
+			StorageKey:  dto.StorageKey.String,
+			AltText:     dto.AltText,
+			Width:       dto.Width,
+			Height:      dto.Height,
+			ContentType: dto.ContentType,
+			Primary:     dto.Primary,
+		})
+	}
+	return media, nil
+}
@@ -3,6 +3,9 @@ package query
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
 	"github.com/google/uuid"
@@ -10,6 +13,7 @@ import (
 
 	appmodel "productservice/pkg/product/application/model"
 	"productservice/pkg/product/application/query"
+	"productservice/pkg/product/domain/model"
 )
 
 func NewProductQueryService(client mysql.ClientContext) query.ProductQueryService {
@@ -22,18 +26,66 @@ type productQueryService struct {
 	client mysql.ClientContext
 }
 
-func (q *productQueryService) FindProduct(ctx context.Context, productID uuid.UUID) (*appmodel.Product, error) {
-	productDTO := struct {
-		ProductID uuid.UUID `db:"product_id"`
-		Name      string    `db:"name"`
-		Price     int64     `db:"price"`
-	}{}
+type productDTO struct {
+	ProductID   uuid.UUID      `db:"product_id"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	SKU         sql.NullString `db:"sku"`
+	Status      string         `db:"status"`
+	Version     int64          `db:"version"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+type priceDTO struct {
+	ProductID uuid.UUID `db:"product_id"`
+	Currency  string    `db:"currency"`
+	Amount    int64     `db:"amount"`
+}
+
+const productColumns = `p.product_id, p.name, COALESCE(p.description, '') AS description, p.sku, p.status, p.version, p.created_at, p.updated_at`
+
+func (q *productQueryService) FindProduct(ctx context.Context, productID uuid.UUID, locale string) (*appmodel.Product, error) {
+	chain, err := query.LocaleChain(locale)
+	if err != nil {
+		return nil, err
+	}
+	return q.findProduct(ctx, chain, "p.product_id = ?", productID)
+}
+
+func (q *productQueryService) FindProductByName(ctx context.Context, name string) (*appmodel.Product, error) {
+	// Имя нормализуется так же, как при сохранении, а сравнение идет по сопоставлению колонки (utf8mb4_unicode_ci),
+	// на котором построен UNIQUE KEY (name)
+	return q.findProduct(ctx, nil, "p.name = ?", model.NormalizeName(name))
+}
 
+func (q *productQueryService) FindProductBySKU(ctx context.Context, sku string) (*appmodel.Product, error) {
+	sku = model.NormalizeSKU(sku)
+	return q.findProduct(
+		ctx,
+		nil,
+		"(p.sku = ? OR p.product_id IN (SELECT pv.product_id FROM product_variant pv WHERE pv.sku = ?))",
+		sku,
+		sku,
+	)
+}
+
+func (q *productQueryService) FindProductByBarcode(ctx context.Context, barcode string) (*appmodel.Product, error) {
+	return q.findProduct(
+		ctx,
+		nil,
+		"p.product_id IN (SELECT pb.product_id FROM product_barcode pb WHERE pb.barcode = ?)",
+		model.NormalizeBarcode(barcode),
+	)
+}
+
+func (q *productQueryService) findProduct(ctx context.Context, chain []string, condition string, args ...interface{}) (*appmodel.Product, error) {
+	var dto productDTO
 	err := q.client.GetContext(
 		ctx,
-		&productDTO,
-		`SELECT product_id, name, price FROM product WHERE product_id = ?`,
-		productID,
+		&dto,
+		`SELECT `+productColumns+` FROM product p WHERE `+condition+` AND p.deleted_at IS NULL`,
+		args...,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -42,9 +94,451 @@ func (q *productQueryService) FindProduct(ctx context.Context, productID uuid.UU
 		return nil, errors.WithStack(err)
 	}
 
-	return &appmodel.Product{
-		ProductID: productDTO.ProductID,
-		Name:      productDTO.Name,
-		Price:     productDTO.Price,
-	}, nil
+	products, err := q.toAppProducts(ctx, []productDTO{dto}, chain)
+	if err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+func (q *productQueryService) FindProducts(ctx context.Context, productIDs []uuid.UUID) ([]appmodel.Product, error) {
+	if len(productIDs) > query.MaxFindProductsBatchSize {
+		return nil, errors.Wrapf(query.ErrTooManyProductIDs, "got %d, max %d", len(productIDs), query.MaxFindProductsBatchSize)
+	}
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders, args := inPlaceholders(productIDs)
+	var dtos []productDTO
+	err := q.client.SelectContext(
+		ctx,
+		&dtos,
+		`SELECT `+productColumns+` FROM product p WHERE p.product_id IN (`+placeholders+`) AND p.deleted_at IS NULL`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return q.toAppProducts(ctx, dtos, nil)
+}
+
+func (q *productQueryService) GetPriceHistory(ctx context.Context, productID uuid.UUID, currency *string) ([]appmodel.PriceHistoryEntry, error) {
+	conditions := []string{"product_id = ?"}
+	args := []interface{}{productID}
+	if currency != nil {
+		conditions = append(conditions, "currency = ?")
+		args = append(args, strings.ToUpper(strings.TrimSpace(*currency)))
+	}
+
+	var dtos []struct {
+		Currency      string        `db:"currency"`
+		Amount        sql.NullInt64 `db:"amount"`
+		EffectiveFrom time.Time     `db:"effective_from"`
+	}
+	err := q.client.SelectContext(
+		ctx,
+		&dtos,
+		`SELECT currency, amount, effective_from FROM product_price_history WHERE `+strings.Join(conditions, " AND ")+
+			` ORDER BY effective_from, history_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	entries := make([]appmodel.PriceHistoryEntry, 0, len(dtos))
+	for _, dto := range dtos {
+		entry := appmodel.PriceHistoryEntry{
+			Currency:      dto.Currency,
+			EffectiveFrom: dto.EffectiveFrom,
+		}
+		if dto.Amount.Valid {
+			entry.Amount = &dto.Amount.Int64
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+var sortColumns = map[query.ProductSortField]string{
+	query.SortByCreatedAt: "p.created_at",
+	query.SortByName:      "p.name",
+	query.SortByPrice:     "pp.amount",
+}
+
+func (q *productQueryService) ListProducts(ctx context.Context, spec query.ListProductsSpec) (*query.ProductList, error) {
+	sortColumn, ok := sortColumns[spec.SortBy]
+	if !ok {
+		return nil, errors.Errorf("unknown sort field %d", spec.SortBy)
+	}
+	if len(spec.AttributeFilters) > query.MaxAttributeFilters {
+		return nil, errors.Wrapf(query.ErrTooManyAttributeFilters, "got %d, max %d", len(spec.AttributeFilters), query.MaxAttributeFilters)
+	}
+	for _, status := range spec.Statuses {
+		_, err := model.ParseProductStatus(status)
+		if err != nil {
+			return nil, err
+		}
+	}
+	chain, err := query.LocaleChain(spec.Locale)
+	if err != nil {
+		return nil, err
+	}
+	spec.AttributeFilters, err = q.normalizeAttributeFilters(ctx, spec.AttributeFilters)
+	if err != nil {
+		return nil, err
+	}
+	limit := spec.Limit
+	if limit <= 0 {
+		limit = query.DefaultListLimit
+	}
+	if limit > query.MaxListLimit {
+		limit = query.MaxListLimit
+	}
+	priceCurrency := strings.ToUpper(spec.PriceCurrency)
+	if priceCurrency == "" {
+		priceCurrency = model.DefaultCurrency
+	}
+
+	var (
+		joins []string
+		args  []interface{}
+	)
+	if spec.SortBy == query.SortByPrice || spec.MinPrice != nil || spec.MaxPrice != nil {
+		joins = append(joins, "INNER JOIN product_price pp ON pp.product_id = p.product_id AND pp.currency = ?")
+		args = append(args, priceCurrency)
+	}
+
+	conditions, conditionArgs := buildListConditions(spec)
+	args = append(args, conditionArgs...)
+	direction, comparison := "ASC", ">"
+	if spec.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	if spec.Cursor != "" {
+		cursor, err := decodeCursor(spec.Cursor, spec, priceCurrency)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, fmt.Sprintf(
+			"(%[1]s %[2]s ? OR (%[1]s = ? AND p.product_id %[2]s ?))",
+			sortColumn,
+			comparison,
+		))
+		args = append(args, cursor.sortValue(), cursor.sortValue(), cursor.ProductID)
+	}
+
+	// Запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	args = append(args, limit+1)
+	var dtos []productDTO
+	err = q.client.SelectContext(
+		ctx,
+		&dtos,
+		fmt.Sprintf(
+			`SELECT %s FROM product p %s WHERE %s ORDER BY %s %[5]s, p.product_id %[5]s LIMIT ?`,
+			productColumns,
+			strings.Join(joins, " "),
+			strings.Join(conditions, " AND "),
+			sortColumn,
+			direction,
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	hasNextPage := len(dtos) > limit
+	if hasNextPage {
+		dtos = dtos[:limit]
+	}
+	products, err := q.toAppProducts(ctx, dtos, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &query.ProductList{Products: products}
+	if hasNextPage {
+		last := products[len(products)-1]
+		cursor := productCursor{
+			SortBy:     spec.SortBy,
+			Descending: spec.Descending,
+			Name:       last.Name,
+			CreatedAt:  last.CreatedAt,
+			ProductID:  last.ProductID,
+		}
+		if spec.SortBy == query.SortByPrice {
+			cursor.Currency = priceCurrency
+			for _, price := range last.Prices {
+				if price.Currency == priceCurrency {
+					cursor.Price = price.Amount
+				}
+			}
+		}
+		result.NextCursor, err = encodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func buildListConditions(spec query.ListProductsSpec) (conditions []string, args []interface{}) {
+	conditions = append(conditions, "p.deleted_at IS NULL")
+	if spec.NamePrefix != nil {
+		conditions = append(conditions, "p.name LIKE ?")
+		args = append(args, escapeLike(*spec.NamePrefix)+"%")
+	}
+	if spec.MinPrice != nil {
+		conditions = append(conditions, "pp.amount >= ?")
+		args = append(args, *spec.MinPrice)
+	}
+	if spec.MaxPrice != nil {
+		conditions = append(conditions, "pp.amount <= ?")
+		args = append(args, *spec.MaxPrice)
+	}
+	if spec.CreatedFrom != nil {
+		conditions = append(conditions, "p.created_at >= ?")
+		args = append(args, *spec.CreatedFrom)
+	}
+	if spec.CreatedTo != nil {
+		conditions = append(conditions, "p.created_at < ?")
+		args = append(args, *spec.CreatedTo)
+	}
+	if spec.UpdatedFrom != nil {
+		conditions = append(conditions, "p.updated_at >= ?")
+		args = append(args, *spec.UpdatedFrom)
+	}
+	if spec.UpdatedTo != nil {
+		conditions = append(conditions, "p.updated_at < ?")
+		args = append(args, *spec.UpdatedTo)
+	}
+	if len(spec.Statuses) != 0 {
+		placeholders := make([]string, 0, len(spec.Statuses))
+		for _, status := range spec.Statuses {
+			placeholders = append(placeholders, "?")
+			args = append(args, status)
+		}
+		conditions = append(conditions, "p.status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if spec.CategoryID != nil {
+		categories := "?"
+		if spec.IncludeSubcategories {
+			categories = `
+				WITH RECURSIVE subtree (category_id) AS (
+					SELECT category_id FROM category WHERE category_id = ?
+					UNION ALL
+					SELECT c.category_id FROM category c INNER JOIN subtree s ON c.parent_id = s.category_id
+				)
+				SELECT category_id FROM subtree`
+		}
+		conditions = append(conditions, "p.product_id IN (SELECT pc.product_id FROM product_category pc WHERE pc.category_id IN ("+categories+"))")
+		args = append(args, *spec.CategoryID)
+	}
+	for _, filter := range spec.AttributeFilters {
+		attributeConditions := []string{"pa.code = ?"}
+		args = append(args, model.NormalizeAttributeCode(filter.Code))
+		if filter.Value != nil {
+			attributeConditions = append(attributeConditions, "pa.value = ?")
+			args = append(args, *filter.Value)
+		}
+		if filter.MinNumber != nil {
+			attributeConditions = append(attributeConditions, "pa.value_number >= ?")
+			args = append(args, *filter.MinNumber)
+		}
+		if filter.MaxNumber != nil {
+			attributeConditions = append(attributeConditions, "pa.value_number <= ?")
+			args = append(args, *filter.MaxNumber)
+		}
+		conditions = append(conditions, "p.product_id IN (SELECT pa.product_id FROM product_attribute pa WHERE "+strings.Join(attributeConditions, " AND ")+")")
+	}
+	return conditions, args
+}
+
+// normalizeAttributeFilters приводит значения фильтров к виду, в котором они хранятся в product_attribute.
+// Атрибут без определения не может быть у продукта, поэтому значение фильтра по нему не меняется
+func (q *productQueryService) normalizeAttributeFilters(ctx context.Context, filters []query.AttributeFilter) ([]query.AttributeFilter, error) {
+	result := make([]query.AttributeFilter, 0, len(filters))
+	for _, filter := range filters {
+		if filter.Value != nil {
+			definition, err := findAttributeDefinition(ctx, q.client, model.NormalizeAttributeCode(filter.Code))
+			if err != nil {
+				return nil, err
+			}
+			if definition != nil {
+				value, err := model.NormalizeAttributeValue(*definition, *filter.Value)
+				if err != nil {
+					return nil, err
+				}
+				filter.Value = &value
+			}
+		}
+		result = append(result, filter)
+	}
+	return result, nil
+}
+
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+func inPlaceholders(productIDs []uuid.UUID) (string, []interface{}) {
+	placeholders := make([]string, 0, len(productIDs))
+	args := make([]interface{}, 0, len(productIDs))
+	for _, productID := range productIDs {
+		placeholders = append(placeholders, "?")
+		args = append(args, productID)
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
+// toAppProducts дополняет продукты переводами, ценами, штрихкодами, категориями и вариантами,
+// не выполняя запросов на каждый продукт, и выбирает переводы по цепочке локалей chain
+func (q *productQueryService) toAppProducts(ctx context.Context, dtos []productDTO, chain []string) ([]appmodel.Product, error) {
+	products := make([]appmodel.Product, 0, len(dtos))
+	if len(dtos) == 0 {
+		return products, nil
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(dtos))
+	for _, dto := range dtos {
+		productIDs = append(productIDs, dto.ProductID)
+	}
+	placeholders, args := inPlaceholders(productIDs)
+	var translationDTOs []struct {
+		ProductID   uuid.UUID `db:"product_id"`
+		Locale      string    `db:"locale"`
+		Name        string    `db:"name"`
+		Description string    `db:"description"`
+	}
+	err := q.client.SelectContext(
+		ctx,
+		&translationDTOs,
+		`SELECT product_id, locale, name, description FROM product_translation WHERE product_id IN (`+placeholders+`) ORDER BY locale`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	translations := make(map[uuid.UUID][]appmodel.Translation, len(dtos))
+	for _, translation := range translationDTOs {
+		translations[translation.ProductID] = append(translations[translation.ProductID], appmodel.Translation{
+			Locale:      translation.Locale,
+			Name:        translation.Name,
+			Description: translation.Description,
+		})
+	}
+
+	var priceDTOs []priceDTO
+	err = q.client.SelectContext(
+		ctx,
+		&priceDTOs,
+		`SELECT product_id, currency, amount FROM product_price WHERE product_id IN (`+placeholders+`) ORDER BY currency`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	prices := make(map[uuid.UUID][]appmodel.Money, len(dtos))
+	for _, price := range priceDTOs {
+		prices[price.ProductID] = append(prices[price.ProductID], appmodel.Money{
+			Amount:   price.Amount,
+			Currency: price.Currency,
+		})
+	}
+
+	var barcodeDTOs []struct {
+		ProductID uuid.UUID `db:"product_id"`
+		Barcode   string    `db:"barcode"`
+	}
+	err = q.client.SelectContext(
+		ctx,
+		&barcodeDTOs,
+		`SELECT product_id, barcode FROM product_barcode WHERE product_id IN (`+placeholders+`) ORDER BY barcode`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	barcodes := make(map[uuid.UUID][]string, len(dtos))
+	for _, barcode := range barcodeDTOs {
+		barcodes[barcode.ProductID] = append(barcodes[barcode.ProductID], barcode.Barcode)
+	}
+
+	var categoryDTOs []struct {
+		ProductID  uuid.UUID `db:"product_id"`
+		CategoryID uuid.UUID `db:"category_id"`
+	}
+	err = q.client.SelectContext(
+		ctx,
+		&categoryDTOs,
+		`SELECT product_id, category_id FROM product_category WHERE product_id IN (`+placeholders+`) ORDER BY category_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	categoryIDs := make(map[uuid.UUID][]uuid.UUID, len(dtos))
+	for _, category := range categoryDTOs {
+		categoryIDs[category.ProductID] = append(categoryIDs[category.ProductID], category.CategoryID)
+	}
+
+	variants, err := q.findVariants(ctx, placeholders, args)
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := q.findMedia(ctx, placeholders, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var attributeDTOs []struct {
+		ProductID uuid.UUID `db:"product_id"`
+		Code      string    `db:"code"`
+		Value     string    `db:"value"`
+	}
+	err = q.client.SelectContext(
+		ctx,
+		&attributeDTOs,
+		`SELECT product_id, code, value FROM product_attribute WHERE product_id IN (`+placeholders+`) ORDER BY code`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	attributes := make(map[uuid.UUID][]appmodel.AttributeValue, len(dtos))
+	for _, attribute := range attributeDTOs {
+		attributes[attribute.ProductID] = append(attributes[attribute.ProductID], appmodel.AttributeValue{
+			Code:  attribute.Code,
+			Value: attribute.Value,
+		})
+	}
+
+	for _, dto := range dtos {
+		product := appmodel.Product{
+			ProductID:    dto.ProductID,
+			Name:         dto.Name,
+			Description:  dto.Description,
+			Translations: translations[dto.ProductID],
+			Prices:       prices[dto.ProductID],
+			SKU:          dto.SKU.String,
+			Status:       dto.Status,
+			Barcodes:     barcodes[dto.ProductID],
+			CategoryIDs:  categoryIDs[dto.ProductID],
+			Attributes:   attributes[dto.ProductID],
+			Variants:     variants[dto.ProductID],
+			Media:        media[dto.ProductID],
+			Version:      dto.Version,
+			CreatedAt:    dto.CreatedAt,
+			UpdatedAt:    dto.UpdatedAt,
+		}
+		product.Localized = query.Localize(product, chain)
+		products = append(products, product)
+	}
+	return products, nil
 }
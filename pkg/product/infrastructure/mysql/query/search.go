@@ -0,0 +1,200 @@
+package query
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/product/application/query"
+	"productservice/pkg/product/domain/model"
+)
+
+// NewProductSearchService поиск по FULLTEXT индексам name_description_ft продуктов и их переводов
+func NewProductSearchService(client mysql.ClientContext) query.ProductSearchService {
+	return &productSearchService{
+		client:   client,
+		products: &productQueryService{client: client},
+	}
+}
+
+type productSearchService struct {
+	client   mysql.ClientContext
+	products *productQueryService
+}
+
+// searchCursor смещение следующей страницы. Ранжирование по релевантности не дает
+// устойчивого ключа для keyset-пагинации, поэтому пагинация по смещению
+type searchCursor struct {
+	Query  string `json:"query"`
+	Offset int    `json:"offset"`
+}
+
+func (s *productSearchService) SearchProducts(ctx context.Context, spec query.SearchProductsSpec) (*query.ProductSearchResult, error) {
+	terms := searchTerms(spec.Query)
+	if len(terms) == 0 {
+		return nil, errors.Wrap(query.ErrInvalidSearchQuery, "query must contain at least one word")
+	}
+	for _, status := range spec.Statuses {
+		_, err := model.ParseProductStatus(status)
+		if err != nil {
+			return nil, err
+		}
+	}
+	chain, err := query.LocaleChain(spec.Locale)
+	if err != nil {
+		return nil, err
+	}
+	limit := spec.Limit
+	if limit <= 0 {
+		limit = query.DefaultSearchLimit
+	}
+	if limit > query.MaxSearchLimit {
+		limit = query.MaxSearchLimit
+	}
+	normalizedQuery := strings.Join(terms, " ")
+	offset := 0
+	if spec.Cursor != "" {
+		offset, err = decodeSearchCursor(spec.Cursor, normalizedQuery)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	against := booleanQuery(terms)
+	conditions := []string{"p.deleted_at IS NULL", "(MATCH(p.name, p.description) AGAINST (? IN BOOLEAN MODE) OR t.product_id IS NOT NULL)"}
+	args := []interface{}{against, against, against, against}
+	if len(spec.Statuses) != 0 {
+		placeholders := make([]string, 0, len(spec.Statuses))
+		for _, status := range spec.Statuses {
+			placeholders = append(placeholders, "?")
+			args = append(args, status)
+		}
+		conditions = append(conditions, "p.status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	// Запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	args = append(args, limit+1, offset)
+
+	var dtos []struct {
+		productDTO
+		Relevance float64 `db:"relevance"`
+	}
+	err = s.client.SelectContext(
+		ctx,
+		&dtos,
+		`SELECT `+productColumns+`,
+			GREATEST(MATCH(p.name, p.description) AGAINST (? IN BOOLEAN MODE), COALESCE(t.relevance, 0)) AS relevance
+		FROM product p
+		LEFT JOIN (
+			SELECT pt.product_id, MAX(MATCH(pt.name, pt.description) AGAINST (? IN BOOLEAN MODE)) AS relevance
+			FROM product_translation pt
+			WHERE MATCH(pt.name, pt.description) AGAINST (? IN BOOLEAN MODE)
+			GROUP BY pt.product_id
+		) t ON t.product_id = p.product_id
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY relevance DESC, p.product_id
+		LIMIT ? OFFSET ?`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	hasNextPage := len(dtos) > limit && offset+limit < query.MaxSearchOffset
+	if len(dtos) > limit {
+		dtos = dtos[:limit]
+	}
+	productDTOs := make([]productDTO, 0, len(dtos))
+	for _, dto := range dtos {
+		productDTOs = append(productDTOs, dto.productDTO)
+	}
+	products, err := s.products.toAppProducts(ctx, productDTOs, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &query.ProductSearchResult{
+		Hits: make([]query.ProductSearchHit, 0, len(products)),
+	}
+	for i, product := range products {
+		hit := query.ProductSearchHit{
+			Product:    product,
+			Relevance:  dtos[i].Relevance,
+			Highlights: appendHighlight(nil, "name", "", product.Name, terms),
+		}
+		hit.Highlights = appendHighlight(hit.Highlights, "description", "", product.Description, terms)
+		for _, translation := range product.Translations {
+			hit.Highlights = appendHighlight(hit.Highlights, "name", translation.Locale, translation.Name, terms)
+			hit.Highlights = appendHighlight(hit.Highlights, "description", translation.Locale, translation.Description, terms)
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	if hasNextPage {
+		result.NextCursor, err = encodeSearchCursor(searchCursor{Query: normalizedQuery, Offset: offset + limit})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// searchTerms разбивает запрос на слова без повторов, операторы полнотекстового поиска отбрасываются
+func searchTerms(q string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(q, isNotWordRune) {
+		word = strings.ToLower(word)
+		if !slices.Contains(terms, word) {
+			terms = append(terms, word)
+		}
+		if len(terms) == query.MaxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// booleanQuery требует каждое слово запроса как начало слова, например "+зелен* +чай*"
+func booleanQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, "+"+term+"*")
+	}
+	return strings.Join(parts, " ")
+}
+
+func encodeSearchCursor(c searchCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeSearchCursor(s string, normalizedQuery string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, errors.WithStack(query.ErrInvalidCursor)
+	}
+	var c searchCursor
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return 0, errors.WithStack(query.ErrInvalidCursor)
+	}
+	if c.Query != normalizedQuery {
+		return 0, errors.Wrap(query.ErrInvalidCursor, "cursor was issued for another query")
+	}
+	if c.Offset < 0 || c.Offset > query.MaxSearchOffset {
+		return 0, errors.Wrap(query.ErrInvalidCursor, fmt.Sprintf("offset must be between 0 and %d", query.MaxSearchOffset))
+	}
+	return c.Offset, nil
+}
@@ -0,0 +1,105 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/product/application/query"
+	"productservice/pkg/product/infrastructure/integrationevent"
+)
+
+func NewProductSnapshotService(client mysql.TransactionalClient) query.ProductSnapshotService {
+	return &productSnapshotService{
+		client: client,
+	}
+}
+
+type productSnapshotService struct {
+	client mysql.TransactionalClient
+}
+
+func (s *productSnapshotService) InSnapshot(ctx context.Context, f func(watermark query.SnapshotWatermark, products query.ProductQueryService) error) error {
+	conn, err := s.client.Connection(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer conn.Close()
+
+	// В REPEATABLE READ все чтения транзакции видят снимок, созданный первым чтением
+	tx, err := conn.BeginTransaction(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// Транзакция только читает, откат лишь освобождает снимок
+	defer tx.Rollback()
+
+	var maxUpdatedAt sql.NullTime
+	err = tx.GetContext(ctx, &maxUpdatedAt, `SELECT MAX(updated_at) FROM product WHERE deleted_at IS NULL`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	outboxEventID, err := s.outboxWatermark(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	watermark := query.SnapshotWatermark{OutboxEventID: outboxEventID}
+	if maxUpdatedAt.Valid {
+		watermark.MaxUpdatedAt = maxUpdatedAt.Time
+	}
+	return f(watermark, NewProductQueryService(tx))
+}
+
+// outboxWatermark возвращает последнее событие, до которого включительно все события видны в снимке.
+// Идентификаторы событий выдаются до фиксации транзакций, поэтому событие с меньшим идентификатором
+// может зафиксироваться позже снимка. Как и обработчик outbox, сравниваем зафиксированные в снимке события
+// с видимыми без изоляции и останавливаемся перед первым расхождением.
+// События до last_tracked_event_id обработчика уже окончательны, их не проверяем
+func (s *productSnapshotService) outboxWatermark(ctx context.Context, snapshot mysql.ClientContext) (uint64, error) {
+	var lastTracked uint64
+	err := snapshot.GetContext(
+		ctx,
+		&lastTracked,
+		fmt.Sprintf(`SELECT COALESCE(MAX(last_tracked_event_id), 0) FROM outbox_%s_tracked_event WHERE transport_name = ?`, integrationevent.TransportName),
+		integrationevent.TransportName,
+	)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	eventIDsQuery := fmt.Sprintf(`SELECT event_id FROM outbox_%s_event WHERE event_id > ? ORDER BY event_id`, integrationevent.TransportName)
+	var committed []uint64
+	err = snapshot.SelectContext(ctx, &committed, eventIDsQuery, lastTracked)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	conn, err := s.client.Connection(ctx)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer conn.Close()
+	tx, err := conn.BeginTransaction(ctx, &sql.TxOptions{Isolation: sql.LevelReadUncommitted, ReadOnly: true})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer tx.Rollback()
+	var uncommitted []uint64
+	err = tx.SelectContext(ctx, &uncommitted, eventIDsQuery, lastTracked)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	watermark := lastTracked
+	for i, eventID := range uncommitted {
+		if i >= len(committed) || committed[i] != eventID {
+			break
+		}
+		watermark = eventID
+	}
+	return watermark, nil
+}
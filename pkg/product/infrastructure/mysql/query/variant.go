@@ -0,0 +1,85 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "productservice/pkg/product/application/model"
+)
+
+// findVariants возвращает варианты продуктов, сгруппированные по продуктам, в порядке добавления
+func (q *productQueryService) findVariants(ctx context.Context, placeholders string, productArgs []interface{}) (map[uuid.UUID][]appmodel.Variant, error) {
+	var variantDTOs []struct {
+		VariantID uuid.UUID      `db:"variant_id"`
+		ProductID uuid.UUID      `db:"product_id"`
+		Options   []byte         `db:"options"`
+		SKU       sql.NullString `db:"sku"`
+	}
+	err := q.client.SelectContext(
+		ctx,
+		&variantDTOs,
+		`SELECT variant_id, product_id, options, sku FROM product_variant WHERE product_id IN (`+placeholders+`) ORDER BY position`,
+		productArgs...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(variantDTOs) == 0 {
+		return nil, nil
+	}
+
+	var priceDTOs []struct {
+		VariantID uuid.UUID `db:"variant_id"`
+		Currency  string    `db:"currency"`
+		Amount    int64     `db:"amount"`
+	}
+	err = q.client.SelectContext(
+		ctx,
+		&priceDTOs,
+		`
+		SELECT vp.variant_id, vp.currency, vp.amount
+		FROM product_variant_price vp
+			INNER JOIN product_variant v ON v.variant_id = vp.variant_id
+		WHERE v.product_id IN (`+placeholders+`)
+		ORDER BY vp.currency
+		`,
+		productArgs...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	prices := make(map[uuid.UUID][]appmodel.Money, len(variantDTOs))
+	for _, price := range priceDTOs {
+		prices[price.VariantID] = append(prices[price.VariantID], appmodel.Money{
+			Amount:   price.Amount,
+			Currency: price.Currency,
+		})
+	}
+
+	variants := make(map[uuid.UUID][]appmodel.Variant)
+	for _, dto := range variantDTOs {
+		var optionDTOs []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		}
+		err = json.Unmarshal(dto.Options, &optionDTOs)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		options := make([]appmodel.VariantOption, 0, len(optionDTOs))
+		for _, option := range optionDTOs {
+			options = append(options, appmodel.VariantOption{Name: option.Name, Value: option.Value})
+		}
+		variants[dto.ProductID] = append(variants[dto.ProductID], appmodel.Variant{
+			VariantID: dto.VariantID,
+			Options:   options,
+			Prices:    prices[dto.VariantID],
+			SKU:       dto.SKU.String,
+		})
+	}
+	return variants, nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/product/domain/model"
+)
+
+func NewAttributeDefinitionRepository(ctx context.Context, client mysql.ClientContext) model.AttributeDefinitionRepository {
+	return &attributeDefinitionRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type attributeDefinitionRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *attributeDefinitionRepository) Store(definition model.AttributeDefinition) error {
+	enumValues := definition.EnumValues
+	if enumValues == nil {
+		enumValues = []string{}
+	}
+	enumValuesJSON, err := json.Marshal(enumValues)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = r.client.ExecContext(r.ctx,
+		`
+	INSERT INTO attribute_definition (code, name, type, enum_values, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		name=VALUES(name),
+		enum_values=VALUES(enum_values),
+		updated_at=VALUES(updated_at)
+	`,
+		definition.Code,
+		definition.Name,
+		definition.Type.String(),
+		string(enumValuesJSON),
+		definition.CreatedAt,
+		definition.UpdatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *attributeDefinitionRepository) Find(code string) (*model.AttributeDefinition, error) {
+	definitionDTO := struct {
+		Code       string    `db:"code"`
+		Name       string    `db:"name"`
+		Type       string    `db:"type"`
+		EnumValues []byte    `db:"enum_values"`
+		CreatedAt  time.Time `db:"created_at"`
+		UpdatedAt  time.Time `db:"updated_at"`
+	}{}
+	err := r.client.GetContext(
+		r.ctx,
+		&definitionDTO,
+		`SELECT code, name, type, enum_values, created_at, updated_at FROM attribute_definition WHERE code = ?`,
+		code,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(model.ErrAttributeNotFound, "attribute %q", code)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	definition := &model.AttributeDefinition{
+		Code:      definitionDTO.Code,
+		Name:      definitionDTO.Name,
+		CreatedAt: definitionDTO.CreatedAt,
+		UpdatedAt: definitionDTO.UpdatedAt,
+	}
+	definition.Type, err = model.ParseAttributeType(definitionDTO.Type)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = json.Unmarshal(definitionDTO.EnumValues, &definition.EnumValues)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(definition.EnumValues) == 0 {
+		definition.EnumValues = nil
+	}
+	return definition, nil
+}
+
+func (r *attributeDefinitionRepository) Delete(code string) error {
+	_, err := r.client.ExecContext(r.ctx, `DELETE FROM attribute_definition WHERE code = ?`, code)
+	return errors.WithStack(err)
+}
+
+func (r *attributeDefinitionRepository) IsUsed(code string, value *string) (bool, error) {
+	query, args := `SELECT EXISTS(SELECT 1 FROM product_attribute WHERE code = ?)`, []interface{}{code}
+	if value != nil {
+		query, args = `SELECT EXISTS(SELECT 1 FROM product_attribute WHERE code = ? AND value = ?)`, []interface{}{code, *value}
+	}
+	var used bool
+	err := r.client.GetContext(r.ctx, &used, query, args...)
+	return used, errors.WithStack(err)
+}
+
+// storeAttributes перезаписывает значения атрибутов продукта.
+// value_number заполняется для всех значений, похожих на число, фильтр по числу всегда указывает код атрибута
+func (p *productRepository) storeAttributes(productID uuid.UUID, attributes []model.AttributeValue) error {
+	_, err := p.client.ExecContext(p.ctx, `DELETE FROM product_attribute WHERE product_id = ?`, productID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(attributes))
+	args := make([]interface{}, 0, len(attributes)*4)
+	for _, attribute := range attributes {
+		var number sql.NullFloat64
+		if f, err := strconv.ParseFloat(attribute.Value, 64); err == nil {
+			number = sql.NullFloat64{Float64: f, Valid: true}
+		}
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		args = append(args, productID, attribute.Code, attribute.Value, number)
+	}
+	_, err = p.client.ExecContext(
+		p.ctx,
+		`INSERT INTO product_attribute (product_id, code, value, value_number) VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *productRepository) findAttributes(productID uuid.UUID) ([]model.AttributeValue, error) {
+	var attributeDTOs []struct {
+		Code  string `db:"code"`
+		Value string `db:"value"`
+	}
+	err := p.client.SelectContext(
+		p.ctx,
+		&attributeDTOs,
+		`SELECT code, value FROM product_attribute WHERE product_id = ? ORDER BY code`,
+		productID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(attributeDTOs) == 0 {
+		return nil, nil
+	}
+
+	attributes := make([]model.AttributeValue, 0, len(attributeDTOs))
+	for _, attributeDTO := range attributeDTOs {
+		attributes = append(attributes, model.AttributeValue{Code: attributeDTO.Code, Value: attributeDTO.Value})
+	}
+	return attributes, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/product/domain/model"
+)
+
+func NewCategoryRepository(ctx context.Context, client mysql.ClientContext) model.CategoryRepository {
+	return &categoryRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type categoryRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *categoryRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *categoryRepository) Store(category model.Category) error {
+	_, err := r.client.ExecContext(r.ctx,
+		`
+	INSERT INTO category (category_id, parent_id, name, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		parent_id=VALUES(parent_id),
+		name=VALUES(name),
+		updated_at=VALUES(updated_at)
+	`,
+		category.CategoryID,
+		category.ParentID,
+		category.Name,
+		category.CreatedAt,
+		category.UpdatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *categoryRepository) Find(categoryID uuid.UUID) (*model.Category, error) {
+	categoryDTO := struct {
+		CategoryID uuid.UUID     `db:"category_id"`
+		ParentID   uuid.NullUUID `db:"parent_id"`
+		Name       string        `db:"name"`
+		CreatedAt  time.Time     `db:"created_at"`
+		UpdatedAt  time.Time     `db:"updated_at"`
+	}{}
+	err := r.client.GetContext(
+		r.ctx,
+		&categoryDTO,
+		`SELECT category_id, parent_id, name, created_at, updated_at FROM category WHERE category_id = ?`,
+		categoryID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrapf(model.ErrCategoryNotFound, "category %s", categoryID)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	category := &model.Category{
+		CategoryID: categoryDTO.CategoryID,
+		Name:       categoryDTO.Name,
+		CreatedAt:  categoryDTO.CreatedAt,
+		UpdatedAt:  categoryDTO.UpdatedAt,
+	}
+	if categoryDTO.ParentID.Valid {
+		category.ParentID = &categoryDTO.ParentID.UUID
+	}
+	return category, nil
+}
+
+func (r *categoryRepository) Delete(categoryID uuid.UUID) error {
+	_, err := r.client.ExecContext(r.ctx, `DELETE FROM category WHERE category_id = ?`, categoryID)
+	return errors.WithStack(err)
+}
+
+func (r *categoryRepository) HasChildren(categoryID uuid.UUID) (bool, error) {
+	return r.exists(`SELECT EXISTS(SELECT 1 FROM category WHERE parent_id = ?)`, categoryID)
+}
+
+func (r *categoryRepository) HasProducts(categoryID uuid.UUID) (bool, error) {
+	return r.exists(`SELECT EXISTS(SELECT 1 FROM product_category WHERE category_id = ?)`, categoryID)
+}
+
+func (r *categoryRepository) exists(query string, categoryID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.client.GetContext(r.ctx, &exists, query, categoryID)
+	return exists, errors.WithStack(err)
+}
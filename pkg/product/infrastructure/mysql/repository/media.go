@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/product/domain/model"
+)
+
+type mediaDTO struct {
+	MediaID     uuid.UUID      `db:"media_id"`
+	URL         sql.NullString `db:"url"`
+	StorageKey  sql.NullString `db:"storage_key"`
+	AltText     string         `db:"alt_text"`
+	Width       int            `db:"width"`
+	Height      int            `db:"height"`
+	ContentType string         `db:"content_type"`
+	Primary     bool           `db:"is_primary"`
+}
+
+// storeMedia перезаписывает медиа продукта, порядок сохраняется в position
+func (p *productRepository) storeMedia(productID uuid.UUID, media []model.Media) error {
+	_, err := p.client.ExecContext(p.ctx, `DELETE FROM product_media WHERE product_id = ?`, productID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(media) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(media))
+	args := make([]interface{}, 0, len(media)*10)
+	for i, m := range media {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			m.MediaID,
+			productID,
+			i,
+			sql.NullString{String: m.URL, Valid: m.URL != ""},
+			sql.NullString{String: m.StorageKey, Valid: m.StorageKey != ""},
+			m.AltText,
+			m.Width,
+			m.Height,
+			m.ContentType,
+			m.Primary,
+		)
+	}
+	_, err = p.client.ExecContext(
+		p.ctx,
+		`INSERT INTO product_media (media_id, product_id, position, url, storage_key, alt_text, width, height, content_type, is_primary) VALUES `+
+			strings.Join(placeholders, ", "),
+		args...,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *productRepository) findMedia(productID uuid.UUID) ([]model.Media, error) {
+	var dtos []mediaDTO
+	err := p.client.SelectContext(
+		p.ctx,
+		&dtos,
+		`
+		SELECT media_id, url, storage_key, alt_text, width, height, content_type, is_primary
+		FROM product_media
+		WHERE product_id = ?
+		ORDER BY position
+		`,
+		productID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(dtos) == 0 {
+		return nil, nil
+	}
+
+	media := make([]model.Media, 0, len(dtos))
+	for _, dto := range dtos {
+		media = append(media, model.Media{
+			MediaID:     dto.MediaID,
+			URL:         dto.URL.String,
+			StorageKey:  dto.StorageKey.String,
+			AltText:     dto.AltText,
+			Width:       dto.Width,
+			Height:      dto.Height,
+			ContentType: dto.ContentType,
+			Primary:     dto.Primary,
+		})
+	}
+	return media, nil
+}
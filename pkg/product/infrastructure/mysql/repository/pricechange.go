@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/product/domain/model"
+)
+
+func NewScheduledPriceChangeRepository(ctx context.Context, client mysql.ClientContext) model.ScheduledPriceChangeRepository {
+	return &scheduledPriceChangeRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type scheduledPriceChangeRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *scheduledPriceChangeRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *scheduledPriceChangeRepository) Store(change model.ScheduledPriceChange) error {
+	_, err := r.client.ExecContext(r.ctx,
+		`
+	INSERT INTO product_scheduled_price_change (change_id, product_id, currency, amount, effective_from, created_at, applied_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		applied_at=VALUES(applied_at)
+	`,
+		change.ChangeID,
+		change.ProductID,
+		change.Price.Currency,
+		change.Price.Amount,
+		change.EffectiveFrom,
+		change.CreatedAt,
+		change.AppliedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *scheduledPriceChangeRepository) FindDue(productID uuid.UUID, at time.Time) ([]model.ScheduledPriceChange, error) {
+	var changeDTOs []struct {
+		ChangeID      uuid.UUID    `db:"change_id"`
+		ProductID     uuid.UUID    `db:"product_id"`
+		Currency      string       `db:"currency"`
+		Amount        int64        `db:"amount"`
+		EffectiveFrom time.Time    `db:"effective_from"`
+		CreatedAt     time.Time    `db:"created_at"`
+		AppliedAt     sql.NullTime `db:"applied_at"`
+	}
+	err := r.client.SelectContext(
+		r.ctx,
+		&changeDTOs,
+		`
+		SELECT change_id, product_id, currency, amount, effective_from, created_at, applied_at
+		FROM product_scheduled_price_change
+		WHERE product_id = ? AND applied_at IS NULL AND effective_from <= ?
+		ORDER BY effective_from, change_id
+		`,
+		productID,
+		at,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	changes := make([]model.ScheduledPriceChange, 0, len(changeDTOs))
+	for _, changeDTO := range changeDTOs {
+		change := model.ScheduledPriceChange{
+			ChangeID:  changeDTO.ChangeID,
+			ProductID: changeDTO.ProductID,
+			Price: model.Money{
+				Amount:   changeDTO.Amount,
+				Currency: changeDTO.Currency,
+			},
+			EffectiveFrom: changeDTO.EffectiveFrom,
+			CreatedAt:     changeDTO.CreatedAt,
+		}
+		if changeDTO.AppliedAt.Valid {
+			change.AppliedAt = &changeDTO.AppliedAt.Time
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func (r *scheduledPriceChangeRepository) FindDueProductIDs(at time.Time, limit int) ([]uuid.UUID, error) {
+	var productIDs []uuid.UUID
+	// Изменения удаленных продуктов применятся после восстановления
+	err := r.client.SelectContext(
+		r.ctx,
+		&productIDs,
+		`
+		SELECT c.product_id
+		FROM product_scheduled_price_change c
+			INNER JOIN product p ON p.product_id = c.product_id
+		WHERE c.applied_at IS NULL AND c.effective_from <= ? AND p.deleted_at IS NULL
+		GROUP BY c.product_id
+		ORDER BY MIN(c.effective_from), c.product_id
+		LIMIT ?
+		`,
+		at,
+		limit,
+	)
+	return productIDs, errors.WithStack(err)
+}
@@ -32,35 +32,75 @@ func (p *productRepository) NextID() (uuid.UUID, error) {
 func (p *productRepository) Store(product model.Product) error {
 	_, err := p.client.ExecContext(p.ctx,
 		`
-	INSERT INTO product (product_id, name, price, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+	INSERT INTO product (product_id, name, description, sku, status, version, created_at, updated_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON DUPLICATE KEY UPDATE
 		name=VALUES(name),
-	    price=VALUES(price),
-	    updated_at=VALUES(updated_at)
+		description=VALUES(description),
+		sku=VALUES(sku),
+		status=VALUES(status),
+	    version=VALUES(version),
+	    updated_at=VALUES(updated_at),
+	    deleted_at=VALUES(deleted_at)
 	`,
 		product.ProductID,
 		product.Name,
-		product.Price,
+		product.Description,
+		sql.NullString{String: product.SKU, Valid: product.SKU != ""},
+		product.Status.String(),
+		product.Version,
 		product.CreatedAt,
 		product.UpdatedAt,
+		product.DeletedAt,
 	)
-	return errors.WithStack(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = p.storeTranslations(product.ProductID, product.Translations)
+	if err != nil {
+		return err
+	}
+	err = p.storePrices(product.ProductID, product.Prices, product.UpdatedAt, product.PricesEffectiveFrom)
+	if err != nil {
+		return err
+	}
+	err = p.storeBarcodes(product.ProductID, product.Barcodes)
+	if err != nil {
+		return err
+	}
+	err = p.storeVariants(product.ProductID, product.Variants)
+	if err != nil {
+		return err
+	}
+	err = p.storeAttributes(product.ProductID, product.Attributes)
+	if err != nil {
+		return err
+	}
+	err = p.storeMedia(product.ProductID, product.Media)
+	if err != nil {
+		return err
+	}
+	return p.storeCategories(product.ProductID, product.CategoryIDs)
 }
 
 func (p *productRepository) Find(spec model.FindSpec) (*model.Product, error) {
 	productDTO := struct {
-		ProductID uuid.UUID `db:"product_id"`
-		Name      string    `db:"name"`
-		Price     int64     `db:"price"`
-		CreatedAt time.Time `db:"created_at"`
-		UpdatedAt time.Time `db:"updated_at"`
+		ProductID   uuid.UUID      `db:"product_id"`
+		Name        string         `db:"name"`
+		Description string         `db:"description"`
+		SKU         sql.NullString `db:"sku"`
+		Status      string         `db:"status"`
+		Version     int64          `db:"version"`
+		CreatedAt   time.Time      `db:"created_at"`
+		UpdatedAt   time.Time      `db:"updated_at"`
+		DeletedAt   sql.NullTime   `db:"deleted_at"`
 	}{}
 	query, args := p.buildSpecArgs(spec)
 
 	err := p.client.GetContext(
 		p.ctx,
 		&productDTO,
-		`SELECT product_id, name, price, created_at, updated_at FROM product WHERE `+query,
+		`SELECT product_id, name, COALESCE(description, '') AS description, sku, status, version, created_at, updated_at, deleted_at FROM product WHERE `+query,
 		args...,
 	)
 	if err != nil {
@@ -70,20 +110,267 @@ func (p *productRepository) Find(spec model.FindSpec) (*model.Product, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	return &model.Product{
-		ProductID: productDTO.ProductID,
-		Name:      productDTO.Name,
-		Price:     productDTO.Price,
-		CreatedAt: productDTO.CreatedAt,
-		UpdatedAt: productDTO.UpdatedAt,
-	}, nil
+	status, err := model.ParseProductStatus(productDTO.Status)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	translations, err := p.findTranslations(productDTO.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := p.findPrices(productDTO.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	barcodes, err := p.findBarcodes(productDTO.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs, err := p.findCategoryIDs(productDTO.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := p.findVariants(productDTO.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := p.findAttributes(productDTO.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := p.findMedia(productDTO.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	product := &model.Product{
+		ProductID:    productDTO.ProductID,
+		Name:         productDTO.Name,
+		Description:  productDTO.Description,
+		Translations: translations,
+		Prices:       prices,
+		SKU:          productDTO.SKU.String,
+		Status:       status,
+		Barcodes:     barcodes,
+		CategoryIDs:  categoryIDs,
+		Attributes:   attributes,
+		Variants:     variants,
+		Media:        media,
+		Version:      productDTO.Version,
+		CreatedAt:    productDTO.CreatedAt,
+		UpdatedAt:    productDTO.UpdatedAt,
+	}
+	if productDTO.DeletedAt.Valid {
+		product.DeletedAt = &productDTO.DeletedAt.Time
+	}
+	return product, nil
+}
+
+func (p *productRepository) PurgeDeleted(deletedBefore time.Time) (int64, []string, error) {
+	var storageKeys []string
+	err := p.client.SelectContext(
+		p.ctx,
+		&storageKeys,
+		`
+		SELECT m.storage_key
+		FROM product_media m
+			INNER JOIN product p ON p.product_id = m.product_id
+		WHERE p.deleted_at < ? AND m.storage_key IS NOT NULL
+		FOR UPDATE
+		`,
+		deletedBefore,
+	)
+	if err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+
+	// Медиа удаляются каскадно вместе с продуктом
+	result, err := p.client.ExecContext(p.ctx, `DELETE FROM product WHERE deleted_at < ?`, deletedBefore)
+	if err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+	count, err := result.RowsAffected()
+	return count, storageKeys, errors.WithStack(err)
 }
 
-func (p *productRepository) HardDelete(productID uuid.UUID) error {
-	_, err := p.client.ExecContext(p.ctx, `DELETE FROM product WHERE product_id = ?`, productID)
+// storePrices перезаписывает цены продукта, только если они изменились, и записывает изменения в историю цен
+func (p *productRepository) storePrices(
+	productID uuid.UUID,
+	prices []model.Money,
+	changedAt time.Time,
+	effectiveFrom map[string]time.Time,
+) error {
+	currentPrices, err := p.findPrices(productID)
+	if err != nil {
+		return err
+	}
+	history := pricesDiff(currentPrices, prices)
+	if len(history) == 0 {
+		return nil
+	}
+
+	_, err = p.client.ExecContext(p.ctx, `DELETE FROM product_price WHERE product_id = ?`, productID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(prices) != 0 {
+		placeholders := make([]string, 0, len(prices))
+		args := make([]interface{}, 0, len(prices)*3)
+		for _, price := range prices {
+			placeholders = append(placeholders, "(?, ?, ?)")
+			args = append(args, productID, price.Currency, price.Amount)
+		}
+		_, err = p.client.ExecContext(
+			p.ctx,
+			`INSERT INTO product_price (product_id, currency, amount) VALUES `+strings.Join(placeholders, ", "),
+			args...,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	placeholders := make([]string, 0, len(history))
+	args := make([]interface{}, 0, len(history)*4)
+	for _, entry := range history {
+		entryEffectiveFrom, ok := effectiveFrom[entry.currency]
+		if !ok {
+			entryEffectiveFrom = changedAt
+		}
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		args = append(args, productID, entry.currency, entry.amount, entryEffectiveFrom)
+	}
+	_, err = p.client.ExecContext(
+		p.ctx,
+		`INSERT INTO product_price_history (product_id, currency, amount, effective_from) VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
 	return errors.WithStack(err)
 }
 
+type priceHistoryEntry struct {
+	currency string
+	// amount nil означает, что цена в валюте удалена
+	amount *int64
+}
+
+func pricesDiff(currentPrices, prices []model.Money) []priceHistoryEntry {
+	var result []priceHistoryEntry
+	for _, price := range prices {
+		current, ok := model.FindPrice(currentPrices, price.Currency)
+		if !ok || current.Amount != price.Amount {
+			amount := price.Amount
+			result = append(result, priceHistoryEntry{currency: price.Currency, amount: &amount})
+		}
+	}
+	for _, current := range currentPrices {
+		if _, ok := model.FindPrice(prices, current.Currency); !ok {
+			result = append(result, priceHistoryEntry{currency: current.Currency})
+		}
+	}
+	return result
+}
+
+func (p *productRepository) findPrices(productID uuid.UUID) ([]model.Money, error) {
+	var priceDTOs []struct {
+		Currency string `db:"currency"`
+		Amount   int64  `db:"amount"`
+	}
+	err := p.client.SelectContext(
+		p.ctx,
+		&priceDTOs,
+		`SELECT currency, amount FROM product_price WHERE product_id = ? ORDER BY currency`,
+		productID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	prices := make([]model.Money, 0, len(priceDTOs))
+	for _, priceDTO := range priceDTOs {
+		prices = append(prices, model.Money{
+			Amount:   priceDTO.Amount,
+			Currency: priceDTO.Currency,
+		})
+	}
+	return prices, nil
+}
+
+func (p *productRepository) storeBarcodes(productID uuid.UUID, barcodes []string) error {
+	_, err := p.client.ExecContext(p.ctx, `DELETE FROM product_barcode WHERE product_id = ?`, productID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(barcodes) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(barcodes))
+	args := make([]interface{}, 0, len(barcodes)*2)
+	for _, barcode := range barcodes {
+		placeholders = append(placeholders, "(?, ?)")
+		args = append(args, barcode, productID)
+	}
+	_, err = p.client.ExecContext(
+		p.ctx,
+		`INSERT INTO product_barcode (barcode, product_id) VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *productRepository) findBarcodes(productID uuid.UUID) ([]string, error) {
+	var barcodes []string
+	err := p.client.SelectContext(
+		p.ctx,
+		&barcodes,
+		`SELECT barcode FROM product_barcode WHERE product_id = ? ORDER BY barcode`,
+		productID,
+	)
+	return barcodes, errors.WithStack(err)
+}
+
+func (p *productRepository) storeCategories(productID uuid.UUID, categoryIDs []uuid.UUID) error {
+	_, err := p.client.ExecContext(p.ctx, `DELETE FROM product_category WHERE product_id = ?`, productID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(categoryIDs))
+	args := make([]interface{}, 0, len(categoryIDs)*2)
+	for _, categoryID := range categoryIDs {
+		placeholders = append(placeholders, "(?, ?)")
+		args = append(args, productID, categoryID)
+	}
+	_, err = p.client.ExecContext(
+		p.ctx,
+		`INSERT INTO product_category (product_id, category_id) VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *productRepository) findCategoryIDs(productID uuid.UUID) ([]uuid.UUID, error) {
+	var categoryIDs []uuid.UUID
+	err := p.client.SelectContext(
+		p.ctx,
+		&categoryIDs,
+		`SELECT category_id FROM product_category WHERE product_id = ? ORDER BY category_id`,
+		productID,
+	)
+	return categoryIDs, errors.WithStack(err)
+}
+
 func (p *productRepository) buildSpecArgs(spec model.FindSpec) (query string, args []interface{}) {
 	var parts []string
 	if spec.ProductID != nil {
@@ -94,5 +381,16 @@ func (p *productRepository) buildSpecArgs(spec model.FindSpec) (query string, ar
 		parts = append(parts, "name = ?")
 		args = append(args, *spec.Name)
 	}
+	if spec.SKU != nil {
+		parts = append(parts, "(sku = ? OR product_id IN (SELECT product_id FROM product_variant WHERE sku = ?))")
+		args = append(args, *spec.SKU, *spec.SKU)
+	}
+	if spec.Barcode != nil {
+		parts = append(parts, "product_id IN (SELECT product_id FROM product_barcode WHERE barcode = ?)")
+		args = append(args, *spec.Barcode)
+	}
+	if !spec.IncludeDeleted {
+		parts = append(parts, "deleted_at IS NULL")
+	}
 	return strings.Join(parts, " AND "), args
 }
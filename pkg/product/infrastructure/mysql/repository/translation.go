@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/product/domain/model"
+)
+
+// storeTranslations перезаписывает переводы продукта
+func (p *productRepository) storeTranslations(productID uuid.UUID, translations []model.Translation) error {
+	_, err := p.client.ExecContext(p.ctx, `DELETE FROM product_translation WHERE product_id = ?`, productID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(translations) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(translations))
+	args := make([]interface{}, 0, len(translations)*4)
+	for _, translation := range translations {
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		args = append(args, productID, translation.Locale, translation.Name, translation.Description)
+	}
+	_, err = p.client.ExecContext(
+		p.ctx,
+		`INSERT INTO product_translation (product_id, locale, name, description) VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *productRepository) findTranslations(productID uuid.UUID) ([]model.Translation, error) {
+	var translationDTOs []struct {
+		Locale      string `db:"locale"`
+		Name        string `db:"name"`
+		Description string `db:"description"`
+	}
+	err := p.client.SelectContext(
+		p.ctx,
+		&translationDTOs,
+		`SELECT locale, name, description FROM product_translation WHERE product_id = ? ORDER BY locale`,
+		productID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(translationDTOs) == 0 {
+		return nil, nil
+	}
+
+	translations := make([]model.Translation, 0, len(translationDTOs))
+	for _, translationDTO := range translationDTOs {
+		translations = append(translations, model.Translation{
+			Locale:      translationDTO.Locale,
+			Name:        translationDTO.Name,
+			Description: translationDTO.Description,
+		})
+	}
+	return translations, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/product/domain/model"
+)
+
+type variantOptionDTO struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// storeVariants перезаписывает варианты продукта, цены вариантов удаляются каскадно
+func (p *productRepository) storeVariants(productID uuid.UUID, variants []model.Variant) error {
+	_, err := p.client.ExecContext(p.ctx, `DELETE FROM product_variant WHERE product_id = ?`, productID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(variants))
+	args := make([]interface{}, 0, len(variants)*5)
+	var (
+		pricePlaceholders []string
+		priceArgs         []interface{}
+	)
+	for i, variant := range variants {
+		options := make([]variantOptionDTO, 0, len(variant.Options))
+		for _, option := range variant.Options {
+			options = append(options, variantOptionDTO{Name: option.Name, Value: option.Value})
+		}
+		optionsJSON, err := json.Marshal(options)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+		args = append(args, variant.VariantID, productID, i, string(optionsJSON), sql.NullString{String: variant.SKU, Valid: variant.SKU != ""})
+		for _, price := range variant.Prices {
+			pricePlaceholders = append(pricePlaceholders, "(?, ?, ?)")
+			priceArgs = append(priceArgs, variant.VariantID, price.Currency, price.Amount)
+		}
+	}
+	_, err = p.client.ExecContext(
+		p.ctx,
+		`INSERT INTO product_variant (variant_id, product_id, position, options, sku) VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(pricePlaceholders) == 0 {
+		return nil
+	}
+
+	_, err = p.client.ExecContext(
+		p.ctx,
+		`INSERT INTO product_variant_price (variant_id, currency, amount) VALUES `+strings.Join(pricePlaceholders, ", "),
+		priceArgs...,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *productRepository) findVariants(productID uuid.UUID) ([]model.Variant, error) {
+	var variantDTOs []struct {
+		VariantID uuid.UUID      `db:"variant_id"`
+		Options   []byte         `db:"options"`
+		SKU       sql.NullString `db:"sku"`
+	}
+	err := p.client.SelectContext(
+		p.ctx,
+		&variantDTOs,
+		`SELECT variant_id, options, sku FROM product_variant WHERE product_id = ? ORDER BY position`,
+		productID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(variantDTOs) == 0 {
+		return nil, nil
+	}
+
+	var priceDTOs []struct {
+		VariantID uuid.UUID `db:"variant_id"`
+		Currency  string    `db:"currency"`
+		Amount    int64     `db:"amount"`
+	}
+	err = p.client.SelectContext(
+		p.ctx,
+		&priceDTOs,
+		`
+		SELECT vp.variant_id, vp.currency, vp.amount
+		FROM product_variant_price vp
+			INNER JOIN product_variant v ON v.variant_id = vp.variant_id
+		WHERE v.product_id = ?
+		ORDER BY vp.currency
+		`,
+		productID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	prices := make(map[uuid.UUID][]model.Money, len(variantDTOs))
+	for _, priceDTO := range priceDTOs {
+		prices[priceDTO.VariantID] = append(prices[priceDTO.VariantID], model.Money{
+			Amount:   priceDTO.Amount,
+			Currency: priceDTO.Currency,
+		})
+	}
+
+	variants := make([]model.Variant, 0, len(variantDTOs))
+	for _, variantDTO := range variantDTOs {
+		var optionDTOs []variantOptionDTO
+		err = json.Unmarshal(variantDTO.Options, &optionDTOs)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		options := make([]model.VariantOption, 0, len(optionDTOs))
+		for _, option := range optionDTOs {
+			options = append(options, model.VariantOption{Name: option.Name, Value: option.Value})
+		}
+
+		variants = append(variants, model.Variant{
+			VariantID: variantDTO.VariantID,
+			Options:   options,
+			Prices:    prices[variantDTO.VariantID],
+			SKU:       variantDTO.SKU.String,
+		})
+	}
+	return variants, nil
+}
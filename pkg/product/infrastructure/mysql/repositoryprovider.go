@@ -21,3 +21,15 @@ type repositoryProvider struct {
 func (r *repositoryProvider) ProductRepository(ctx context.Context) model.ProductRepository {
 	return repository.NewProductRepository(ctx, r.client)
 }
+
+func (r *repositoryProvider) CategoryRepository(ctx context.Context) model.CategoryRepository {
+	return repository.NewCategoryRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) AttributeDefinitionRepository(ctx context.Context) model.AttributeDefinitionRepository {
+	return repository.NewAttributeDefinitionRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) ScheduledPriceChangeRepository(ctx context.Context) model.ScheduledPriceChangeRepository {
+	return repository.NewScheduledPriceChangeRepository(ctx, r.client)
+}
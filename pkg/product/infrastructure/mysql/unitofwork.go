@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
@@ -34,6 +35,9 @@ type lockableUnitOfWork struct {
 }
 
 func (l *lockableUnitOfWork) Execute(ctx context.Context, lockNames []string, f func(provider service.RepositoryProvider) error) error {
+	if len(lockNames) == 0 {
+		return errors.New("lockable unit of work requires at least one lock")
+	}
 	if len(lockNames) == 1 {
 		return l.uow.ExecuteWithRepositoryProvider(ctx, lockNames[0], time.Minute, f)
 	}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"productservice/pkg/product/application/service"
+)
+
+var ErrInvalidKey = errors.New("invalid media storage key")
+
+// NewLocalMediaStorage хранит файлы в каталоге root, ключ задает путь относительно него.
+// Файлы доступны клиентам по адресу baseURL/<ключ>, раздавать их можно через Handler
+func NewLocalMediaStorage(root, baseURL string) (*LocalMediaStorage, error) {
+	err := os.MkdirAll(root, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalMediaStorage{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+type LocalMediaStorage struct {
+	root    string
+	baseURL string
+}
+
+var _ service.MediaStorage = (*LocalMediaStorage)(nil)
+
+// Put пишет файл во временный рядом и переименовывает, чтобы клиенты не получили недописанный файл
+func (s *LocalMediaStorage) Put(_ context.Context, key, _ string, content io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, content)
+	err = errors.Join(err, file.Chmod(0o644), file.Close())
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(file.Name()))
+	}
+	return nil
+}
+
+func (s *LocalMediaStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *LocalMediaStorage) URL(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return s.baseURL + "/" + strings.Join(segments, "/")
+}
+
+// Handler раздает файлы хранилища, пути запросов совпадают с ключами.
+// Список файлов каталога не отдается, браузеру запрещено угадывать тип и исполнять содержимое файлов
+func (s *LocalMediaStorage) Handler() http.Handler {
+	fileServer := http.FileServer(http.Dir(s.root))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+// path не дает ключу выйти за пределы каталога хранилища
+func (s *LocalMediaStorage) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", fmt.Errorf("%w %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,107 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"productservice/api/server/productinternal"
+	appmodel "productservice/pkg/product/application/model"
+)
+
+var attributeTypes = map[productinternal.AttributeType]string{
+	productinternal.AttributeType_ATTRIBUTE_TYPE_STRING: "string",
+	productinternal.AttributeType_ATTRIBUTE_TYPE_NUMBER: "number",
+	productinternal.AttributeType_ATTRIBUTE_TYPE_BOOL:   "bool",
+	productinternal.AttributeType_ATTRIBUTE_TYPE_ENUM:   "enum",
+}
+
+func (p *productInternalAPI) SetProductAttributes(ctx context.Context, request *productinternal.SetProductAttributesRequest) (*productinternal.SetProductAttributesResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]appmodel.AttributeValue, 0, len(request.Attributes))
+	for _, attribute := range request.Attributes {
+		values = append(values, appmodel.AttributeValue{
+			Code:  attribute.Code,
+			Value: attribute.Value,
+		})
+	}
+	err = p.attributeService.SetProductAttributes(ctx, productID, values)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.SetProductAttributesResponse{}, nil
+}
+
+func (p *productInternalAPI) CreateAttributeDefinition(ctx context.Context, request *productinternal.CreateAttributeDefinitionRequest) (*productinternal.CreateAttributeDefinitionResponse, error) {
+	attributeType, ok := attributeTypes[request.Type]
+	if !ok {
+		return nil, invalidArgument("type", fmt.Sprintf("unknown attribute type %v", request.Type))
+	}
+	err := p.attributeService.CreateAttributeDefinition(ctx, appmodel.AttributeDefinition{
+		Code:       request.Code,
+		Name:       request.Name,
+		Type:       attributeType,
+		EnumValues: request.EnumValues,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.CreateAttributeDefinitionResponse{}, nil
+}
+
+func (p *productInternalAPI) UpdateAttributeDefinition(ctx context.Context, request *productinternal.UpdateAttributeDefinitionRequest) (*productinternal.UpdateAttributeDefinitionResponse, error) {
+	err := p.attributeService.UpdateAttributeDefinition(ctx, request.Code, request.Name, request.EnumValues)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.UpdateAttributeDefinitionResponse{}, nil
+}
+
+func (p *productInternalAPI) DeleteAttributeDefinition(ctx context.Context, request *productinternal.DeleteAttributeDefinitionRequest) (*productinternal.DeleteAttributeDefinitionResponse, error) {
+	err := p.attributeService.DeleteAttributeDefinition(ctx, request.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.DeleteAttributeDefinitionResponse{}, nil
+}
+
+func (p *productInternalAPI) ListAttributeDefinitions(ctx context.Context, _ *productinternal.ListAttributeDefinitionsRequest) (*productinternal.ListAttributeDefinitionsResponse, error) {
+	definitions, err := p.attributeQueryService.ListAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &productinternal.ListAttributeDefinitionsResponse{
+		Definitions: make([]*productinternal.AttributeDefinition, 0, len(definitions)),
+	}
+	for _, definition := range definitions {
+		var attributeType productinternal.AttributeType
+		for protoType, name := range attributeTypes {
+			if name == definition.Type {
+				attributeType = protoType
+			}
+		}
+		response.Definitions = append(response.Definitions, &productinternal.AttributeDefinition{
+			Code:       definition.Code,
+			Name:       definition.Name,
+			Type:       attributeType,
+			EnumValues: definition.EnumValues,
+			CreatedAt:  definition.CreatedAt.Unix(),
+			UpdatedAt:  definition.UpdatedAt.Unix(),
+		})
+	}
+	return response, nil
+}
+
+func toAttributesProto(attributes []appmodel.AttributeValue) []*productinternal.AttributeValue {
+	result := make([]*productinternal.AttributeValue, 0, len(attributes))
+	for _, attribute := range attributes {
+		result = append(result, &productinternal.AttributeValue{
+			Code:  attribute.Code,
+			Value: attribute.Value,
+		})
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"productservice/api/server/productinternal"
+	appmodel "productservice/pkg/product/application/model"
+)
+
+func (p *productInternalAPI) SetProductCategories(ctx context.Context, request *productinternal.SetProductCategoriesRequest) (*productinternal.SetProductCategoriesResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	categoryIDs := make([]uuid.UUID, 0, len(request.CategoryIDs))
+	for i, id := range request.CategoryIDs {
+		categoryID, err := parseUUID(fmt.Sprintf("categoryIDs[%d]", i), id)
+		if err != nil {
+			return nil, err
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+
+	err = p.categoryService.SetProductCategories(ctx, productID, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.SetProductCategoriesResponse{}, nil
+}
+
+func (p *productInternalAPI) CreateCategory(ctx context.Context, request *productinternal.CreateCategoryRequest) (*productinternal.CreateCategoryResponse, error) {
+	parentID, err := parseOptionalUUID("parentID", request.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	categoryID, err := p.categoryService.CreateCategory(ctx, request.Name, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.CreateCategoryResponse{
+		CategoryID: categoryID.String(),
+	}, nil
+}
+
+func (p *productInternalAPI) UpdateCategory(ctx context.Context, request *productinternal.UpdateCategoryRequest) (*productinternal.UpdateCategoryResponse, error) {
+	categoryID, err := parseUUID("categoryID", request.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := parseOptionalUUID("parentID", request.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.categoryService.UpdateCategory(ctx, categoryID, request.Name, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.UpdateCategoryResponse{}, nil
+}
+
+func (p *productInternalAPI) DeleteCategory(ctx context.Context, request *productinternal.DeleteCategoryRequest) (*productinternal.DeleteCategoryResponse, error) {
+	categoryID, err := parseUUID("categoryID", request.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.categoryService.DeleteCategory(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.DeleteCategoryResponse{}, nil
+}
+
+func (p *productInternalAPI) FindCategory(ctx context.Context, request *productinternal.FindCategoryRequest) (*productinternal.FindCategoryResponse, error) {
+	categoryID, err := parseUUID("categoryID", request.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	category, err := p.categoryQueryService.FindCategory(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return &productinternal.FindCategoryResponse{}, nil
+	}
+	return &productinternal.FindCategoryResponse{
+		Category: toCategoryProto(*category),
+	}, nil
+}
+
+func (p *productInternalAPI) ListCategories(ctx context.Context, request *productinternal.ListCategoriesRequest) (*productinternal.ListCategoriesResponse, error) {
+	parentID, err := parseOptionalUUID("parentID", request.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := p.categoryQueryService.ListCategories(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &productinternal.ListCategoriesResponse{
+		Categories: make([]*productinternal.Category, 0, len(categories)),
+	}
+	for _, category := range categories {
+		response.Categories = append(response.Categories, toCategoryProto(category))
+	}
+	return response, nil
+}
+
+func toCategoryProto(category appmodel.Category) *productinternal.Category {
+	result := &productinternal.Category{
+		CategoryID: category.CategoryID.String(),
+		Name:       category.Name,
+		CreatedAt:  category.CreatedAt.Unix(),
+		UpdatedAt:  category.UpdatedAt.Unix(),
+	}
+	if category.ParentID != nil {
+		parentID := category.ParentID.String()
+		result.ParentID = &parentID
+	}
+	return result
+}
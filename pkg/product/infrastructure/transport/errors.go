@@ -0,0 +1,103 @@
+package transport
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	commontransport "productservice/pkg/common/infrastructure/transport"
+	"productservice/pkg/product/application/query"
+	appservice "productservice/pkg/product/application/service"
+	"productservice/pkg/product/domain/model"
+	"productservice/pkg/product/infrastructure/importer"
+)
+
+// ErrorMappings gRPC коды и причины ошибок модуля продуктов
+var ErrorMappings = []commontransport.ErrorMapping{
+	{Target: model.ErrProductNotFound, Code: codes.NotFound, Reason: "PRODUCT_NOT_FOUND"},
+	{Target: model.ErrProductNameAlreadyUsed, Code: codes.AlreadyExists, Reason: "PRODUCT_NAME_ALREADY_USED", Metadata: productNameMetadata},
+	{Target: model.ErrProductVersionMismatch, Code: codes.FailedPrecondition, Reason: "PRODUCT_VERSION_MISMATCH"},
+	{Target: model.ErrSKUAlreadyUsed, Code: codes.AlreadyExists, Reason: "PRODUCT_SKU_ALREADY_USED"},
+	{Target: model.ErrBarcodeAlreadyUsed, Code: codes.AlreadyExists, Reason: "PRODUCT_BARCODE_ALREADY_USED"},
+	{Target: model.ErrInvalidStatusTransition, Code: codes.FailedPrecondition, Reason: "INVALID_PRODUCT_STATUS_TRANSITION"},
+	{Target: model.ErrVariantNotFound, Code: codes.NotFound, Reason: "PRODUCT_VARIANT_NOT_FOUND"},
+	{Target: model.ErrVariantOptionsAlreadyUsed, Code: codes.AlreadyExists, Reason: "PRODUCT_VARIANT_OPTIONS_ALREADY_USED"},
+	{Target: model.ErrMediaNotFound, Code: codes.NotFound, Reason: "PRODUCT_MEDIA_NOT_FOUND"},
+	{Target: model.ErrTooManyMedia, Code: codes.FailedPrecondition, Reason: "TOO_MANY_PRODUCT_MEDIA"},
+	{Target: model.ErrAttributeNotFound, Code: codes.NotFound, Reason: "ATTRIBUTE_NOT_FOUND"},
+	{Target: model.ErrAttributeAlreadyExists, Code: codes.AlreadyExists, Reason: "ATTRIBUTE_ALREADY_EXISTS"},
+	{Target: model.ErrAttributeInUse, Code: codes.FailedPrecondition, Reason: "ATTRIBUTE_IN_USE"},
+	{Target: model.ErrCategoryNotFound, Code: codes.NotFound, Reason: "CATEGORY_NOT_FOUND"},
+	{Target: model.ErrCategoryNotEmpty, Code: codes.FailedPrecondition, Reason: "CATEGORY_NOT_EMPTY"},
+	{Target: model.ErrInvalidCategoryParent, Code: codes.InvalidArgument, Reason: "INVALID_CATEGORY_PARENT"},
+	{Target: model.ErrInvalidTranslation, Code: codes.InvalidArgument, Reason: "INVALID_TRANSLATION"},
+	{Target: model.ErrInvalidLocale, Code: codes.InvalidArgument, Reason: "INVALID_LOCALE"},
+	{Target: model.ErrInvalidDescription, Code: codes.InvalidArgument, Reason: "INVALID_DESCRIPTION"},
+	{Target: model.ErrInvalidName, Code: codes.InvalidArgument, Reason: "INVALID_NAME"},
+	{Target: model.ErrInvalidPrice, Code: codes.InvalidArgument, Reason: "INVALID_PRICE"},
+	{Target: model.ErrInvalidSKU, Code: codes.InvalidArgument, Reason: "INVALID_SKU"},
+	{Target: model.ErrInvalidBarcode, Code: codes.InvalidArgument, Reason: "INVALID_BARCODE"},
+	{Target: model.ErrTooManyBarcodes, Code: codes.InvalidArgument, Reason: "TOO_MANY_BARCODES"},
+	{Target: model.ErrInvalidVariantOptions, Code: codes.InvalidArgument, Reason: "INVALID_VARIANT_OPTIONS"},
+	{Target: model.ErrInvalidMedia, Code: codes.InvalidArgument, Reason: "INVALID_MEDIA"},
+	{Target: model.ErrInvalidMediaOrder, Code: codes.InvalidArgument, Reason: "INVALID_MEDIA_ORDER"},
+	{Target: model.ErrInvalidAttributeDefinition, Code: codes.InvalidArgument, Reason: "INVALID_ATTRIBUTE_DEFINITION"},
+	{Target: model.ErrInvalidAttributeValue, Code: codes.InvalidArgument, Reason: "INVALID_ATTRIBUTE_VALUE"},
+	{Target: model.ErrInvalidProductStatus, Code: codes.InvalidArgument, Reason: "INVALID_PRODUCT_STATUS"},
+	{Target: model.ErrInvalidCurrency, Code: codes.InvalidArgument, Reason: "INVALID_CURRENCY"},
+	{Target: query.ErrInvalidSearchQuery, Code: codes.InvalidArgument, Reason: "INVALID_SEARCH_QUERY"},
+	{Target: query.ErrInvalidCursor, Code: codes.InvalidArgument, Reason: "INVALID_CURSOR"},
+	{Target: query.ErrTooManyAttributeFilters, Code: codes.InvalidArgument, Reason: "TOO_MANY_ATTRIBUTE_FILTERS"},
+	{Target: query.ErrTooManyProductIDs, Code: codes.InvalidArgument, Reason: "TOO_MANY_PRODUCT_IDS"},
+	{Target: appservice.ErrImportKeyMissing, Code: codes.InvalidArgument, Reason: "IMPORT_KEY_MISSING"},
+	{Target: appservice.ErrImportConflict, Code: codes.Aborted, Reason: "IMPORT_CONFLICT"},
+	{Target: importer.ErrInvalidRow, Code: codes.InvalidArgument, Reason: "INVALID_IMPORT_ROW"},
+}
+
+// errorMapper переводит ошибки строк импорта, которые передаются в ответе, а не статусом вызова
+var errorMapper = commontransport.NewErrorMapper(ErrorMappings)
+
+func productNameMetadata(err error) map[string]string {
+	var nameErr model.ProductNameAlreadyUsedError
+	if errors.As(err, &nameErr) {
+		return map[string]string{"name": nameErr.Name}
+	}
+	return nil
+}
+
+func parseUUID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, invalidArgument(field, err.Error())
+	}
+	return id, nil
+}
+
+func parseOptionalUUID(field string, value *string) (*uuid.UUID, error) {
+	if value == nil {
+		return nil, nil
+	}
+	id, err := parseUUID(field, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func invalidArgument(field, description string) error {
+	st, err := status.New(codes.InvalidArgument, "invalid "+field).WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       field,
+				Description: description,
+			},
+		},
+	})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid "+field)
+	}
+	return st.Err()
+}
@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"bufio"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"productservice/api/server/productinternal"
+	"productservice/pkg/product/application/query"
+	"productservice/pkg/product/infrastructure/exporter"
+)
+
+// exportChunkSize размер фрагмента файла в одном сообщении, с запасом меньше лимита сообщения gRPC
+const exportChunkSize = 64 * 1024
+
+var exportFormats = map[productinternal.ExportFormat]exporter.Format{
+	productinternal.ExportFormat_EXPORT_FORMAT_JSONL: exporter.FormatJSONLines,
+	productinternal.ExportFormat_EXPORT_FORMAT_CSV:   exporter.FormatCSV,
+}
+
+func (p *productInternalAPI) ExportProducts(request *productinternal.ExportProductsRequest, stream grpc.ServerStreamingServer[productinternal.ExportProductsResponse]) error {
+	format, ok := exportFormats[request.Format]
+	if !ok {
+		return invalidArgument("format", fmt.Sprintf("unknown export format %v", request.Format))
+	}
+	if request.PageSize < 0 || request.PageSize > query.MaxListLimit {
+		return invalidArgument("pageSize", fmt.Sprintf("must be between 0 and %d", query.MaxListLimit))
+	}
+
+	chunks := bufio.NewWriterSize(exportChunkWriter{stream: stream}, exportChunkSize)
+	_, err := exporter.Export(stream.Context(), p.productSnapshotService, chunks, exporter.Options{
+		Format:   format,
+		Gzip:     request.Gzip,
+		PageSize: int(request.PageSize),
+	}, func(watermark query.SnapshotWatermark) error {
+		message := &productinternal.SnapshotWatermark{OutboxEventID: watermark.OutboxEventID}
+		if !watermark.MaxUpdatedAt.IsZero() {
+			message.MaxUpdatedAt = watermark.MaxUpdatedAt.Unix()
+		}
+		return stream.Send(&productinternal.ExportProductsResponse{
+			Payload: &productinternal.ExportProductsResponse_Watermark{Watermark: message},
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return chunks.Flush()
+}
+
+// exportChunkWriter отправляет каждую запись отдельным сообщением, размер записей ограничивает bufio.Writer
+type exportChunkWriter struct {
+	stream grpc.ServerStreamingServer[productinternal.ExportProductsResponse]
+}
+
+func (w exportChunkWriter) Write(p []byte) (int, error) {
+	// Send может удерживать буфер до отправки, а bufio.Writer переиспользует его
+	chunk := make([]byte, len(p))
+	copy(chunk, p)
+	err := w.stream.Send(&productinternal.ExportProductsResponse{
+		Payload: &productinternal.ExportProductsResponse_Chunk{Chunk: chunk},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
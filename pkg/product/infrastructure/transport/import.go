@@ -0,0 +1,86 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+
+	"productservice/api/server/productinternal"
+	appmodel "productservice/pkg/product/application/model"
+	"productservice/pkg/product/infrastructure/importer"
+)
+
+var importKeys = map[productinternal.ImportKey]appmodel.ImportKey{
+	productinternal.ImportKey_IMPORT_KEY_NAME: appmodel.ImportByName,
+	productinternal.ImportKey_IMPORT_KEY_SKU:  appmodel.ImportBySKU,
+}
+
+func (p *productInternalAPI) ImportProducts(stream grpc.ClientStreamingServer[productinternal.ImportProductsRequest, productinternal.ImportProductsResponse]) error {
+	var (
+		options importer.Options
+		summary importer.Summary
+		rowErrs []*productinternal.ImportRowError
+		row     int
+	)
+	for {
+		request, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch payload := request.Payload.(type) {
+		case *productinternal.ImportProductsRequest_Options:
+			if row > 0 {
+				return invalidArgument("options", "must be sent before product rows")
+			}
+			key, ok := importKeys[payload.Options.Key]
+			if !ok {
+				return invalidArgument("options.key", fmt.Sprintf("unknown import key %v", payload.Options.Key))
+			}
+			options = importer.Options{Key: key, DryRun: payload.Options.DryRun}
+		case *productinternal.ImportProductsRequest_Product:
+			row++
+			result := importer.ImportRow(stream.Context(), p.productService, row, fromImportProductRowProto(payload.Product), options)
+			summary.Add(result)
+			if result.Err != nil {
+				reason, message := errorMapper.ErrorReason(result.Err)
+				rowErrs = append(rowErrs, &productinternal.ImportRowError{
+					Row:     int32(row),
+					Reason:  reason,
+					Message: message,
+				})
+			}
+		default:
+			return invalidArgument("payload", "must be options or product")
+		}
+	}
+
+	return stream.SendAndClose(&productinternal.ImportProductsResponse{
+		Created:   int32(summary.Created),
+		Updated:   int32(summary.Updated),
+		Unchanged: int32(summary.Unchanged),
+		Failed:    int32(summary.Failed),
+		Errors:    rowErrs,
+	})
+}
+
+func fromImportProductRowProto(product *productinternal.ImportProductRow) appmodel.ProductImport {
+	result := appmodel.ProductImport{
+		Name:        product.Name,
+		Description: product.Description,
+		SKU:         product.Sku,
+	}
+	if product.Prices != nil {
+		prices := fromMoneyProto(product.Prices.Prices)
+		result.Prices = &prices
+	}
+	if product.Barcodes != nil {
+		result.Barcodes = &product.Barcodes.Barcodes
+	}
+	return result
+}
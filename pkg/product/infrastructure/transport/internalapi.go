@@ -2,6 +2,8 @@ package transport
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -9,21 +11,46 @@ import (
 	appmodel "productservice/pkg/product/application/model"
 	"productservice/pkg/product/application/query"
 	"productservice/pkg/product/application/service"
+	"productservice/pkg/product/domain/model"
 )
 
 func NewProductInternalAPI(
 	productQueryService query.ProductQueryService,
+	categoryQueryService query.CategoryQueryService,
+	attributeQueryService query.AttributeQueryService,
+	productSearchService query.ProductSearchService,
+	productSnapshotService query.ProductSnapshotService,
 	productService service.ProductService,
+	mediaService service.MediaService,
+	mediaStorage service.MediaStorage,
+	categoryService service.CategoryService,
+	attributeService service.AttributeService,
 ) productinternal.ProductInternalServiceServer {
 	return &productInternalAPI{
-		productQueryService: productQueryService,
-		productService:      productService,
+		productQueryService:    productQueryService,
+		categoryQueryService:   categoryQueryService,
+		attributeQueryService:  attributeQueryService,
+		productSearchService:   productSearchService,
+		productSnapshotService: productSnapshotService,
+		productService:         productService,
+		mediaService:           mediaService,
+		mediaStorage:           mediaStorage,
+		categoryService:        categoryService,
+		attributeService:       attributeService,
 	}
 }
 
 type productInternalAPI struct {
-	productQueryService query.ProductQueryService
-	productService      service.ProductService
+	productQueryService    query.ProductQueryService
+	categoryQueryService   query.CategoryQueryService
+	attributeQueryService  query.AttributeQueryService
+	productSearchService   query.ProductSearchService
+	productSnapshotService query.ProductSnapshotService
+	productService         service.ProductService
+	mediaService           service.MediaService
+	mediaStorage           service.MediaStorage
+	categoryService        service.CategoryService
+	attributeService       service.AttributeService
 
 	productinternal.UnimplementedProductInternalServiceServer
 }
@@ -34,17 +61,38 @@ func (p *productInternalAPI) StoreProduct(ctx context.Context, request *producti
 		err       error
 	)
 	if request.Product.ProductID != "" {
-		productID, err = uuid.Parse(request.Product.ProductID)
+		productID, err = parseUUID("product.productID", request.Product.ProductID)
 		if err != nil {
 			return nil, err
 		}
+	} else if request.ExpectedVersion != nil {
+		return nil, invalidArgument("expectedVersion", "must not be set when creating a product")
 	}
 
-	productID, err = p.productService.StoreProduct(ctx, appmodel.Product{
-		ProductID: productID,
-		Name:      request.Product.Name,
-		Price:     request.Product.Price,
-	})
+	// Поля без признака наличия считаются незаданными, если пусты, иначе клиенты, которые их не знают,
+	// стирали бы их при каждом сохранении
+	product := appmodel.ProductUpdate{
+		Name:        &request.Product.Name,
+		Description: request.Product.Description,
+		SKU:         request.Product.Sku,
+	}
+	if len(request.Product.Translations) != 0 {
+		translations := fromTranslationsProto(request.Product.Translations)
+		product.Translations = &translations
+	}
+	if len(request.Product.Prices) != 0 {
+		prices := fromMoneyProto(request.Product.Prices)
+		product.Prices = &prices
+	} else {
+		// Устаревшее поле price поддерживается до перехода клиентов на prices. Нулевую цену от незаданной
+		// не отличить, поэтому без prices price применяется всегда, иначе продукт остался бы без цены
+		product.DefaultCurrencyPrice = &request.Product.Price
+	}
+	if len(request.Product.Barcodes) != 0 {
+		product.Barcodes = &request.Product.Barcodes
+	}
+
+	productID, err = p.productService.StoreProduct(ctx, productID, product, request.ExpectedVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -55,22 +103,419 @@ func (p *productInternalAPI) StoreProduct(ctx context.Context, request *producti
 }
 
 func (p *productInternalAPI) FindProduct(ctx context.Context, request *productinternal.FindProductRequest) (*productinternal.FindProductResponse, error) {
-	productID, err := uuid.Parse(request.ProductID)
+	productID, err := parseUUID("productID", request.ProductID)
 	if err != nil {
 		return nil, err
 	}
-	product, err := p.productQueryService.FindProduct(ctx, productID)
+	product, err := p.productQueryService.FindProduct(ctx, productID, request.Locale)
 	if err != nil {
 		return nil, err
 	}
 	if product == nil {
 		return &productinternal.FindProductResponse{}, nil
 	}
+	productProto, err := p.toProductProto(*product)
+	if err != nil {
+		return nil, err
+	}
 	return &productinternal.FindProductResponse{
-		Product: &productinternal.Product{
-			ProductID: product.ProductID.String(),
-			Name:      product.Name,
-			Price:     product.Price,
+		Product: productProto,
+	}, nil
+}
+
+func (p *productInternalAPI) UpdateProduct(ctx context.Context, request *productinternal.UpdateProductRequest) (*productinternal.UpdateProductResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	update := appmodel.ProductUpdate{
+		Name:        request.Name,
+		Description: request.Description,
+		SKU:         request.Sku,
+	}
+	if request.Translations != nil {
+		translations := fromTranslationsProto(request.Translations.Translations)
+		update.Translations = &translations
+	}
+	if request.Prices != nil {
+		prices := fromMoneyProto(request.Prices.Prices)
+		update.Prices = &prices
+	}
+	// Устаревшее поле price поддерживается до перехода клиентов на prices
+	update.DefaultCurrencyPrice = request.Price
+	if request.Barcodes != nil {
+		update.Barcodes = &request.Barcodes.Barcodes
+	}
+	err = p.productService.UpdateProduct(ctx, productID, update, request.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.UpdateProductResponse{}, nil
+}
+
+func (p *productInternalAPI) DeleteProduct(ctx context.Context, request *productinternal.DeleteProductRequest) (*productinternal.DeleteProductResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.productService.DeleteProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.DeleteProductResponse{}, nil
+}
+
+func (p *productInternalAPI) RestoreProduct(ctx context.Context, request *productinternal.RestoreProductRequest) (*productinternal.RestoreProductResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.productService.RestoreProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.RestoreProductResponse{}, nil
+}
+
+func (p *productInternalAPI) PublishProduct(ctx context.Context, request *productinternal.PublishProductRequest) (*productinternal.PublishProductResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.productService.PublishProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.PublishProductResponse{}, nil
+}
+
+func (p *productInternalAPI) ArchiveProduct(ctx context.Context, request *productinternal.ArchiveProductRequest) (*productinternal.ArchiveProductResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.productService.ArchiveProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.ArchiveProductResponse{}, nil
+}
+
+func (p *productInternalAPI) FindProductByName(ctx context.Context, request *productinternal.FindProductByNameRequest) (*productinternal.FindProductByNameResponse, error) {
+	product, err := p.productQueryService.FindProductByName(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return &productinternal.FindProductByNameResponse{}, nil
+	}
+	productProto, err := p.toProductProto(*product)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.FindProductByNameResponse{
+		Product: productProto,
+	}, nil
+}
+
+func (p *productInternalAPI) FindProductBySKU(ctx context.Context, request *productinternal.FindProductBySKURequest) (*productinternal.FindProductBySKUResponse, error) {
+	product, err := p.productQueryService.FindProductBySKU(ctx, request.Sku)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return &productinternal.FindProductBySKUResponse{}, nil
+	}
+	productProto, err := p.toProductProto(*product)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.FindProductBySKUResponse{
+		Product: productProto,
+	}, nil
+}
+
+func (p *productInternalAPI) FindProductByBarcode(ctx context.Context, request *productinternal.FindProductByBarcodeRequest) (*productinternal.FindProductByBarcodeResponse, error) {
+	product, err := p.productQueryService.FindProductByBarcode(ctx, request.Barcode)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return &productinternal.FindProductByBarcodeResponse{}, nil
+	}
+	productProto, err := p.toProductProto(*product)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.FindProductByBarcodeResponse{
+		Product: productProto,
+	}, nil
+}
+
+func (p *productInternalAPI) FindProducts(ctx context.Context, request *productinternal.FindProductsRequest) (*productinternal.FindProductsResponse, error) {
+	productIDs := make([]uuid.UUID, 0, len(request.ProductIDs))
+	for i, id := range request.ProductIDs {
+		productID, err := parseUUID(fmt.Sprintf("productIDs[%d]", i), id)
+		if err != nil {
+			return nil, err
+		}
+		productIDs = append(productIDs, productID)
+	}
+
+	products, err := p.productQueryService.FindProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uuid.UUID]struct{}, len(products))
+	response := &productinternal.FindProductsResponse{
+		Products: make([]*productinternal.Product, 0, len(products)),
+	}
+	for _, product := range products {
+		found[product.ProductID] = struct{}{}
+		productProto, err := p.toProductProto(product)
+		if err != nil {
+			return nil, err
+		}
+		response.Products = append(response.Products, productProto)
+	}
+	for _, productID := range productIDs {
+		if _, ok := found[productID]; ok {
+			continue
+		}
+		// Повторяющиеся идентификаторы попадают в ответ один раз
+		found[productID] = struct{}{}
+		response.MissingProductIDs = append(response.MissingProductIDs, productID.String())
+	}
+	return response, nil
+}
+
+func (p *productInternalAPI) SchedulePriceChange(ctx context.Context, request *productinternal.SchedulePriceChangeRequest) (*productinternal.SchedulePriceChangeResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Price == nil {
+		return nil, invalidArgument("price", "must be set")
+	}
+	changeID, err := p.productService.SchedulePriceChange(ctx, productID, appmodel.Money{
+		Amount:   request.Price.Amount,
+		Currency: request.Price.Currency,
+	}, time.Unix(request.EffectiveFrom, 0))
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.SchedulePriceChangeResponse{
+		ChangeID: changeID.String(),
+	}, nil
+}
+
+func (p *productInternalAPI) GetPriceHistory(ctx context.Context, request *productinternal.GetPriceHistoryRequest) (*productinternal.GetPriceHistoryResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := p.productQueryService.GetPriceHistory(ctx, productID, request.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &productinternal.GetPriceHistoryResponse{
+		Entries: make([]*productinternal.PriceHistoryEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, &productinternal.PriceHistoryEntry{
+			Currency:      entry.Currency,
+			Amount:        entry.Amount,
+			EffectiveFrom: entry.EffectiveFrom.Unix(),
+		})
+	}
+	return response, nil
+}
+
+var sortFields = map[productinternal.ProductSortField]query.ProductSortField{
+	productinternal.ProductSortField_SORT_BY_CREATED_AT: query.SortByCreatedAt,
+	productinternal.ProductSortField_SORT_BY_NAME:       query.SortByName,
+	productinternal.ProductSortField_SORT_BY_PRICE:      query.SortByPrice,
+}
+
+var productStatuses = map[productinternal.ProductStatus]string{
+	productinternal.ProductStatus_PRODUCT_STATUS_DRAFT:    "draft",
+	productinternal.ProductStatus_PRODUCT_STATUS_ACTIVE:   "active",
+	productinternal.ProductStatus_PRODUCT_STATUS_ARCHIVED: "archived",
+}
+
+func (p *productInternalAPI) ListProducts(ctx context.Context, request *productinternal.ListProductsRequest) (*productinternal.ListProductsResponse, error) {
+	sortBy, ok := sortFields[request.SortBy]
+	if !ok {
+		return nil, invalidArgument("sortBy", fmt.Sprintf("unknown sort field %v", request.SortBy))
+	}
+	spec := query.ListProductsSpec{
+		Locale:        request.Locale,
+		PriceCurrency: request.PriceCurrency,
+		SortBy:        sortBy,
+		Descending:    request.Descending,
+		Cursor:        request.Cursor,
+		Limit:         int(request.Limit),
+	}
+	if filter := request.Filter; filter != nil {
+		spec.NamePrefix = filter.NamePrefix
+		spec.MinPrice = filter.MinPrice
+		spec.MaxPrice = filter.MaxPrice
+		spec.CreatedFrom = fromUnix(filter.CreatedFrom)
+		spec.CreatedTo = fromUnix(filter.CreatedTo)
+		spec.UpdatedFrom = fromUnix(filter.UpdatedFrom)
+		spec.UpdatedTo = fromUnix(filter.UpdatedTo)
+		categoryID, err := parseOptionalUUID("filter.categoryID", filter.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		spec.CategoryID = categoryID
+		spec.IncludeSubcategories = filter.IncludeSubcategories
+		for _, status := range filter.Statuses {
+			statusName, ok := productStatuses[status]
+			if !ok {
+				return nil, invalidArgument("filter.statuses", fmt.Sprintf("unknown status %v", status))
+			}
+			spec.Statuses = append(spec.Statuses, statusName)
+		}
+		for _, attribute := range filter.Attributes {
+			spec.AttributeFilters = append(spec.AttributeFilters, query.AttributeFilter{
+				Code:      attribute.Code,
+				Value:     attribute.Value,
+				MinNumber: attribute.MinNumber,
+				MaxNumber: attribute.MaxNumber,
+			})
+		}
+	}
+
+	list, err := p.productQueryService.ListProducts(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]*productinternal.Product, 0, len(list.Products))
+	for _, product := range list.Products {
+		productProto, err := p.toProductProto(product)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, productProto)
+	}
+	return &productinternal.ListProductsResponse{
+		Products:   products,
+		NextCursor: list.NextCursor,
+	}, nil
+}
+
+func (p *productInternalAPI) toProductProto(product appmodel.Product) (*productinternal.Product, error) {
+	status, err := toProductStatusProto(product.Status)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.Product{
+		ProductID:    product.ProductID.String(),
+		Name:         product.Name,
+		Description:  &product.Description,
+		Translations: toTranslationsProto(product.Translations),
+		Localized: &productinternal.LocalizedText{
+			Locale:      product.Localized.Locale,
+			Name:        product.Localized.Name,
+			Description: product.Localized.Description,
 		},
+		Price:       defaultCurrencyAmount(product.Prices),
+		Prices:      toMoneyProto(product.Prices),
+		Sku:         &product.SKU,
+		Barcodes:    product.Barcodes,
+		CategoryIDs: toIDStrings(product.CategoryIDs),
+		Variants:    toVariantsProto(product.Variants),
+		Attributes:  toAttributesProto(product.Attributes),
+		Media:       p.toMediaProto(product.Media),
+		Status:      status,
+		Version:     product.Version,
+		CreatedAt:   product.CreatedAt.Unix(),
+		UpdatedAt:   product.UpdatedAt.Unix(),
 	}, nil
 }
+
+// toProductStatusProto не подменяет неизвестный статус черновиком, а возвращает ошибку
+func toProductStatusProto(status string) (productinternal.ProductStatus, error) {
+	for protoStatus, name := range productStatuses {
+		if name == status {
+			return protoStatus, nil
+		}
+	}
+	return productinternal.ProductStatus_PRODUCT_STATUS_UNSPECIFIED, fmt.Errorf("unknown product status %q", status)
+}
+
+func toTranslationsProto(translations []appmodel.Translation) []*productinternal.Translation {
+	result := make([]*productinternal.Translation, 0, len(translations))
+	for _, translation := range translations {
+		result = append(result, &productinternal.Translation{
+			Locale:      translation.Locale,
+			Name:        translation.Name,
+			Description: translation.Description,
+		})
+	}
+	return result
+}
+
+func fromTranslationsProto(translations []*productinternal.Translation) []appmodel.Translation {
+	result := make([]appmodel.Translation, 0, len(translations))
+	for _, translation := range translations {
+		result = append(result, appmodel.Translation{
+			Locale:      translation.Locale,
+			Name:        translation.Name,
+			Description: translation.Description,
+		})
+	}
+	return result
+}
+
+func toIDStrings(ids []uuid.UUID) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, id.String())
+	}
+	return result
+}
+
+func toMoneyProto(prices []appmodel.Money) []*productinternal.Money {
+	result := make([]*productinternal.Money, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, &productinternal.Money{
+			Amount:   price.Amount,
+			Currency: price.Currency,
+		})
+	}
+	return result
+}
+
+// defaultCurrencyAmount цена в валюте по умолчанию для устаревшего поля price, 0 если ее нет
+func defaultCurrencyAmount(prices []appmodel.Money) int64 {
+	for _, price := range prices {
+		if price.Currency == model.DefaultCurrency {
+			return price.Amount
+		}
+	}
+	return 0
+}
+
+func fromMoneyProto(prices []*productinternal.Money) []appmodel.Money {
+	result := make([]appmodel.Money, 0, len(prices))
+	for _, price := range prices {
+		result = append(result, appmodel.Money{
+			Amount:   price.Amount,
+			Currency: price.Currency,
+		})
+	}
+	return result
+}
+
+func fromUnix(timestamp *int64) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+	t := time.Unix(*timestamp, 0)
+	return &t
+}
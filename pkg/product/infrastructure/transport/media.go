@@ -0,0 +1,107 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"productservice/api/server/productinternal"
+	appmodel "productservice/pkg/product/application/model"
+)
+
+func (p *productInternalAPI) AddProductMedia(ctx context.Context, request *productinternal.AddProductMediaRequest) (*productinternal.AddProductMediaResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	mediaID, err := p.mediaService.AddMedia(ctx, productID, appmodel.MediaCreate{
+		URL:         request.Url,
+		Content:     request.Content,
+		AltText:     request.AltText,
+		Width:       int(request.Width),
+		Height:      int(request.Height),
+		ContentType: request.ContentType,
+		Primary:     request.Primary,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.AddProductMediaResponse{
+		MediaID: mediaID.String(),
+	}, nil
+}
+
+func (p *productInternalAPI) ReorderProductMedia(ctx context.Context, request *productinternal.ReorderProductMediaRequest) (*productinternal.ReorderProductMediaResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	mediaIDs := make([]uuid.UUID, 0, len(request.MediaIDs))
+	for i, id := range request.MediaIDs {
+		mediaID, err := parseUUID(fmt.Sprintf("mediaIDs[%d]", i), id)
+		if err != nil {
+			return nil, err
+		}
+		mediaIDs = append(mediaIDs, mediaID)
+	}
+	err = p.mediaService.ReorderMedia(ctx, productID, mediaIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.ReorderProductMediaResponse{}, nil
+}
+
+func (p *productInternalAPI) SetPrimaryProductMedia(ctx context.Context, request *productinternal.SetPrimaryProductMediaRequest) (*productinternal.SetPrimaryProductMediaResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	mediaID, err := parseUUID("mediaID", request.MediaID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.mediaService.SetPrimaryMedia(ctx, productID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.SetPrimaryProductMediaResponse{}, nil
+}
+
+func (p *productInternalAPI) RemoveProductMedia(ctx context.Context, request *productinternal.RemoveProductMediaRequest) (*productinternal.RemoveProductMediaResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	mediaID, err := parseUUID("mediaID", request.MediaID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.mediaService.RemoveMedia(ctx, productID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.RemoveProductMediaResponse{}, nil
+}
+
+// toMediaProto заполняет url загруженных файлов адресом из хранилища медиа
+func (p *productInternalAPI) toMediaProto(media []appmodel.Media) []*productinternal.Media {
+	result := make([]*productinternal.Media, 0, len(media))
+	for _, m := range media {
+		url := m.URL
+		if m.StorageKey != "" {
+			url = p.mediaStorage.URL(m.StorageKey)
+		}
+		result = append(result, &productinternal.Media{
+			MediaID:     m.MediaID.String(),
+			Url:         url,
+			StorageKey:  m.StorageKey,
+			AltText:     m.AltText,
+			Width:       int32(m.Width),
+			Height:      int32(m.Height),
+			ContentType: m.ContentType,
+			Primary:     m.Primary,
+		})
+	}
+	return result
+}
@@ -29,3 +29,22 @@ func NewGRPCLoggingMiddleware(logger logging.Logger) grpc.UnaryServerInterceptor
 		return resp, err
 	}
 }
+
+func NewGRPCStreamLoggingMiddleware(logger logging.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		err := handler(srv, stream)
+
+		l := logger.WithFields(logging.Fields{
+			"duration": time.Since(start).String(),
+			"method":   info.FullMethod,
+		})
+		if err != nil {
+			l.Error(err, "stream failed")
+		} else {
+			l.Info("stream finished")
+		}
+		return err
+	}
+}
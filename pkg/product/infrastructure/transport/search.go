@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"productservice/api/server/productinternal"
+	"productservice/pkg/product/application/query"
+)
+
+func (p *productInternalAPI) SearchProducts(ctx context.Context, request *productinternal.SearchProductsRequest) (*productinternal.SearchProductsResponse, error) {
+	spec := query.SearchProductsSpec{
+		Query:  request.Query,
+		Locale: request.Locale,
+		Cursor: request.Cursor,
+		Limit:  int(request.Limit),
+	}
+	for _, status := range request.Statuses {
+		statusName, ok := productStatuses[status]
+		if !ok {
+			return nil, invalidArgument("statuses", fmt.Sprintf("unknown status %v", status))
+		}
+		spec.Statuses = append(spec.Statuses, statusName)
+	}
+
+	result, err := p.productSearchService.SearchProducts(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*productinternal.SearchHit, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		productProto, err := p.toProductProto(hit.Product)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, &productinternal.SearchHit{
+			Product:    productProto,
+			Relevance:  hit.Relevance,
+			Highlights: toHighlightsProto(hit.Highlights),
+		})
+	}
+	return &productinternal.SearchProductsResponse{
+		Hits:       hits,
+		NextCursor: result.NextCursor,
+	}, nil
+}
+
+func toHighlightsProto(highlights []query.Highlight) []*productinternal.Highlight {
+	result := make([]*productinternal.Highlight, 0, len(highlights))
+	for _, highlight := range highlights {
+		matches := make([]*productinternal.TextRange, 0, len(highlight.Matches))
+		for _, match := range highlight.Matches {
+			matches = append(matches, &productinternal.TextRange{
+				Start: int32(match.Start),
+				End:   int32(match.End),
+			})
+		}
+		result = append(result, &productinternal.Highlight{
+			Field:   highlight.Field,
+			Locale:  highlight.Locale,
+			Text:    highlight.Text,
+			Matches: matches,
+		})
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"context"
+
+	"productservice/api/server/productinternal"
+	appmodel "productservice/pkg/product/application/model"
+)
+
+func (p *productInternalAPI) AddProductVariant(ctx context.Context, request *productinternal.AddProductVariantRequest) (*productinternal.AddProductVariantResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	variantID, err := p.productService.AddVariant(ctx, productID, appmodel.Variant{
+		Options: fromVariantOptionsProto(request.Options),
+		Prices:  fromMoneyProto(request.Prices),
+		SKU:     request.Sku,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.AddProductVariantResponse{
+		VariantID: variantID.String(),
+	}, nil
+}
+
+func (p *productInternalAPI) UpdateProductVariant(ctx context.Context, request *productinternal.UpdateProductVariantRequest) (*productinternal.UpdateProductVariantResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	variantID, err := parseUUID("variantID", request.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	update := appmodel.VariantUpdate{
+		SKU: request.Sku,
+	}
+	if request.Options != nil {
+		options := fromVariantOptionsProto(request.Options.Options)
+		update.Options = &options
+	}
+	if request.Prices != nil {
+		prices := fromMoneyProto(request.Prices.Prices)
+		update.Prices = &prices
+	}
+	err = p.productService.UpdateVariant(ctx, productID, variantID, update)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.UpdateProductVariantResponse{}, nil
+}
+
+func (p *productInternalAPI) RemoveProductVariant(ctx context.Context, request *productinternal.RemoveProductVariantRequest) (*productinternal.RemoveProductVariantResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	variantID, err := parseUUID("variantID", request.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.productService.RemoveVariant(ctx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	return &productinternal.RemoveProductVariantResponse{}, nil
+}
+
+func toVariantsProto(variants []appmodel.Variant) []*productinternal.Variant {
+	result := make([]*productinternal.Variant, 0, len(variants))
+	for _, variant := range variants {
+		options := make([]*productinternal.VariantOption, 0, len(variant.Options))
+		for _, option := range variant.Options {
+			options = append(options, &productinternal.VariantOption{
+				Name:  option.Name,
+				Value: option.Value,
+			})
+		}
+		result = append(result, &productinternal.Variant{
+			VariantID: variant.VariantID.String(),
+			Options:   options,
+			Prices:    toMoneyProto(variant.Prices),
+			Sku:       variant.SKU,
+		})
+	}
+	return result
+}
+
+func fromVariantOptionsProto(options []*productinternal.VariantOption) []appmodel.VariantOption {
+	result := make([]appmodel.VariantOption, 0, len(options))
+	for _, option := range options {
+		result = append(result, appmodel.VariantOption{
+			Name:  option.Name,
+			Value: option.Value,
+		})
+	}
+	return result
+}
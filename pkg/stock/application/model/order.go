@@ -0,0 +1,7 @@
+package model
+
+// Order DTO заказа из событий сервиса заказов
+type Order struct {
+	OrderID string
+	Items   []ReservationItem
+}
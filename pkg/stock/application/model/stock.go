@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductStock DTO, остатки продукта по складам и суммарно по всем складам
+type ProductStock struct {
+	ProductID uuid.UUID
+	// Warehouses отсортированы по складу, склады без записи остатка не попадают
+	Warehouses []StockLevel
+	OnHand     int64
+	Reserved   int64
+	Available  int64
+}
+
+// StockLevel DTO остатка на складе
+type StockLevel struct {
+	WarehouseID string
+	OnHand      int64
+	Reserved    int64
+	Available   int64
+	Version     int64
+	UpdatedAt   time.Time
+}
+
+// Reservation DTO, Status одно из "active", "committed", "released", "expired"
+type Reservation struct {
+	ReservationID uuid.UUID
+	Reference     string
+	Items         []ReservationItem
+	Status        string
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type ReservationItem struct {
+	ProductID   uuid.UUID
+	WarehouseID string
+	Quantity    int64
+}
@@ -0,0 +1,16 @@
+package query
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	appmodel "productservice/pkg/stock/application/model"
+)
+
+type StockQueryService interface {
+	// GetProductStock возвращает остатки продукта, для продукта без остатков - пустой список складов
+	GetProductStock(ctx context.Context, productID uuid.UUID) (appmodel.ProductStock, error)
+	// FindReservation возвращает model.ErrReservationNotFound, если резерва нет
+	FindReservation(ctx context.Context, reservationID uuid.UUID) (*appmodel.Reservation, error)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+
+	appmodel "productservice/pkg/stock/application/model"
+	"productservice/pkg/stock/domain/model"
+)
+
+// OrderService применяет к остаткам события заказов. Резерв заказа создается с Reference, равным идентификатору заказа.
+// Методы сначала берут блокировки и только потом читают данные блокирующим чтением, поэтому их можно вызывать
+// внутри уже начатой транзакции, например транзакции inbox, которая фиксируется уже после снятия блокировок
+type OrderService interface {
+	// ReserveOrder резервирует позиции заказа. Если у заказа уже есть резерв, ничего не делает
+	ReserveOrder(ctx context.Context, order appmodel.Order) error
+	// ReleaseOrder снимает резервы заказа, уже снятые и истекшие резервы пропускаются
+	ReleaseOrder(ctx context.Context, orderID string) error
+}
+
+func NewOrderService(
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	reservationTTL time.Duration,
+) OrderService {
+	return &orderService{
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+		reservationTTL:  reservationTTL,
+	}
+}
+
+type orderService struct {
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	reservationTTL  time.Duration
+}
+
+func (s *orderService) ReserveOrder(ctx context.Context, order appmodel.Order) error {
+	orderID := model.NormalizeReference(order.OrderID)
+	items := make([]model.ReservationItem, 0, len(order.Items))
+	keys := make([]model.StockKey, 0, len(order.Items))
+	for _, item := range order.Items {
+		domainItem := model.ReservationItem{
+			ProductID:   item.ProductID,
+			WarehouseID: item.WarehouseID,
+			Quantity:    item.Quantity,
+		}
+		items = append(items, domainItem)
+		keys = append(keys, domainItem.Key())
+	}
+
+	lockNames := append([]string{orderLock(orderID)}, stockLocks(keys...)...)
+	return s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		reservationIDs, err := provider.ReservationRepository(ctx).FindByReference(orderID)
+		if err != nil || len(reservationIDs) != 0 {
+			return err
+		}
+
+		_, err = newDomainStockService(ctx, provider, s.eventDispatcher).Reserve(orderID, items, s.reservationTTL)
+		return err
+	})
+}
+
+// ReleaseOrder блокирует только заказ: резервы заказа и их остатки заранее неизвестны,
+// а от параллельных изменений их защищают блокировки строк, которые берут репозитории
+func (s *orderService) ReleaseOrder(ctx context.Context, orderID string) error {
+	orderID = model.NormalizeReference(orderID)
+	return s.luow.Execute(ctx, []string{orderLock(orderID)}, func(provider RepositoryProvider) error {
+		reservationIDs, err := provider.ReservationRepository(ctx).FindByReference(orderID)
+		if err != nil {
+			return err
+		}
+
+		domainService := newDomainStockService(ctx, provider, s.eventDispatcher)
+		for _, reservationID := range reservationIDs {
+			err = domainService.Release(reservationID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// orderLock не дает одновременно обрабатывать события одного заказа
+func orderLock(orderID string) string {
+	return baseStockLock + "order_" + orderID
+}
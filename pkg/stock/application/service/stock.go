@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	appmodel "productservice/pkg/stock/application/model"
+	"productservice/pkg/stock/domain/model"
+	"productservice/pkg/stock/domain/service"
+)
+
+type StockService interface {
+	SetStock(ctx context.Context, productID uuid.UUID, warehouseID string, onHand int64) error
+	AdjustStock(ctx context.Context, productID uuid.UUID, warehouseID string, delta int64) error
+	// ReserveStock резервирует все позиции или ни одной, нулевой ttl означает model.DefaultReservationTTL
+	ReserveStock(ctx context.Context, reference string, items []appmodel.ReservationItem, ttl time.Duration) (uuid.UUID, error)
+	CommitReservation(ctx context.Context, reservationID uuid.UUID) error
+	ReleaseReservation(ctx context.Context, reservationID uuid.UUID) error
+	// ExpireReservations снимает истекшие резервы, возвращает число снятых.
+	// Ошибка одного резерва не мешает обработке остальных
+	ExpireReservations(ctx context.Context) (int, error)
+}
+
+// expireBatchSize ограничивает число резервов, снимаемых за один вызов ExpireReservations
+const expireBatchSize = 100
+
+func NewStockService(
+	uow UnitOfWork,
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+) StockService {
+	return &stockService{
+		uow:             uow,
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+type stockService struct {
+	uow             UnitOfWork
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+}
+
+func (s *stockService) SetStock(ctx context.Context, productID uuid.UUID, warehouseID string, onHand int64) error {
+	key := model.StockKey{ProductID: productID, WarehouseID: warehouseID}
+	return s.luow.Execute(ctx, stockLocks(key), func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).SetStock(key, onHand)
+	})
+}
+
+func (s *stockService) AdjustStock(ctx context.Context, productID uuid.UUID, warehouseID string, delta int64) error {
+	key := model.StockKey{ProductID: productID, WarehouseID: warehouseID}
+	return s.luow.Execute(ctx, stockLocks(key), func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).AdjustStock(key, delta)
+	})
+}
+
+func (s *stockService) ReserveStock(ctx context.Context, reference string, items []appmodel.ReservationItem, ttl time.Duration) (uuid.UUID, error) {
+	// Без позиций нечего блокировать, поэтому пустой резерв отклоняется до блокировок
+	if len(items) == 0 {
+		return uuid.Nil, fmt.Errorf("%w: must have at least one item", model.ErrInvalidReservation)
+	}
+	if ttl == 0 {
+		ttl = model.DefaultReservationTTL
+	}
+	domainItems := make([]model.ReservationItem, 0, len(items))
+	keys := make([]model.StockKey, 0, len(items))
+	for _, item := range items {
+		domainItem := model.ReservationItem{
+			ProductID:   item.ProductID,
+			WarehouseID: item.WarehouseID,
+			Quantity:    item.Quantity,
+		}
+		domainItems = append(domainItems, domainItem)
+		keys = append(keys, domainItem.Key())
+	}
+
+	var reservationID uuid.UUID
+	err := s.luow.Execute(ctx, stockLocks(keys...), func(provider RepositoryProvider) error {
+		var err error
+		reservationID, err = s.domainService(ctx, provider).Reserve(reference, domainItems, ttl)
+		return err
+	})
+	return reservationID, err
+}
+
+func (s *stockService) CommitReservation(ctx context.Context, reservationID uuid.UUID) error {
+	return s.executeReservation(ctx, reservationID, func(domainService service.StockService) error {
+		return domainService.Commit(reservationID)
+	})
+}
+
+func (s *stockService) ReleaseReservation(ctx context.Context, reservationID uuid.UUID) error {
+	return s.executeReservation(ctx, reservationID, func(domainService service.StockService) error {
+		return domainService.Release(reservationID)
+	})
+}
+
+func (s *stockService) ExpireReservations(ctx context.Context) (int, error) {
+	currentTime := time.Now()
+	var reservationIDs []uuid.UUID
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		reservationIDs, err = provider.ReservationRepository(ctx).FindExpired(currentTime, expireBatchSize)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+	for _, reservationID := range reservationIDs {
+		err = s.executeReservation(ctx, reservationID, func(domainService service.StockService) error {
+			return domainService.Expire(reservationID, currentTime)
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		expired++
+	}
+	return expired, errors.Join(errs...)
+}
+
+// executeReservation блокирует резерв и остатки его позиций. Позиции резерва не меняются,
+// поэтому их можно прочитать до блокировки
+func (s *stockService) executeReservation(ctx context.Context, reservationID uuid.UUID, f func(domainService service.StockService) error) error {
+	var keys []model.StockKey
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		reservation, err := provider.ReservationRepository(ctx).Find(reservationID)
+		if err != nil {
+			return err
+		}
+		for _, item := range reservation.Items {
+			keys = append(keys, item.Key())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	lockNames := append([]string{reservationLock(reservationID)}, stockLocks(keys...)...)
+	return s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		return f(s.domainService(ctx, provider))
+	})
+}
+
+func (s *stockService) domainService(ctx context.Context, provider RepositoryProvider) service.StockService {
+	return newDomainStockService(ctx, provider, s.eventDispatcher)
+}
+
+func newDomainStockService(
+	ctx context.Context,
+	provider RepositoryProvider,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+) service.StockService {
+	return service.NewStockService(
+		provider.StockRepository(ctx),
+		provider.ReservationRepository(ctx),
+		&domainEventDispatcher{
+			ctx:             ctx,
+			eventDispatcher: eventDispatcher,
+		},
+	)
+}
+
+const baseStockLock = "stock_"
+
+// stockLocks блокирует остатки в одном порядке, чтобы параллельные резервы не ждали друг друга по кругу
+func stockLocks(keys ...model.StockKey) []string {
+	normalized := make([]model.StockKey, 0, len(keys))
+	for _, key := range keys {
+		key.WarehouseID = model.NormalizeWarehouseID(key.WarehouseID)
+		normalized = append(normalized, key)
+	}
+	slices.SortFunc(normalized, model.CompareStockKeys)
+	normalized = slices.Compact(normalized)
+
+	lockNames := make([]string, 0, len(normalized))
+	for _, key := range normalized {
+		lockNames = append(lockNames, baseStockLock+key.ProductID.String()+"_"+key.WarehouseID)
+	}
+	return lockNames
+}
+
+func reservationLock(reservationID uuid.UUID) string {
+	return baseStockLock + "reservation_" + reservationID.String()
+}
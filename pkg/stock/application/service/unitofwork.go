@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"productservice/pkg/stock/domain/model"
+)
+
+type RepositoryProvider interface {
+	StockRepository(ctx context.Context) model.StockRepository
+	ReservationRepository(ctx context.Context) model.ReservationRepository
+}
+
+type LockableUnitOfWork interface {
+	Execute(ctx context.Context, lockNames []string, f func(provider RepositoryProvider) error) error
+}
+type UnitOfWork interface {
+	Execute(ctx context.Context, f func(provider RepositoryProvider) error) error
+}
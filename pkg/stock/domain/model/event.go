@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockChangeReason причина изменения остатка
+type StockChangeReason string
+
+const (
+	StockSet       StockChangeReason = "set"
+	StockAdjusted  StockChangeReason = "adjusted"
+	StockReserved  StockChangeReason = "reserved"
+	StockReleased  StockChangeReason = "released"
+	StockCommitted StockChangeReason = "committed"
+	StockExpired   StockChangeReason = "expired"
+)
+
+// StockChanged событие об изменении остатка, содержит остаток после изменения.
+// ReservationID задан для изменений резервами
+type StockChanged struct {
+	ProductID     uuid.UUID
+	WarehouseID   string
+	OnHand        int64
+	Reserved      int64
+	Reason        StockChangeReason
+	ReservationID *uuid.UUID
+	Version       int64
+	ChangedAt     time.Time
+}
+
+func (e StockChanged) Type() string {
+	return "stock_changed"
+}
+
+// StockDepleted событие о том, что доступный остаток продукта на складе закончился.
+// Отправляется после StockChanged при переходе доступного количества в ноль
+type StockDepleted struct {
+	ProductID   uuid.UUID
+	WarehouseID string
+	Version     int64
+	DepletedAt  time.Time
+}
+
+func (e StockDepleted) Type() string {
+	return "stock_depleted"
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReservationNotFound  = errors.New("stock reservation not found")
+	ErrReservationNotActive = errors.New("stock reservation is not active")
+	ErrInvalidReservation   = errors.New("invalid stock reservation")
+)
+
+const (
+	// MaxReservationItems ограничивает число позиций одного резерва
+	MaxReservationItems   = 100
+	MaxReferenceLength    = 128
+	MinReservationTTL     = time.Second
+	MaxReservationTTL     = 7 * 24 * time.Hour
+	DefaultReservationTTL = 15 * time.Minute
+)
+
+type ReservationStatus int
+
+const (
+	ReservationActive ReservationStatus = iota
+	ReservationCommitted
+	ReservationReleased
+	// ReservationExpired резерв снят по истечении срока
+	ReservationExpired
+)
+
+var reservationStatusNames = map[ReservationStatus]string{
+	ReservationActive:    "active",
+	ReservationCommitted: "committed",
+	ReservationReleased:  "released",
+	ReservationExpired:   "expired",
+}
+
+func (s ReservationStatus) String() string {
+	return reservationStatusNames[s]
+}
+
+// ParseReservationStatus разбирает статус резерва по имени
+func ParseReservationStatus(name string) (ReservationStatus, error) {
+	for status, statusName := range reservationStatusNames {
+		if statusName == name {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown reservation status %q", name)
+}
+
+// Reservation резерв остатков, например под заказ. Позиции резервируются, снимаются и списываются вместе
+type Reservation struct {
+	ReservationID uuid.UUID
+	// Reference внешний идентификатор, например заказа, пустой, если не задан
+	Reference string
+	// Items позиции резерва, не больше одной на продукт и склад, отсортированы по продукту и складу
+	Items     []ReservationItem
+	Status    ReservationStatus
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ReservationItem struct {
+	ProductID   uuid.UUID
+	WarehouseID string
+	Quantity    int64
+}
+
+// Key возвращает остаток, из которого резервируется позиция
+func (i ReservationItem) Key() StockKey {
+	return StockKey{ProductID: i.ProductID, WarehouseID: i.WarehouseID}
+}
+
+type ReservationRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(reservation Reservation) error
+	Find(reservationID uuid.UUID) (*Reservation, error)
+	// FindByReference возвращает резервы с заданным внешним идентификатором в порядке создания
+	FindByReference(reference string) ([]uuid.UUID, error)
+	// FindExpired возвращает активные резервы с ExpiresAt не позже at, упорядоченные по ExpiresAt
+	FindExpired(at time.Time, limit int) ([]uuid.UUID, error)
+}
+
+// NormalizeReservationItems нормализует склады, складывает количества повторяющихся позиций и сортирует позиции
+func NormalizeReservationItems(items []ReservationItem) []ReservationItem {
+	result := make([]ReservationItem, 0, len(items))
+	for _, item := range items {
+		item.WarehouseID = NormalizeWarehouseID(item.WarehouseID)
+		i := slices.IndexFunc(result, func(r ReservationItem) bool {
+			return r.Key() == item.Key()
+		})
+		if i == -1 {
+			result = append(result, item)
+			continue
+		}
+		result[i].Quantity += item.Quantity
+	}
+	slices.SortFunc(result, func(l, r ReservationItem) int {
+		return CompareStockKeys(l.Key(), r.Key())
+	})
+	return result
+}
+
+// ValidateReservationItems проверяет уже нормализованные позиции резерва
+func ValidateReservationItems(items []ReservationItem) error {
+	if len(items) == 0 || len(items) > MaxReservationItems {
+		return fmt.Errorf("%w: must have 1 to %d items", ErrInvalidReservation, MaxReservationItems)
+	}
+	var errs []error
+	for _, item := range items {
+		err := ValidateWarehouseID(item.WarehouseID)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if item.Quantity <= 0 || item.Quantity > MaxQuantity {
+			errs = append(errs, fmt.Errorf("%w: item quantity must be between 1 and %d", ErrInvalidQuantity, MaxQuantity))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NormalizeReference убирает пробелы по краям
+func NormalizeReference(reference string) string {
+	return strings.TrimSpace(reference)
+}
+
+func ValidateReference(reference string) error {
+	if utf8.RuneCountInString(reference) > MaxReferenceLength {
+		return fmt.Errorf("%w: reference must be at most %d characters", ErrInvalidReservation, MaxReferenceLength)
+	}
+	return nil
+}
+
+// ValidateReservationTTL проверяет срок резерва
+func ValidateReservationTTL(ttl time.Duration) error {
+	if ttl < MinReservationTTL || ttl > MaxReservationTTL {
+		return fmt.Errorf("%w: ttl must be between %s and %s", ErrInvalidReservation, MinReservationTTL, MaxReservationTTL)
+	}
+	return nil
+}
+
+// CompareStockKeys задает порядок остатков, в котором они блокируются и перечисляются
+func CompareStockKeys(l, r StockKey) int {
+	if c := strings.Compare(l.ProductID.String(), r.ProductID.String()); c != 0 {
+		return c
+	}
+	return strings.Compare(l.WarehouseID, r.WarehouseID)
+}
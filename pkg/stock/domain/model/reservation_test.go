@@ -0,0 +1,47 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"productservice/pkg/stock/domain/model"
+)
+
+func TestNormalizeReservationItems(t *testing.T) {
+	productID := uuid.New()
+	items := model.NormalizeReservationItems([]model.ReservationItem{
+		{ProductID: productID, WarehouseID: "spb", Quantity: 1},
+		{ProductID: productID, WarehouseID: " MSK", Quantity: 2},
+		{ProductID: productID, WarehouseID: "msk", Quantity: 3},
+	})
+	assert.Equal(t, []model.ReservationItem{
+		{ProductID: productID, WarehouseID: "msk", Quantity: 5},
+		{ProductID: productID, WarehouseID: "spb", Quantity: 1},
+	}, items)
+}
+
+func TestValidateReservationItems(t *testing.T) {
+	productID := uuid.New()
+	testCases := []struct {
+		name  string
+		items []model.ReservationItem
+		err   error
+	}{
+		{name: "valid", items: []model.ReservationItem{{ProductID: productID, WarehouseID: "msk_1", Quantity: 1}}},
+		{name: "empty", items: nil, err: model.ErrInvalidReservation},
+		{name: "zero quantity", items: []model.ReservationItem{{ProductID: productID, WarehouseID: "msk", Quantity: 0}}, err: model.ErrInvalidQuantity},
+		{name: "invalid warehouse", items: []model.ReservationItem{{ProductID: productID, WarehouseID: "склад", Quantity: 1}}, err: model.ErrInvalidWarehouseID},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := model.ValidateReservationItems(tc.items)
+			if tc.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
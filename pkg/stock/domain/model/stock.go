@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProductNotFound    = errors.New("product not found")
+	ErrInvalidWarehouseID = errors.New("invalid warehouse id")
+	ErrInvalidQuantity    = errors.New("invalid stock quantity")
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	// ErrStockBelowReserved остаток не может стать меньше зарезервированного количества
+	ErrStockBelowReserved = errors.New("stock below reserved quantity")
+)
+
+const (
+	MaxWarehouseIDLength = 64
+	// MaxQuantity ограничивает остаток и количество в одной позиции резерва
+	MaxQuantity = 1_000_000_000
+)
+
+// Stock остаток продукта на складе
+type Stock struct {
+	ProductID   uuid.UUID
+	WarehouseID string
+	// OnHand физически находящееся на складе количество, включая зарезервированное
+	OnHand int64
+	// Reserved сумма активных резервов продукта на складе, не больше OnHand
+	Reserved int64
+	// Version увеличивается при каждом изменении остатка
+	Version   int64
+	UpdatedAt time.Time
+}
+
+// Available количество, которое еще можно зарезервировать
+func (s Stock) Available() int64 {
+	return s.OnHand - s.Reserved
+}
+
+// StockKey идентифицирует остаток продукта на складе
+type StockKey struct {
+	ProductID   uuid.UUID
+	WarehouseID string
+}
+
+type StockRepository interface {
+	Store(stock Stock) error
+	// Find возвращает остаток, для продукта без остатка на складе возвращает остаток с заданным ключом,
+	// нулевыми количествами и Version 0
+	Find(key StockKey) (*Stock, error)
+	// ProductExists проверяет, что продукт существует и не удален
+	ProductExists(productID uuid.UUID) (bool, error)
+}
+
+// NormalizeWarehouseID убирает пробелы по краям и приводит идентификатор склада к нижнему регистру
+func NormalizeWarehouseID(warehouseID string) string {
+	return strings.ToLower(strings.TrimSpace(warehouseID))
+}
+
+// ValidateWarehouseID проверяет уже нормализованный идентификатор склада
+func ValidateWarehouseID(warehouseID string) error {
+	if warehouseID == "" || len(warehouseID) > MaxWarehouseIDLength {
+		return fmt.Errorf("%w: must be 1 to %d characters", ErrInvalidWarehouseID, MaxWarehouseIDLength)
+	}
+	for _, r := range warehouseID {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' && r != '-' {
+			return fmt.Errorf("%w: must contain only latin letters, digits, '_' and '-'", ErrInvalidWarehouseID)
+		}
+	}
+	return nil
+}
+
+// ValidateOnHand проверяет новое значение остатка
+func ValidateOnHand(onHand int64) error {
+	if onHand < 0 || onHand > MaxQuantity {
+		return fmt.Errorf("%w: must be between 0 and %d", ErrInvalidQuantity, MaxQuantity)
+	}
+	return nil
+}
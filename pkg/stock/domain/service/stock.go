@@ -0,0 +1,302 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"productservice/pkg/common/domain"
+	"productservice/pkg/stock/domain/model"
+)
+
+type StockService interface {
+	// SetStock задает остаток по результатам пересчета склада
+	SetStock(key model.StockKey, onHand int64) error
+	// AdjustStock меняет остаток на delta, например при поступлении или списании товара
+	AdjustStock(key model.StockKey, delta int64) error
+	// Reserve резервирует все позиции или ни одной, резерв снимается через ttl, если его не списали
+	Reserve(reference string, items []model.ReservationItem, ttl time.Duration) (uuid.UUID, error)
+	// Commit списывает зарезервированное количество с остатка. Повторное списание ничего не меняет
+	Commit(reservationID uuid.UUID) error
+	// Release снимает резерв. Повторное снятие и снятие истекшего резерва ничего не меняют
+	Release(reservationID uuid.UUID) error
+	// Expire снимает активный резерв с ExpiresAt не позже at, остальные резервы не меняет
+	Expire(reservationID uuid.UUID, at time.Time) error
+}
+
+func NewStockService(
+	stockRepository model.StockRepository,
+	reservationRepository model.ReservationRepository,
+	eventDispatcher domain.EventDispatcher,
+) StockService {
+	return &stockService{
+		stockRepository:       stockRepository,
+		reservationRepository: reservationRepository,
+		eventDispatcher:       eventDispatcher,
+	}
+}
+
+type stockService struct {
+	stockRepository       model.StockRepository
+	reservationRepository model.ReservationRepository
+	eventDispatcher       domain.EventDispatcher
+}
+
+func (s *stockService) SetStock(key model.StockKey, onHand int64) error {
+	key.WarehouseID = model.NormalizeWarehouseID(key.WarehouseID)
+	err := errors.Join(model.ValidateWarehouseID(key.WarehouseID), model.ValidateOnHand(onHand))
+	if err != nil {
+		return err
+	}
+
+	stock, err := s.findStockForUpdate(key)
+	if err != nil {
+		return err
+	}
+	if stock.Version > 0 && stock.OnHand == onHand {
+		return nil
+	}
+	if onHand < stock.Reserved {
+		return fmt.Errorf("%w: %d reserved", model.ErrStockBelowReserved, stock.Reserved)
+	}
+
+	return s.changeStock(stock, model.StockSet, nil, time.Now(), func(stock *model.Stock) {
+		stock.OnHand = onHand
+	})
+}
+
+func (s *stockService) AdjustStock(key model.StockKey, delta int64) error {
+	key.WarehouseID = model.NormalizeWarehouseID(key.WarehouseID)
+	err := model.ValidateWarehouseID(key.WarehouseID)
+	if err != nil {
+		return err
+	}
+	if delta == 0 {
+		return fmt.Errorf("%w: delta must not be zero", model.ErrInvalidQuantity)
+	}
+
+	stock, err := s.findStockForUpdate(key)
+	if err != nil {
+		return err
+	}
+	onHand := stock.OnHand + delta
+	err = model.ValidateOnHand(onHand)
+	if err != nil {
+		return err
+	}
+	if onHand < stock.Reserved {
+		return fmt.Errorf("%w: %d reserved", model.ErrStockBelowReserved, stock.Reserved)
+	}
+
+	return s.changeStock(stock, model.StockAdjusted, nil, time.Now(), func(stock *model.Stock) {
+		stock.OnHand = onHand
+	})
+}
+
+func (s *stockService) Reserve(reference string, items []model.ReservationItem, ttl time.Duration) (uuid.UUID, error) {
+	reference = model.NormalizeReference(reference)
+	items = model.NormalizeReservationItems(items)
+	err := errors.Join(
+		model.ValidateReference(reference),
+		model.ValidateReservationItems(items),
+		model.ValidateReservationTTL(ttl),
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	stocks := make([]*model.Stock, 0, len(items))
+	var errs []error
+	for _, item := range items {
+		stock, err := s.stockRepository.Find(item.Key())
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if stock.Available() < item.Quantity {
+			errs = append(errs, fmt.Errorf(
+				"%w: product %s in warehouse %q: available %d, requested %d",
+				model.ErrInsufficientStock, item.ProductID, item.WarehouseID, stock.Available(), item.Quantity,
+			))
+		}
+		stocks = append(stocks, stock)
+	}
+	err = errors.Join(errs...)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	reservationID, err := s.reservationRepository.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	currentTime := time.Now()
+	err = s.reservationRepository.Store(model.Reservation{
+		ReservationID: reservationID,
+		Reference:     reference,
+		Items:         items,
+		Status:        model.ReservationActive,
+		ExpiresAt:     currentTime.Add(ttl),
+		CreatedAt:     currentTime,
+		UpdatedAt:     currentTime,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	for i, item := range items {
+		err = s.changeStock(stocks[i], model.StockReserved, &reservationID, currentTime, func(stock *model.Stock) {
+			stock.Reserved += item.Quantity
+		})
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+	return reservationID, nil
+}
+
+func (s *stockService) Commit(reservationID uuid.UUID) error {
+	reservation, err := s.reservationRepository.Find(reservationID)
+	if err != nil {
+		return err
+	}
+	currentTime := time.Now()
+	switch {
+	case reservation.Status == model.ReservationCommitted:
+		return nil
+	case reservation.Status != model.ReservationActive:
+		return fmt.Errorf("%w: reservation is %s", model.ErrReservationNotActive, reservation.Status)
+	case !reservation.ExpiresAt.After(currentTime):
+		// Истекший резерв снимет ExpireReservations, списывать его уже нельзя
+		return fmt.Errorf("%w: reservation expired at %s", model.ErrReservationNotActive, reservation.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return s.closeReservation(reservation, model.ReservationCommitted, model.StockCommitted, currentTime, func(stock *model.Stock, item model.ReservationItem) {
+		stock.OnHand -= item.Quantity
+		stock.Reserved -= item.Quantity
+	})
+}
+
+func (s *stockService) Release(reservationID uuid.UUID) error {
+	reservation, err := s.reservationRepository.Find(reservationID)
+	if err != nil {
+		return err
+	}
+	switch reservation.Status {
+	case model.ReservationReleased, model.ReservationExpired:
+		return nil
+	case model.ReservationCommitted:
+		return fmt.Errorf("%w: reservation is %s", model.ErrReservationNotActive, reservation.Status)
+	}
+
+	return s.closeReservation(reservation, model.ReservationReleased, model.StockReleased, time.Now(), func(stock *model.Stock, item model.ReservationItem) {
+		stock.Reserved -= item.Quantity
+	})
+}
+
+func (s *stockService) Expire(reservationID uuid.UUID, at time.Time) error {
+	reservation, err := s.reservationRepository.Find(reservationID)
+	if err != nil {
+		return err
+	}
+	if reservation.Status != model.ReservationActive || reservation.ExpiresAt.After(at) {
+		return nil
+	}
+
+	return s.closeReservation(reservation, model.ReservationExpired, model.StockExpired, time.Now(), func(stock *model.Stock, item model.ReservationItem) {
+		stock.Reserved -= item.Quantity
+	})
+}
+
+// closeReservation переводит активный резерв в status и применяет change к остатку каждой позиции
+func (s *stockService) closeReservation(
+	reservation *model.Reservation,
+	status model.ReservationStatus,
+	reason model.StockChangeReason,
+	currentTime time.Time,
+	change func(stock *model.Stock, item model.ReservationItem),
+) error {
+	reservation.Status = status
+	reservation.UpdatedAt = currentTime
+	err := s.reservationRepository.Store(*reservation)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range reservation.Items {
+		stock, err := s.stockRepository.Find(item.Key())
+		if err != nil {
+			return err
+		}
+		if stock.Version == 0 {
+			// Остаток удален вместе с продуктом, снимать резерв не с чего
+			continue
+		}
+		err = s.changeStock(stock, reason, &reservation.ReservationID, currentTime, func(stock *model.Stock) {
+			change(stock, item)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findStockForUpdate возвращает остаток, для нового остатка проверяет, что продукт существует
+func (s *stockService) findStockForUpdate(key model.StockKey) (*model.Stock, error) {
+	stock, err := s.stockRepository.Find(key)
+	if err != nil {
+		return nil, err
+	}
+	if stock.Version > 0 {
+		return stock, nil
+	}
+	exists, err := s.stockRepository.ProductExists(key.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", model.ErrProductNotFound, key.ProductID)
+	}
+	return stock, nil
+}
+
+// changeStock применяет change, сохраняет остаток и отправляет StockChanged,
+// а если доступное количество закончилось, то и StockDepleted
+func (s *stockService) changeStock(
+	stock *model.Stock,
+	reason model.StockChangeReason,
+	reservationID *uuid.UUID,
+	currentTime time.Time,
+	change func(stock *model.Stock),
+) error {
+	wasAvailable := stock.Available() > 0
+	change(stock)
+	stock.Version++
+	stock.UpdatedAt = currentTime
+	err := s.stockRepository.Store(*stock)
+	if err != nil {
+		return err
+	}
+
+	err = s.eventDispatcher.Dispatch(&model.StockChanged{
+		ProductID:     stock.ProductID,
+		WarehouseID:   stock.WarehouseID,
+		OnHand:        stock.OnHand,
+		Reserved:      stock.Reserved,
+		Reason:        reason,
+		ReservationID: reservationID,
+		Version:       stock.Version,
+		ChangedAt:     currentTime,
+	})
+	if err != nil || !wasAvailable || stock.Available() > 0 {
+		return err
+	}
+	return s.eventDispatcher.Dispatch(&model.StockDepleted{
+		ProductID:   stock.ProductID,
+		WarehouseID: stock.WarehouseID,
+		Version:     stock.Version,
+		DepletedAt:  currentTime,
+	})
+}
@@ -0,0 +1,220 @@
+package service_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"productservice/pkg/common/domain"
+	"productservice/pkg/stock/domain/model"
+	"productservice/pkg/stock/domain/service"
+)
+
+// --- Mocks ---
+type mockStockRepository struct {
+	stocks   map[model.StockKey]model.Stock
+	products map[uuid.UUID]bool
+}
+
+func newMockStockRepository(productIDs ...uuid.UUID) *mockStockRepository {
+	products := make(map[uuid.UUID]bool, len(productIDs))
+	for _, productID := range productIDs {
+		products[productID] = true
+	}
+	return &mockStockRepository{
+		stocks:   make(map[model.StockKey]model.Stock),
+		products: products,
+	}
+}
+
+func (m *mockStockRepository) Store(stock model.Stock) error {
+	m.stocks[model.StockKey{ProductID: stock.ProductID, WarehouseID: stock.WarehouseID}] = stock
+	return nil
+}
+
+func (m *mockStockRepository) Find(key model.StockKey) (*model.Stock, error) {
+	if stock, ok := m.stocks[key]; ok {
+		return &stock, nil
+	}
+	return &model.Stock{ProductID: key.ProductID, WarehouseID: key.WarehouseID}, nil
+}
+
+func (m *mockStockRepository) ProductExists(productID uuid.UUID) (bool, error) {
+	return m.products[productID], nil
+}
+
+type mockReservationRepository struct {
+	reservations map[uuid.UUID]model.Reservation
+}
+
+func (m *mockReservationRepository) NextID() (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+func (m *mockReservationRepository) Store(reservation model.Reservation) error {
+	m.reservations[reservation.ReservationID] = reservation
+	return nil
+}
+
+func (m *mockReservationRepository) Find(reservationID uuid.UUID) (*model.Reservation, error) {
+	if reservation, ok := m.reservations[reservationID]; ok {
+		return &reservation, nil
+	}
+	return nil, model.ErrReservationNotFound
+}
+
+func (m *mockReservationRepository) FindByReference(reference string) ([]uuid.UUID, error) {
+	var result []uuid.UUID
+	for _, reservation := range m.reservations {
+		if reservation.Reference == reference {
+			result = append(result, reservation.ReservationID)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockReservationRepository) FindExpired(at time.Time, limit int) ([]uuid.UUID, error) {
+	var result []uuid.UUID
+	for _, reservation := range m.reservations {
+		if reservation.Status == model.ReservationActive && !reservation.ExpiresAt.After(at) && len(result) < limit {
+			result = append(result, reservation.ReservationID)
+		}
+	}
+	return result, nil
+}
+
+type mockEventDispatcher struct {
+	dispatchedEvents []domain.Event
+}
+
+func (m *mockEventDispatcher) Dispatch(event domain.Event) error {
+	m.dispatchedEvents = append(m.dispatchedEvents, event)
+	return nil
+}
+
+func (m *mockEventDispatcher) eventTypes() []string {
+	result := make([]string, 0, len(m.dispatchedEvents))
+	for _, event := range m.dispatchedEvents {
+		result = append(result, event.Type())
+	}
+	return result
+}
+
+func newStockService(productIDs ...uuid.UUID) (service.StockService, *mockStockRepository, *mockReservationRepository, *mockEventDispatcher) {
+	stocks := newMockStockRepository(productIDs...)
+	reservations := &mockReservationRepository{reservations: make(map[uuid.UUID]model.Reservation)}
+	dispatcher := &mockEventDispatcher{}
+	return service.NewStockService(stocks, reservations, dispatcher), stocks, reservations, dispatcher
+}
+
+// --- Tests ---
+
+func TestStockService_SetAndAdjustStock(t *testing.T) {
+	// Arrange
+	productID := uuid.New()
+	stockService, stocks, _, dispatcher := newStockService(productID)
+	key := model.StockKey{ProductID: productID, WarehouseID: " MSK-1 "}
+	normalizedKey := model.StockKey{ProductID: productID, WarehouseID: "msk-1"}
+
+	// Act & Assert
+	require.NoError(t, stockService.SetStock(key, 10))
+	require.NoError(t, stockService.AdjustStock(key, -4))
+	assert.Equal(t, int64(6), stocks.stocks[normalizedKey].OnHand)
+	assert.Equal(t, int64(2), stocks.stocks[normalizedKey].Version)
+
+	err := stockService.AdjustStock(key, -7)
+	assert.ErrorIs(t, err, model.ErrInvalidQuantity)
+	err = stockService.SetStock(model.StockKey{ProductID: uuid.New(), WarehouseID: "msk-1"}, 1)
+	assert.ErrorIs(t, err, model.ErrProductNotFound)
+	err = stockService.SetStock(model.StockKey{ProductID: productID, WarehouseID: "msk 1"}, 1)
+	assert.ErrorIs(t, err, model.ErrInvalidWarehouseID)
+
+	require.NoError(t, stockService.AdjustStock(key, -6))
+	assert.Equal(t, []string{"stock_changed", "stock_changed", "stock_changed", "stock_depleted"}, dispatcher.eventTypes())
+}
+
+func TestStockService_Reserve(t *testing.T) {
+	// Arrange
+	tea, coffee := uuid.New(), uuid.New()
+	stockService, stocks, reservations, dispatcher := newStockService(tea, coffee)
+	require.NoError(t, stockService.SetStock(model.StockKey{ProductID: tea, WarehouseID: "msk"}, 5))
+	require.NoError(t, stockService.SetStock(model.StockKey{ProductID: coffee, WarehouseID: "msk"}, 1))
+	dispatcher.dispatchedEvents = nil
+
+	// Act
+	_, err := stockService.Reserve("order-1", []model.ReservationItem{
+		{ProductID: tea, WarehouseID: "msk", Quantity: 2},
+		{ProductID: coffee, WarehouseID: "msk", Quantity: 2},
+	}, time.Minute)
+	require.ErrorIs(t, err, model.ErrInsufficientStock)
+	reservationID, err := stockService.Reserve("order-1", []model.ReservationItem{
+		{ProductID: tea, WarehouseID: "msk", Quantity: 2},
+		{ProductID: coffee, WarehouseID: "MSK", Quantity: 1},
+		{ProductID: tea, WarehouseID: "msk", Quantity: 1},
+	}, time.Minute)
+
+	// Assert
+	require.NoError(t, err)
+	reservation := reservations.reservations[reservationID]
+	assert.Equal(t, model.ReservationActive, reservation.Status)
+	assert.Len(t, reservation.Items, 2)
+	assert.Equal(t, int64(3), stocks.stocks[model.StockKey{ProductID: tea, WarehouseID: "msk"}].Reserved)
+	assert.Equal(t, int64(2), stocks.stocks[model.StockKey{ProductID: tea, WarehouseID: "msk"}].Available())
+	assert.Equal(t, int64(0), stocks.stocks[model.StockKey{ProductID: coffee, WarehouseID: "msk"}].Available())
+	assert.ElementsMatch(t, []string{"stock_changed", "stock_changed", "stock_depleted"}, dispatcher.eventTypes())
+	depleted, ok := dispatcher.dispatchedEvents[slices.Index(dispatcher.eventTypes(), "stock_depleted")].(*model.StockDepleted)
+	require.True(t, ok)
+	assert.Equal(t, coffee, depleted.ProductID)
+
+	err = stockService.SetStock(model.StockKey{ProductID: coffee, WarehouseID: "msk"}, 0)
+	assert.ErrorIs(t, err, model.ErrStockBelowReserved)
+}
+
+func TestStockService_CommitAndRelease(t *testing.T) {
+	// Arrange
+	productID := uuid.New()
+	stockService, stocks, _, _ := newStockService(productID)
+	key := model.StockKey{ProductID: productID, WarehouseID: "msk"}
+	require.NoError(t, stockService.SetStock(key, 10))
+	items := []model.ReservationItem{{ProductID: productID, WarehouseID: "msk", Quantity: 4}}
+	committedID, err := stockService.Reserve("", items, time.Minute)
+	require.NoError(t, err)
+	releasedID, err := stockService.Reserve("", items, time.Minute)
+	require.NoError(t, err)
+
+	// Act & Assert
+	require.NoError(t, stockService.Commit(committedID))
+	require.NoError(t, stockService.Commit(committedID))
+	require.NoError(t, stockService.Release(releasedID))
+	require.NoError(t, stockService.Release(releasedID))
+	assert.Equal(t, int64(6), stocks.stocks[key].OnHand)
+	assert.Equal(t, int64(0), stocks.stocks[key].Reserved)
+
+	assert.ErrorIs(t, stockService.Release(committedID), model.ErrReservationNotActive)
+	assert.ErrorIs(t, stockService.Commit(releasedID), model.ErrReservationNotActive)
+	assert.ErrorIs(t, stockService.Commit(uuid.New()), model.ErrReservationNotFound)
+}
+
+func TestStockService_Expire(t *testing.T) {
+	// Arrange
+	productID := uuid.New()
+	stockService, stocks, reservations, _ := newStockService(productID)
+	key := model.StockKey{ProductID: productID, WarehouseID: "msk"}
+	require.NoError(t, stockService.SetStock(key, 3))
+	reservationID, err := stockService.Reserve("", []model.ReservationItem{{ProductID: productID, WarehouseID: "msk", Quantity: 3}}, time.Minute)
+	require.NoError(t, err)
+
+	// Act & Assert
+	require.NoError(t, stockService.Expire(reservationID, time.Now()))
+	assert.Equal(t, model.ReservationActive, reservations.reservations[reservationID].Status)
+
+	require.NoError(t, stockService.Expire(reservationID, time.Now().Add(time.Hour)))
+	assert.Equal(t, model.ReservationExpired, reservations.reservations[reservationID].Status)
+	assert.Equal(t, int64(0), stocks.stocks[key].Reserved)
+	assert.ErrorIs(t, stockService.Commit(reservationID), model.ErrReservationNotActive)
+	assert.NoError(t, stockService.Release(reservationID))
+}
@@ -0,0 +1,84 @@
+package integrationevent
+
+import (
+	"encoding/json"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/stock/domain/model"
+)
+
+// RoutingKeyPrefix события остатков публикуются в обменник событий продуктов с ключами
+// stock.stock_changed (StockChanged) и stock.stock_depleted (StockDepleted)
+const RoutingKeyPrefix = "stock."
+
+// RoutingKey возвращает ключ маршрутизации события остатков, ok false для других событий.
+// События остатков идут через outbox продуктов, транспорт продуктов публикует их с этими ключами
+func RoutingKey(eventType string) (string, bool) {
+	switch eventType {
+	case model.StockChanged{}.Type(), model.StockDepleted{}.Type():
+		return RoutingKeyPrefix + eventType, true
+	default:
+		return "", false
+	}
+}
+
+// NewEventSerializer сериализует события остатков, события отправляются через транспорт продуктов
+func NewEventSerializer() outbox.EventSerializer[outbox.Event] {
+	return &eventSerializer{}
+}
+
+type eventSerializer struct{}
+
+func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
+	switch e := event.(type) {
+	case *model.StockChanged:
+		ie := StockChanged{
+			ProductID:   e.ProductID.String(),
+			WarehouseID: e.WarehouseID,
+			OnHand:      e.OnHand,
+			Reserved:    e.Reserved,
+			Available:   e.OnHand - e.Reserved,
+			Reason:      string(e.Reason),
+			Version:     e.Version,
+			ChangedAt:   e.ChangedAt.Unix(),
+		}
+		if e.ReservationID != nil {
+			reservationID := e.ReservationID.String()
+			ie.ReservationID = &reservationID
+		}
+		b, err := json.Marshal(ie)
+		return string(b), errors.WithStack(err)
+	case *model.StockDepleted:
+		b, err := json.Marshal(StockDepleted{
+			ProductID:   e.ProductID.String(),
+			WarehouseID: e.WarehouseID,
+			Version:     e.Version,
+			DepletedAt:  e.DepletedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	default:
+		return "", errors.Errorf("unknown event %q", event.Type())
+	}
+}
+
+// StockChanged reservation_id отсутствует у изменений не резервами
+type StockChanged struct {
+	ProductID     string  `json:"product_id"`
+	WarehouseID   string  `json:"warehouse_id"`
+	OnHand        int64   `json:"on_hand"`
+	Reserved      int64   `json:"reserved"`
+	Available     int64   `json:"available"`
+	Reason        string  `json:"reason"`
+	ReservationID *string `json:"reservation_id,omitempty"`
+	Version       int64   `json:"version"`
+	ChangedAt     int64   `json:"changed_at"`
+}
+
+type StockDepleted struct {
+	ProductID   string `json:"product_id"`
+	WarehouseID string `json:"warehouse_id"`
+	Version     int64  `json:"version"`
+	DepletedAt  int64  `json:"depleted_at"`
+}
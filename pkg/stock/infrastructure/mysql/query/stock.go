@@ -0,0 +1,118 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "productservice/pkg/stock/application/model"
+	"productservice/pkg/stock/application/query"
+	"productservice/pkg/stock/domain/model"
+)
+
+func NewStockQueryService(client mysql.ClientContext) query.StockQueryService {
+	return &stockQueryService{
+		client: client,
+	}
+}
+
+type stockQueryService struct {
+	client mysql.ClientContext
+}
+
+func (q *stockQueryService) GetProductStock(ctx context.Context, productID uuid.UUID) (appmodel.ProductStock, error) {
+	var stockDTOs []struct {
+		WarehouseID string    `db:"warehouse_id"`
+		OnHand      int64     `db:"on_hand"`
+		Reserved    int64     `db:"reserved"`
+		Version     int64     `db:"version"`
+		UpdatedAt   time.Time `db:"updated_at"`
+	}
+	err := q.client.SelectContext(
+		ctx,
+		&stockDTOs,
+		`SELECT warehouse_id, on_hand, reserved, version, updated_at FROM stock WHERE product_id = ? ORDER BY warehouse_id`,
+		productID,
+	)
+	if err != nil {
+		return appmodel.ProductStock{}, errors.WithStack(err)
+	}
+
+	stock := appmodel.ProductStock{
+		ProductID:  productID,
+		Warehouses: make([]appmodel.StockLevel, 0, len(stockDTOs)),
+	}
+	for _, dto := range stockDTOs {
+		stock.Warehouses = append(stock.Warehouses, appmodel.StockLevel{
+			WarehouseID: dto.WarehouseID,
+			OnHand:      dto.OnHand,
+			Reserved:    dto.Reserved,
+			Available:   dto.OnHand - dto.Reserved,
+			Version:     dto.Version,
+			UpdatedAt:   dto.UpdatedAt,
+		})
+		stock.OnHand += dto.OnHand
+		stock.Reserved += dto.Reserved
+	}
+	stock.Available = stock.OnHand - stock.Reserved
+	return stock, nil
+}
+
+func (q *stockQueryService) FindReservation(ctx context.Context, reservationID uuid.UUID) (*appmodel.Reservation, error) {
+	reservationDTO := struct {
+		Reference sql.NullString `db:"reference"`
+		Status    string         `db:"status"`
+		ExpiresAt time.Time      `db:"expires_at"`
+		CreatedAt time.Time      `db:"created_at"`
+		UpdatedAt time.Time      `db:"updated_at"`
+	}{}
+	err := q.client.GetContext(
+		ctx,
+		&reservationDTO,
+		`SELECT reference, status, expires_at, created_at, updated_at FROM stock_reservation WHERE reservation_id = ?`,
+		reservationID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrReservationNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	var itemDTOs []struct {
+		ProductID   uuid.UUID `db:"product_id"`
+		WarehouseID string    `db:"warehouse_id"`
+		Quantity    int64     `db:"quantity"`
+	}
+	err = q.client.SelectContext(
+		ctx,
+		&itemDTOs,
+		`SELECT product_id, warehouse_id, quantity FROM stock_reservation_item WHERE reservation_id = ? ORDER BY product_id, warehouse_id`,
+		reservationID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	items := make([]appmodel.ReservationItem, 0, len(itemDTOs))
+	for _, dto := range itemDTOs {
+		items = append(items, appmodel.ReservationItem{
+			ProductID:   dto.ProductID,
+			WarehouseID: dto.WarehouseID,
+			Quantity:    dto.Quantity,
+		})
+	}
+
+	return &appmodel.Reservation{
+		ReservationID: reservationID,
+		Reference:     reservationDTO.Reference.String,
+		Items:         items,
+		Status:        reservationDTO.Status,
+		ExpiresAt:     reservationDTO.ExpiresAt,
+		CreatedAt:     reservationDTO.CreatedAt,
+		UpdatedAt:     reservationDTO.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/stock/domain/model"
+)
+
+func NewReservationRepository(ctx context.Context, client mysql.ClientContext) model.ReservationRepository {
+	return &reservationRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type reservationRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *reservationRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *reservationRepository) Store(reservation model.Reservation) error {
+	_, err := r.client.ExecContext(r.ctx,
+		`
+	INSERT INTO stock_reservation (reservation_id, reference, status, expires_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		status=VALUES(status),
+		expires_at=VALUES(expires_at),
+		updated_at=VALUES(updated_at)
+	`,
+		reservation.ReservationID,
+		sql.NullString{String: reservation.Reference, Valid: reservation.Reference != ""},
+		reservation.Status.String(),
+		reservation.ExpiresAt,
+		reservation.CreatedAt,
+		reservation.UpdatedAt,
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return r.storeItems(reservation.ReservationID, reservation.Items)
+}
+
+// Find блокирует строку резерва до конца транзакции, позиции резерва не меняются и читаются без блокировки
+func (r *reservationRepository) Find(reservationID uuid.UUID) (*model.Reservation, error) {
+	reservationDTO := struct {
+		ReservationID uuid.UUID      `db:"reservation_id"`
+		Reference     sql.NullString `db:"reference"`
+		Status        string         `db:"status"`
+		ExpiresAt     time.Time      `db:"expires_at"`
+		CreatedAt     time.Time      `db:"created_at"`
+		UpdatedAt     time.Time      `db:"updated_at"`
+	}{}
+	err := r.client.GetContext(
+		r.ctx,
+		&reservationDTO,
+		`SELECT reservation_id, reference, status, expires_at, created_at, updated_at FROM stock_reservation WHERE reservation_id = ? FOR UPDATE`,
+		reservationID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrReservationNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	status, err := model.ParseReservationStatus(reservationDTO.Status)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	items, err := r.findItems(reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Reservation{
+		ReservationID: reservationDTO.ReservationID,
+		Reference:     reservationDTO.Reference.String,
+		Items:         items,
+		Status:        status,
+		ExpiresAt:     reservationDTO.ExpiresAt,
+		CreatedAt:     reservationDTO.CreatedAt,
+		UpdatedAt:     reservationDTO.UpdatedAt,
+	}, nil
+}
+
+// FindByReference блокирует записи reference_idx, поэтому ждет транзакцию, которая создает резерв с тем же reference,
+// и видит ее резерв после фиксации, даже если блокировка заказа уже снята
+func (r *reservationRepository) FindByReference(reference string) ([]uuid.UUID, error) {
+	var reservationIDs []uuid.UUID
+	err := r.client.SelectContext(
+		r.ctx,
+		&reservationIDs,
+		`SELECT reservation_id FROM stock_reservation WHERE reference = ? ORDER BY created_at, reservation_id FOR UPDATE`,
+		reference,
+	)
+	return reservationIDs, errors.WithStack(err)
+}
+
+func (r *reservationRepository) FindExpired(at time.Time, limit int) ([]uuid.UUID, error) {
+	var reservationIDs []uuid.UUID
+	err := r.client.SelectContext(
+		r.ctx,
+		&reservationIDs,
+		`SELECT reservation_id FROM stock_reservation WHERE status = ? AND expires_at <= ? ORDER BY expires_at LIMIT ?`,
+		model.ReservationActive.String(),
+		at,
+		limit,
+	)
+	return reservationIDs, errors.WithStack(err)
+}
+
+// storeItems записывает позиции нового резерва, позиции резерва после создания не меняются
+func (r *reservationRepository) storeItems(reservationID uuid.UUID, items []model.ReservationItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	placeholders := make([]string, 0, len(items))
+	args := make([]interface{}, 0, len(items)*4)
+	for _, item := range items {
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		args = append(args, reservationID, item.ProductID, item.WarehouseID, item.Quantity)
+	}
+	_, err := r.client.ExecContext(
+		r.ctx,
+		`INSERT IGNORE INTO stock_reservation_item (reservation_id, product_id, warehouse_id, quantity) VALUES `+
+			strings.Join(placeholders, ", "),
+		args...,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *reservationRepository) findItems(reservationID uuid.UUID) ([]model.ReservationItem, error) {
+	var itemDTOs []struct {
+		ProductID   uuid.UUID `db:"product_id"`
+		WarehouseID string    `db:"warehouse_id"`
+		Quantity    int64     `db:"quantity"`
+	}
+	err := r.client.SelectContext(
+		r.ctx,
+		&itemDTOs,
+		`SELECT product_id, warehouse_id, quantity FROM stock_reservation_item WHERE reservation_id = ? ORDER BY product_id, warehouse_id`,
+		reservationID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	items := make([]model.ReservationItem, 0, len(itemDTOs))
+	for _, itemDTO := range itemDTOs {
+		items = append(items, model.ReservationItem{
+			ProductID:   itemDTO.ProductID,
+			WarehouseID: itemDTO.WarehouseID,
+			Quantity:    itemDTO.Quantity,
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"productservice/pkg/stock/domain/model"
+)
+
+func NewStockRepository(ctx context.Context, client mysql.ClientContext) model.StockRepository {
+	return &stockRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type stockRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (s *stockRepository) Store(stock model.Stock) error {
+	_, err := s.client.ExecContext(s.ctx,
+		`
+	INSERT INTO stock (product_id, warehouse_id, on_hand, reserved, version, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		on_hand=VALUES(on_hand),
+		reserved=VALUES(reserved),
+		version=VALUES(version),
+		updated_at=VALUES(updated_at)
+	`,
+		stock.ProductID,
+		stock.WarehouseID,
+		stock.OnHand,
+		stock.Reserved,
+		stock.Version,
+		stock.UpdatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+// Find блокирует строку остатка до конца транзакции и читает ее последнюю версию,
+// даже если снимок транзакции был сделан до именованных блокировок
+func (s *stockRepository) Find(key model.StockKey) (*model.Stock, error) {
+	stockDTO := struct {
+		OnHand    int64     `db:"on_hand"`
+		Reserved  int64     `db:"reserved"`
+		Version   int64     `db:"version"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}{}
+	err := s.client.GetContext(
+		s.ctx,
+		&stockDTO,
+		`SELECT on_hand, reserved, version, updated_at FROM stock WHERE product_id = ? AND warehouse_id = ? FOR UPDATE`,
+		key.ProductID,
+		key.WarehouseID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.Stock{
+				ProductID:   key.ProductID,
+				WarehouseID: key.WarehouseID,
+			}, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	return &model.Stock{
+		ProductID:   key.ProductID,
+		WarehouseID: key.WarehouseID,
+		OnHand:      stockDTO.OnHand,
+		Reserved:    stockDTO.Reserved,
+		Version:     stockDTO.Version,
+		UpdatedAt:   stockDTO.UpdatedAt,
+	}, nil
+}
+
+func (s *stockRepository) ProductExists(productID uuid.UUID) (bool, error) {
+	var exists bool
+	err := s.client.GetContext(
+		s.ctx,
+		&exists,
+		`SELECT EXISTS(SELECT 1 FROM product WHERE product_id = ? AND deleted_at IS NULL)`,
+		productID,
+	)
+	return exists, errors.WithStack(err)
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+
+	"productservice/pkg/stock/application/service"
+	"productservice/pkg/stock/domain/model"
+	"productservice/pkg/stock/infrastructure/mysql/repository"
+)
+
+func NewRepositoryProvider(client mysql.ClientContext) service.RepositoryProvider {
+	return &repositoryProvider{client: client}
+}
+
+type repositoryProvider struct {
+	client mysql.ClientContext
+}
+
+func (r *repositoryProvider) StockRepository(ctx context.Context) model.StockRepository {
+	return repository.NewStockRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) ReservationRepository(ctx context.Context) model.ReservationRepository {
+	return repository.NewReservationRepository(ctx, r.client)
+}
@@ -0,0 +1,138 @@
+package orderevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	"github.com/google/uuid"
+
+	"productservice/pkg/common/infrastructure/consumer"
+	appmodel "productservice/pkg/stock/application/model"
+	"productservice/pkg/stock/application/service"
+	"productservice/pkg/stock/domain/model"
+)
+
+const (
+	RoutingKeyOrderCreated   = "order.created"
+	RoutingKeyOrderCancelled = "order.cancelled"
+)
+
+// errMalformedMessage сообщение нельзя обработать, сколько его ни доставляй
+var errMalformedMessage = errors.New("malformed message")
+
+// NewHandler применяет события заказов к остаткам. Сообщения, которые нельзя применить
+// (неизвестный продукт, не хватает остатка, некорректное содержимое), логируются и возвращаются
+// с consumer.ErrRejected, чтобы их подтвердили, иначе они бесконечно возвращались бы в очередь.
+// Повторная доставка не отсекается, для этого обработчик оборачивается в inbox.NewHandler
+func NewHandler(orderService service.OrderService, logger logging.Logger) consumer.Handler {
+	h := &handler{
+		orderService: orderService,
+		logger:       logger,
+	}
+	return h.handle
+}
+
+type handler struct {
+	orderService service.OrderService
+	logger       logging.Logger
+}
+
+func (h *handler) handle(ctx context.Context, message consumer.Message) error {
+	l := h.logger.WithFields(logging.Fields{
+		"messageID":   message.ID,
+		"messageType": message.Type,
+	})
+
+	err := h.apply(ctx, message)
+	if isRejected(err) {
+		l.WithField("payload", string(message.Body)).Warning(err, "order event rejected")
+		return errors.Join(consumer.ErrRejected, err)
+	}
+	if err != nil {
+		return err
+	}
+	l.Info("order event handled")
+	return nil
+}
+
+func (h *handler) apply(ctx context.Context, message consumer.Message) error {
+	if message.ID == "" {
+		return errors.Join(errMalformedMessage, errors.New("message id is missing"))
+	}
+
+	switch message.Type {
+	case RoutingKeyOrderCreated:
+		var event OrderCreated
+		err := json.Unmarshal(message.Body, &event)
+		if err != nil {
+			return errors.Join(errMalformedMessage, err)
+		}
+		order := appmodel.Order{
+			OrderID: event.OrderID,
+			Items:   make([]appmodel.ReservationItem, 0, len(event.Items)),
+		}
+		for _, item := range event.Items {
+			productID, err := uuid.Parse(item.ProductID)
+			if err != nil {
+				return errors.Join(errMalformedMessage, err)
+			}
+			order.Items = append(order.Items, appmodel.ReservationItem{
+				ProductID:   productID,
+				WarehouseID: item.WarehouseID,
+				Quantity:    item.Quantity,
+			})
+		}
+		if order.OrderID == "" {
+			return errors.Join(errMalformedMessage, errors.New("order_id is missing"))
+		}
+		return h.orderService.ReserveOrder(ctx, order)
+	case RoutingKeyOrderCancelled:
+		var event OrderCancelled
+		err := json.Unmarshal(message.Body, &event)
+		if err != nil {
+			return errors.Join(errMalformedMessage, err)
+		}
+		if event.OrderID == "" {
+			return errors.Join(errMalformedMessage, errors.New("order_id is missing"))
+		}
+		return h.orderService.ReleaseOrder(ctx, event.OrderID)
+	default:
+		return errors.Join(errMalformedMessage, errors.New("unknown message type"))
+	}
+}
+
+var rejectedErrors = []error{
+	errMalformedMessage,
+	model.ErrProductNotFound,
+	model.ErrInsufficientStock,
+	model.ErrReservationNotActive,
+	model.ErrInvalidWarehouseID,
+	model.ErrInvalidQuantity,
+	model.ErrInvalidReservation,
+}
+
+func isRejected(err error) bool {
+	for _, target := range rejectedErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type OrderCreated struct {
+	OrderID string      `json:"order_id"`
+	Items   []OrderItem `json:"items"`
+}
+
+type OrderItem struct {
+	ProductID   string `json:"product_id"`
+	WarehouseID string `json:"warehouse_id"`
+	Quantity    int64  `json:"quantity"`
+}
+
+type OrderCancelled struct {
+	OrderID string `json:"order_id"`
+}
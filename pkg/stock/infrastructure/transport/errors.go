@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	commontransport "productservice/pkg/common/infrastructure/transport"
+	"productservice/pkg/stock/domain/model"
+)
+
+// ErrorMappings gRPC коды и причины ошибок модуля остатков
+var ErrorMappings = []commontransport.ErrorMapping{
+	{Target: model.ErrProductNotFound, Code: codes.NotFound, Reason: "PRODUCT_NOT_FOUND"},
+	{Target: model.ErrReservationNotFound, Code: codes.NotFound, Reason: "STOCK_RESERVATION_NOT_FOUND"},
+	{Target: model.ErrInsufficientStock, Code: codes.FailedPrecondition, Reason: "INSUFFICIENT_STOCK"},
+	{Target: model.ErrStockBelowReserved, Code: codes.FailedPrecondition, Reason: "STOCK_BELOW_RESERVED"},
+	{Target: model.ErrReservationNotActive, Code: codes.FailedPrecondition, Reason: "STOCK_RESERVATION_NOT_ACTIVE"},
+	{Target: model.ErrInvalidWarehouseID, Code: codes.InvalidArgument, Reason: "INVALID_WAREHOUSE_ID"},
+	{Target: model.ErrInvalidQuantity, Code: codes.InvalidArgument, Reason: "INVALID_STOCK_QUANTITY"},
+	{Target: model.ErrInvalidReservation, Code: codes.InvalidArgument, Reason: "INVALID_STOCK_RESERVATION"},
+}
+
+func parseUUID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, invalidArgument(field, err.Error())
+	}
+	return id, nil
+}
+
+func invalidArgument(field, description string) error {
+	st, err := status.New(codes.InvalidArgument, "invalid "+field).WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       field,
+				Description: description,
+			},
+		},
+	})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid "+field)
+	}
+	return st.Err()
+}
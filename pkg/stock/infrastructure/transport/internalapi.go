@@ -0,0 +1,177 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"productservice/api/server/stockinternal"
+	appmodel "productservice/pkg/stock/application/model"
+	"productservice/pkg/stock/application/query"
+	"productservice/pkg/stock/application/service"
+)
+
+func NewStockInternalAPI(
+	stockQueryService query.StockQueryService,
+	stockService service.StockService,
+) stockinternal.StockInternalServiceServer {
+	return &stockInternalAPI{
+		stockQueryService: stockQueryService,
+		stockService:      stockService,
+	}
+}
+
+type stockInternalAPI struct {
+	stockQueryService query.StockQueryService
+	stockService      service.StockService
+
+	stockinternal.UnimplementedStockInternalServiceServer
+}
+
+func (s *stockInternalAPI) SetStock(ctx context.Context, request *stockinternal.SetStockRequest) (*stockinternal.SetStockResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.stockService.SetStock(ctx, productID, request.WarehouseID, request.OnHand)
+	if err != nil {
+		return nil, err
+	}
+	return &stockinternal.SetStockResponse{}, nil
+}
+
+func (s *stockInternalAPI) AdjustStock(ctx context.Context, request *stockinternal.AdjustStockRequest) (*stockinternal.AdjustStockResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.stockService.AdjustStock(ctx, productID, request.WarehouseID, request.Delta)
+	if err != nil {
+		return nil, err
+	}
+	return &stockinternal.AdjustStockResponse{}, nil
+}
+
+func (s *stockInternalAPI) GetStock(ctx context.Context, request *stockinternal.GetStockRequest) (*stockinternal.GetStockResponse, error) {
+	productID, err := parseUUID("productID", request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	stock, err := s.stockQueryService.GetProductStock(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &stockinternal.GetStockResponse{
+		ProductID:  stock.ProductID.String(),
+		Warehouses: make([]*stockinternal.StockLevel, 0, len(stock.Warehouses)),
+		OnHand:     stock.OnHand,
+		Reserved:   stock.Reserved,
+		Available:  stock.Available,
+	}
+	for _, level := range stock.Warehouses {
+		response.Warehouses = append(response.Warehouses, &stockinternal.StockLevel{
+			WarehouseID: level.WarehouseID,
+			OnHand:      level.OnHand,
+			Reserved:    level.Reserved,
+			Available:   level.Available,
+			Version:     level.Version,
+			UpdatedAt:   level.UpdatedAt.Unix(),
+		})
+	}
+	return response, nil
+}
+
+func (s *stockInternalAPI) ReserveStock(ctx context.Context, request *stockinternal.ReserveStockRequest) (*stockinternal.ReserveStockResponse, error) {
+	items := make([]appmodel.ReservationItem, 0, len(request.Items))
+	for _, item := range request.Items {
+		productID, err := parseUUID("items.productID", item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, appmodel.ReservationItem{
+			ProductID:   productID,
+			WarehouseID: item.WarehouseID,
+			Quantity:    item.Quantity,
+		})
+	}
+	var ttl time.Duration
+	if request.TtlSeconds != nil {
+		if *request.TtlSeconds <= 0 {
+			return nil, invalidArgument("ttlSeconds", "must be positive")
+		}
+		ttl = time.Duration(*request.TtlSeconds) * time.Second
+	}
+
+	reservationID, err := s.stockService.ReserveStock(ctx, request.Reference, items, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &stockinternal.ReserveStockResponse{
+		ReservationID: reservationID.String(),
+	}, nil
+}
+
+func (s *stockInternalAPI) CommitReservation(ctx context.Context, request *stockinternal.CommitReservationRequest) (*stockinternal.CommitReservationResponse, error) {
+	reservationID, err := parseUUID("reservationID", request.ReservationID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.stockService.CommitReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	return &stockinternal.CommitReservationResponse{}, nil
+}
+
+func (s *stockInternalAPI) ReleaseReservation(ctx context.Context, request *stockinternal.ReleaseReservationRequest) (*stockinternal.ReleaseReservationResponse, error) {
+	reservationID, err := parseUUID("reservationID", request.ReservationID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.stockService.ReleaseReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	return &stockinternal.ReleaseReservationResponse{}, nil
+}
+
+func (s *stockInternalAPI) FindReservation(ctx context.Context, request *stockinternal.FindReservationRequest) (*stockinternal.FindReservationResponse, error) {
+	reservationID, err := parseUUID("reservationID", request.ReservationID)
+	if err != nil {
+		return nil, err
+	}
+	reservation, err := s.stockQueryService.FindReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	return &stockinternal.FindReservationResponse{
+		Reservation: toReservationProto(*reservation),
+	}, nil
+}
+
+func toReservationProto(reservation appmodel.Reservation) *stockinternal.Reservation {
+	items := make([]*stockinternal.ReservationItem, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		items = append(items, &stockinternal.ReservationItem{
+			ProductID:   item.ProductID.String(),
+			WarehouseID: item.WarehouseID,
+			Quantity:    item.Quantity,
+		})
+	}
+	return &stockinternal.Reservation{
+		ReservationID: reservation.ReservationID.String(),
+		Reference:     reservation.Reference,
+		Items:         items,
+		Status:        reservationStatuses[reservation.Status],
+		ExpiresAt:     reservation.ExpiresAt.Unix(),
+		CreatedAt:     reservation.CreatedAt.Unix(),
+		UpdatedAt:     reservation.UpdatedAt.Unix(),
+	}
+}
+
+var reservationStatuses = map[string]stockinternal.ReservationStatus{
+	"active":    stockinternal.ReservationStatus_RESERVATION_STATUS_ACTIVE,
+	"committed": stockinternal.ReservationStatus_RESERVATION_STATUS_COMMITTED,
+	"released":  stockinternal.ReservationStatus_RESERVATION_STATUS_RELEASED,
+	"expired":   stockinternal.ReservationStatus_RESERVATION_STATUS_EXPIRED,
+}